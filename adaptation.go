@@ -0,0 +1,237 @@
+// Package mayfly - Parameter Adaptation
+//
+// Implements success-history based adaptation of the movement coefficients
+// A1, A2, A3, Beta, Dance and FL in the style of SHADE and jDE.
+//
+// References:
+// Tanabe, R., & Fukunaga, A. (2013). Success-history based parameter
+// adaptation for differential evolution. IEEE CEC 2013.
+// Brest, J., Greiner, S., Bošković, B., Mernik, M., & Žumer, V. (2006).
+// Self-adapting control parameters in differential evolution: A comparative
+// study on numerical benchmark problems. IEEE TEC, 10(6).
+//
+// With Config.AdaptiveParameters every move of a mayfly samples its own
+// coefficients. A random slot of a memory with AdaptationMemory entries is
+// chosen, and each coefficient is drawn log-normally around the slot value,
+// x·exp(0.3·N(0, 1)), limited to 10 times its configured value. A move is
+// successful if it lowers the cost of the mayfly, and it only credits the
+// coefficients of its movement rule (A3 and Beta for the attraction of a
+// female, FL for her random flight, A1, A2 and Beta for a male, Dance for the
+// nuptial dance). After the moves of an iteration each coefficient with
+// successes in the next memory slot (round robin) is replaced by the
+// improvement-weighted geometric mean of its successful samples.
+//
+// The memory starts at the configured A1, A2, A3, Beta, Dance and FL. Dance
+// and FL of every slot are still multiplied by DanceDamp and FLDamp once per
+// iteration, so the schedule of the static algorithm is kept and adaptation
+// corrects its level. Result.Parameters holds the mean of the memory after
+// every iteration.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+)
+
+// defaultAdaptationMemory is used when Config.AdaptationMemory is zero.
+const defaultAdaptationMemory = 5
+
+// Sampling of adapted coefficients.
+const (
+	adaptationSpread    = 0.3  // Standard deviation of the log-normal sample
+	adaptationMaxFactor = 10.0 // Largest coefficient relative to its configured value
+)
+
+// Movement rules, with the coefficients each of them uses.
+const (
+	ruleAttraction = iota // Female towards male: A3, Beta
+	ruleFlight            // Female random flight: FL
+	ruleFollow            // Male towards personal and global best: A1, A2, Beta
+	ruleDance             // Nuptial dance of the best male: Dance
+)
+
+// ruleCoefficients marks the coefficients (in MovementCoefficients.vector
+// order) that each movement rule uses.
+var ruleCoefficients = [4][6]bool{
+	ruleAttraction: {false, false, true, true, false, false},
+	ruleFlight:     {false, false, false, false, false, true},
+	ruleFollow:     {true, true, false, true, false, false},
+	ruleDance:      {false, false, false, false, true, false},
+}
+
+// MovementCoefficients holds the coefficients of the Mayfly movement
+// equations.
+type MovementCoefficients struct {
+	A1    float64 // Personal learning coefficient
+	A2    float64 // Global learning coefficient
+	A3    float64 // Female attraction coefficient
+	Beta  float64 // Visibility coefficient
+	Dance float64 // Nuptial dance step
+	FL    float64 // Random flight step
+}
+
+// vector returns the coefficients in a fixed order.
+func (c MovementCoefficients) vector() [6]float64 {
+	return [6]float64{c.A1, c.A2, c.A3, c.Beta, c.Dance, c.FL}
+}
+
+// coefficientsOf is the inverse of MovementCoefficients.vector.
+func coefficientsOf(v [6]float64) MovementCoefficients {
+	return MovementCoefficients{A1: v[0], A2: v[1], A3: v[2], Beta: v[3], Dance: v[4], FL: v[5]}
+}
+
+// validateAdaptationConfig checks the parameter adaptation parameters.
+func validateAdaptationConfig(config *Config) error {
+	if config.AdaptationMemory < 0 {
+		return fmt.Errorf("AdaptationMemory must be non-negative, got %d", config.AdaptationMemory)
+	}
+
+	// EOBBMA and AOBLMOA replace the velocity update, so they would never
+	// sample the coefficients
+	if config.AdaptiveParameters && (config.UseEOBBMA || config.UseAOBLMOA) {
+		return fmt.Errorf("AdaptiveParameters cannot be combined with EOBBMA or AOBLMOA")
+	}
+
+	return nil
+}
+
+// coefficients returns the movement coefficients for the next move of
+// mayfly with the given rule: the configured A1, A2, A3 and Beta with the
+// damped Dance and FL, or in adaptive mode a sample that is recorded for
+// the memory update.
+func (s *State) coefficients(mayfly *Mayfly, rule int) MovementCoefficients {
+	if s.adaptation == nil {
+		config := s.Config
+
+		return MovementCoefficients{
+			A1:    config.A1,
+			A2:    config.A2,
+			A3:    config.A3,
+			Beta:  config.Beta,
+			Dance: s.Dance,
+			FL:    s.FL,
+		}
+	}
+
+	return s.adaptation.sample(s, mayfly, rule)
+}
+
+// adaptationSample is a coefficient sample used for one move.
+type adaptationSample struct {
+	mayfly *Mayfly
+	cost   float64 // Cost before the move
+	rule   int
+	values [6]float64
+}
+
+// adaptationController holds the success-history memory of a run.
+type adaptationController struct {
+	memory  [][6]float64
+	upper   [6]float64
+	damping [6]float64 // Per-iteration factors (DanceDamp and FLDamp)
+	next    int        // Slot replaced by the next update
+	pending []adaptationSample
+	trace   []MovementCoefficients
+}
+
+// newAdaptationController creates a memory initialized with the configured
+// coefficients.
+func newAdaptationController(config *Config) *adaptationController {
+	size := config.AdaptationMemory
+	if size == 0 {
+		size = defaultAdaptationMemory
+	}
+
+	initial := MovementCoefficients{
+		A1:    config.A1,
+		A2:    config.A2,
+		A3:    config.A3,
+		Beta:  config.Beta,
+		Dance: config.Dance,
+		FL:    config.FL,
+	}.vector()
+
+	a := &adaptationController{
+		memory: make([][6]float64, size),
+		trace:  make([]MovementCoefficients, 0, config.MaxIterations),
+	}
+
+	for k := range a.memory {
+		a.memory[k] = initial
+	}
+
+	for i, v := range initial {
+		a.upper[i] = adaptationMaxFactor * v
+	}
+
+	a.damping = MovementCoefficients{A1: 1, A2: 1, A3: 1, Beta: 1, Dance: config.DanceDamp, FL: config.FLDamp}.vector()
+
+	return a
+}
+
+// sample draws the coefficients of one move of mayfly around a random
+// memory slot and records them.
+func (a *adaptationController) sample(s *State, mayfly *Mayfly, rule int) MovementCoefficients {
+	slot := a.memory[s.Rand.Intn(len(a.memory))]
+
+	var values [6]float64
+	for i, m := range slot {
+		values[i] = math.Min(m*math.Exp(adaptationSpread*s.Rand.NormFloat64()), a.upper[i])
+	}
+
+	a.pending = append(a.pending, adaptationSample{mayfly: mayfly, cost: mayfly.Cost, rule: rule, values: values})
+
+	return coefficientsOf(values)
+}
+
+// update replaces every coefficient of the next memory slot by the
+// improvement-weighted mean, in log space, of its successful samples, damps
+// Dance and FL, and records the memory mean in the trace. It must be called once the moves of
+// an iteration have been evaluated.
+func (a *adaptationController) update() {
+	var sum, total [6]float64
+
+	for _, p := range a.pending {
+		improvement := p.cost - p.mayfly.Cost
+		if !(improvement > 0) || math.IsInf(improvement, 0) {
+			continue
+		}
+
+		for i, v := range p.values {
+			if ruleCoefficients[p.rule][i] && v > 0 {
+				sum[i] += improvement * math.Log(v)
+				total[i] += improvement
+			}
+		}
+	}
+
+	a.pending = a.pending[:0]
+
+	updated := false
+
+	for i := range sum {
+		if total[i] > 0 {
+			a.memory[a.next][i] = math.Exp(sum[i] / total[i])
+			updated = true
+		}
+	}
+
+	if updated {
+		a.next = (a.next + 1) % len(a.memory)
+	}
+
+	for k := range a.memory {
+		for i, d := range a.damping {
+			a.memory[k][i] *= d
+		}
+	}
+
+	var mean [6]float64
+	for _, slot := range a.memory {
+		for i, v := range slot {
+			mean[i] += v / float64(len(a.memory))
+		}
+	}
+
+	a.trace = append(a.trace, coefficientsOf(mean))
+}
@@ -0,0 +1,201 @@
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for adaptation.go - success-history adaptation of the coefficients
+// =============================================================================
+
+func newAdaptationTestConfig(fn ObjectiveFunction, seed int64) *Config {
+	config := newTestConfig(fn, 10, 5, 200, seed)
+	config.AdaptiveParameters = true
+
+	return config
+}
+
+// detune sets movement coefficients far from their defaults.
+func detune(config *Config) {
+	config.A1, config.A2, config.A3 = 0.2, 0.2, 0.2
+	config.Beta = 10
+	config.Dance = 0.5
+	config.FL = 0.1
+}
+
+func TestAdaptationValidation(t *testing.T) {
+	config := newAdaptationTestConfig(Sphere, 1)
+	config.AdaptationMemory = -1
+
+	if _, err := Optimize(config); err == nil {
+		t.Error("Negative AdaptationMemory should be rejected")
+	}
+
+	if err := ValidateConfig(config); err == nil {
+		t.Error("ValidateConfig should reject a negative AdaptationMemory")
+	}
+
+	for name, base := range map[string]func() *Config{"EOBBMA": NewEOBBMAConfig, "AOBLMOA": NewAOBLMOAConfig} {
+		config := base()
+		config.ObjectiveFunc = Sphere
+		config.ProblemSize = 5
+		config.LowerBound, config.UpperBound = -5, 5
+		config.AdaptiveParameters = true
+
+		if _, err := Optimize(config); err == nil {
+			t.Errorf("%s should reject AdaptiveParameters", name)
+		}
+	}
+}
+
+func TestAdaptationTrace(t *testing.T) {
+	static, err := Optimize(newTestConfig(Sphere, 10, 5, 200, 1))
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if static.Parameters != nil {
+		t.Error("Expected no parameter trace without AdaptiveParameters")
+	}
+
+	config := newAdaptationTestConfig(Sphere, 1)
+	config.AdaptationMemory = 3
+
+	result, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if len(result.Parameters) != config.MaxIterations {
+		t.Fatalf("Expected %d trace entries, got %d", config.MaxIterations, len(result.Parameters))
+	}
+
+	initial := MovementCoefficients{A1: config.A1, A2: config.A2, A3: config.A3, Beta: config.Beta, Dance: config.Dance, FL: config.FL}.vector()
+
+	for i, p := range result.Parameters {
+		for k, v := range p.vector() {
+			if !(v > 0) || v > adaptationMaxFactor*initial[k] {
+				t.Fatalf("Coefficient %d at iteration %d is %g, outside (0, %g]", k, i, v, adaptationMaxFactor*initial[k])
+			}
+		}
+	}
+
+	again, err := Optimize(newAdaptationTestConfig(Sphere, 1))
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	reference, err := Optimize(newAdaptationTestConfig(Sphere, 1))
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if again.GlobalBest.Cost != reference.GlobalBest.Cost || again.Parameters[199] != reference.Parameters[199] {
+		t.Error("Seeded adaptive runs are not reproducible")
+	}
+}
+
+func TestAdaptationUpdate(t *testing.T) {
+	config := NewDefaultConfig()
+	config.AdaptationMemory = 2
+	config.DanceDamp, config.FLDamp = 1, 1
+
+	a := newAdaptationController(config)
+	s := &State{Config: config, Rand: rand.New(rand.NewSource(1))}
+
+	improved := &Mayfly{Cost: 10}
+	worse := &Mayfly{Cost: 10}
+
+	c := a.sample(s, improved, ruleFollow)
+	a.sample(s, worse, ruleFlight)
+
+	improved.Cost = 4
+	worse.Cost = 12
+
+	a.update()
+
+	slot := coefficientsOf(a.memory[0])
+	if slot.A1 != c.A1 || slot.A2 != c.A2 || slot.Beta != c.Beta {
+		t.Errorf("Expected slot 0 to take the successful sample %+v, got %+v", c, slot)
+	}
+
+	if slot.A3 != config.A3 || slot.Dance != config.Dance || slot.FL != config.FL {
+		t.Errorf("Coefficients unused by the rule changed: %+v", slot)
+	}
+
+	if a.memory[1] != (MovementCoefficients{A1: config.A1, A2: config.A2, A3: config.A3, Beta: config.Beta, Dance: config.Dance, FL: config.FL}.vector()) {
+		t.Error("Only the next slot should be updated")
+	}
+
+	if a.next != 1 || len(a.trace) != 1 || len(a.pending) != 0 {
+		t.Errorf("Unexpected controller state: next=%d trace=%d pending=%d", a.next, len(a.trace), len(a.pending))
+	}
+
+	// Without successes the memory keeps its values and slot
+	a.sample(s, worse, ruleAttraction)
+	a.update()
+
+	if a.next != 1 || coefficientsOf(a.memory[0]) != slot {
+		t.Error("An iteration without successes should not change the memory")
+	}
+}
+
+func TestAdaptationImprovesDetunedCoefficients(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		fn   ObjectiveFunction
+	}{
+		{"Sphere", Sphere},
+		{"Rosenbrock", Rosenbrock},
+	} {
+		var static, adaptive float64
+
+		for seed := int64(1); seed <= 3; seed++ {
+			config := newTestConfig(tt.fn, 10, 5, 200, seed)
+			detune(config)
+
+			result, err := Optimize(config)
+			if err != nil {
+				t.Fatalf("Optimize failed: %v", err)
+			}
+
+			static += result.GlobalBest.Cost
+
+			config = newAdaptationTestConfig(tt.fn, seed)
+			detune(config)
+
+			result, err = Optimize(config)
+			if err != nil {
+				t.Fatalf("Optimize failed: %v", err)
+			}
+
+			adaptive += result.GlobalBest.Cost
+		}
+
+		if adaptive >= static {
+			t.Errorf("%s: adaptation (%g) does not improve detuned coefficients (%g)", tt.name, adaptive/3, static/3)
+		}
+	}
+}
+
+func TestAdaptationMPMA(t *testing.T) {
+	config := NewMPMAConfig()
+	config.ObjectiveFunc = Sphere
+	config.ProblemSize = 10
+	config.LowerBound = -5
+	config.UpperBound = 5
+	config.MaxIterations = 100
+	config.AdaptiveParameters = true
+	config.Rand = rand.New(rand.NewSource(1))
+
+	result, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if len(result.Parameters) != 100 || math.IsNaN(result.GlobalBest.Cost) || result.GlobalBest.Cost > 1e-2 {
+		t.Errorf("MPMA with adaptation reached %g with %d trace entries", result.GlobalBest.Cost, len(result.Parameters))
+	}
+}
@@ -70,9 +70,18 @@ func applyAOBLMOA(mayfly *Mayfly, globalBest Best, population []*Mayfly,
 // 4. Updates positions and evaluates fitness.
 func applyAOBLMOAToPopulation(males, females []*Mayfly, globalBest Best,
 	currentIter, maxIter int, config *Config) {
-	// Update males with AOBLMOA
+	leader := func([]float64) Best { return globalBest }
+
+	applyAOBLMOAToMales(males, leader, currentIter, maxIter, config)
+	applyAOBLMOAToFemales(females, leader, currentIter, maxIter, config)
+}
+
+// applyAOBLMOAToMales updates the males with AOBLMOA and maintains their
+// personal bests. leader returns the best solution that a mayfly at the given
+// position hunts for.
+func applyAOBLMOAToMales(males []*Mayfly, leader func([]float64) Best, currentIter, maxIter int, config *Config) {
 	for i := 0; i < len(males); i++ {
-		newPos := applyAOBLMOA(males[i], globalBest, males, true, currentIter, maxIter, config)
+		newPos := applyAOBLMOA(males[i], leader(males[i].Position), males, true, currentIter, maxIter, config)
 
 		if newPos != nil {
 			// AOBLMOA provided a new position, use it
@@ -93,10 +102,13 @@ func applyAOBLMOAToPopulation(males, females []*Mayfly, globalBest Best,
 		}
 		// If nil, the standard Mayfly update will be used instead
 	}
+}
 
-	// Update females with AOBLMOA
+// applyAOBLMOAToFemales updates the females with AOBLMOA. leader is as in
+// applyAOBLMOAToMales.
+func applyAOBLMOAToFemales(females []*Mayfly, leader func([]float64) Best, currentIter, maxIter int, config *Config) {
 	for i := 0; i < len(females); i++ {
-		newPos := applyAOBLMOA(females[i], globalBest, females, false, currentIter, maxIter, config)
+		newPos := applyAOBLMOA(females[i], leader(females[i].Position), females, false, currentIter, maxIter, config)
 
 		if newPos != nil {
 			// AOBLMOA provided a new position, use it
@@ -145,72 +157,67 @@ func initializeAOBLMOA(config *Config) {
 	}
 }
 
-// ParetoArchive maintains a set of non-dominated solutions for multi-objective problems.
-type ParetoArchive struct {
-	Solutions []*ParetoSolution
-	MaxSize   int
-}
+// updateParetoArchive updates the Pareto archive with current population.
+// This is called at the end of each iteration to maintain the best solutions found.
+func updateParetoArchive(archive *ParetoArchive, males, females []*Mayfly) {
+	// Add all males
+	for _, m := range males {
+		archive.AddFromMayfly(m)
+	}
 
-// NewParetoArchive creates a new Pareto archive with specified maximum size.
-func NewParetoArchive(maxSize int) *ParetoArchive {
-	return &ParetoArchive{
-		Solutions: make([]*ParetoSolution, 0, maxSize),
-		MaxSize:   maxSize,
+	// Add all females
+	for _, f := range females {
+		archive.AddFromMayfly(f)
 	}
 }
 
-// Add adds a solution to the Pareto archive.
-// If the archive is full, it uses NSGA-II selection to maintain diversity.
-func (pa *ParetoArchive) Add(solution *ParetoSolution) {
-	// Add the new solution
-	pa.Solutions = append(pa.Solutions, solution)
+// aoblmoaMover replaces the velocity-based movement with the hybrid
+// Mayfly-Aquila update of AOBLMOA. In niching mode every mayfly hunts for
+// the nearest niche leader instead of the global best.
+type aoblmoaMover struct{}
 
-	// If archive exceeds max size, select best solutions
-	if len(pa.Solutions) > pa.MaxSize {
-		pa.Solutions = selectByNSGA2(pa.Solutions, pa.MaxSize)
-	}
+// MoveFemales applies AOBLMOA to the female population.
+func (m *aoblmoaMover) MoveFemales(s *State) {
+	applyAOBLMOAToFemales(s.Females, s.socialBest, s.Iteration, s.Config.MaxIterations, aoblmoaRunConfig(s))
 }
 
-// AddFromMayfly converts a Mayfly to a ParetoSolution and adds it to the archive.
-// For single-objective problems, the objective value is just the cost.
-func (pa *ParetoArchive) AddFromMayfly(mayfly *Mayfly) {
-	solution := &ParetoSolution{
-		Position:         make([]float64, len(mayfly.Position)),
-		ObjectiveValues:  []float64{mayfly.Cost},
-		Rank:             0,
-		CrowdingDistance: 0,
+// MoveMales applies AOBLMOA to the male population and updates the global best.
+func (m *aoblmoaMover) MoveMales(s *State) {
+	applyAOBLMOAToMales(s.Males, s.socialBest, s.Iteration, s.Config.MaxIterations, aoblmoaRunConfig(s))
+
+	for _, male := range s.Males {
+		s.UpdateGlobalBest(male.Position, male.Cost)
 	}
-	copy(solution.Position, mayfly.Position)
-	pa.Add(solution)
 }
 
-// GetBestSolution returns the solution with the lowest first objective value.
-// This is useful for single-objective optimization.
-func (pa *ParetoArchive) GetBestSolution() *ParetoSolution {
-	if len(pa.Solutions) == 0 {
-		return nil
-	}
+// aoblmoaRunConfig returns a copy of the run configuration whose objective
+// counts every evaluation in s and whose random source is the run's generator.
+func aoblmoaRunConfig(s *State) *Config {
+	config := *s.Config
+	config.ObjectiveFunc = s.Evaluate
+	config.Rand = s.Rand
 
-	best := pa.Solutions[0]
-	for _, sol := range pa.Solutions[1:] {
-		if sol.ObjectiveValues[0] < best.ObjectiveValues[0] {
-			best = sol
-		}
-	}
+	return &config
+}
 
-	return best
+// aoblmoaStage initializes AOBLMOA parameters and maintains the Pareto archive.
+type aoblmoaStage struct {
+	archive *ParetoArchive
 }
 
-// updateParetoArchive updates the Pareto archive with current population.
-// This is called at the end of each iteration to maintain the best solutions found.
-func updateParetoArchive(archive *ParetoArchive, males, females []*Mayfly) {
-	// Add all males
-	for _, m := range males {
-		archive.AddFromMayfly(m)
-	}
+func (a *aoblmoaStage) Name() string {
+	return "aoblmoa"
+}
 
-	// Add all females
-	for _, f := range females {
-		archive.AddFromMayfly(f)
-	}
+// Initialize sets AOBLMOA defaults and creates the Pareto archive.
+func (a *aoblmoaStage) Initialize(s *State) error {
+	initializeAOBLMOA(s.Config)
+	a.archive = NewParetoArchive(s.Config.ArchiveSize)
+
+	return nil
+}
+
+// PostSelect adds the surviving population to the Pareto archive.
+func (a *aoblmoaStage) PostSelect(s *State) {
+	updateParetoArchive(a.archive, s.Males, s.Females)
 }
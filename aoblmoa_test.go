@@ -533,7 +533,7 @@ func TestCalculateHypervolume(t *testing.T) {
 	// Reference point should be worse than all solutions
 	referencePoint := []float64{5.0, 5.0}
 
-	hypervolume := calculateHypervolume(solutions, referencePoint)
+	hypervolume := CalculateHypervolume(solutions, referencePoint)
 
 	// Hypervolume should be positive
 	if hypervolume <= 0 {
@@ -563,7 +563,7 @@ func TestCalculateHypervolumeEmpty(t *testing.T) {
 	solutions := []*ParetoSolution{}
 	referencePoint := []float64{5.0, 5.0}
 
-	hypervolume := calculateHypervolume(solutions, referencePoint)
+	hypervolume := CalculateHypervolume(solutions, referencePoint)
 
 	if hypervolume != 0 {
 		t.Errorf("Expected hypervolume of 0 for empty solution set, got %f", hypervolume)
@@ -586,7 +586,7 @@ func TestCalculateIGD(t *testing.T) {
 		{ObjectiveValues: []float64{1.0, 0.1}},
 	}
 
-	igd := calculateIGD(obtainedFront, trueFront)
+	igd := CalculateIGD(obtainedFront, trueFront)
 
 	// IGD should be small (close to true front)
 	if igd <= 0 || igd > 1.0 {
@@ -594,7 +594,7 @@ func TestCalculateIGD(t *testing.T) {
 	}
 
 	// Test with identical fronts - should give very small IGD
-	igd2 := calculateIGD(trueFront, trueFront)
+	igd2 := CalculateIGD(trueFront, trueFront)
 	if igd2 > 1e-10 {
 		t.Errorf("Expected near-zero IGD for identical fronts, got %f", igd2)
 	}
@@ -607,14 +607,14 @@ func TestCalculateIGDEmpty(t *testing.T) {
 	}
 	obtainedFront := []*ParetoSolution{}
 
-	igd := calculateIGD(obtainedFront, trueFront)
+	igd := CalculateIGD(obtainedFront, trueFront)
 
 	if !math.IsInf(igd, 1) {
 		t.Errorf("Expected infinite IGD for empty obtained front, got %f", igd)
 	}
 
 	// Empty true front
-	igd2 := calculateIGD(trueFront, []*ParetoSolution{})
+	igd2 := CalculateIGD(trueFront, []*ParetoSolution{})
 	if !math.IsInf(igd2, 1) {
 		t.Errorf("Expected infinite IGD for empty true front, got %f", igd2)
 	}
@@ -866,71 +866,45 @@ func TestApplyAOBLMOAToPopulation(t *testing.T) {
 	}
 }
 
-// Global Pareto front: f1 ∈ [0,1], f2 = 1 - sqrt(f1).
-func ZDT1(x []float64) []float64 {
-	n := len(x)
-	f1 := x[0]
-
-	g := 0.0
-	for i := 1; i < n; i++ {
-		g += x[i]
-	}
-
-	g = 1.0 + (9.0/float64(n-1))*g
-
-	h := 1.0 - math.Sqrt(f1/g)
-
-	f2 := g * h
-
-	return []float64{f1, f2}
-}
+// TestAOBLMOALeader tests that every mayfly hunts for the leader of its position.
+func TestAOBLMOALeader(t *testing.T) {
+	config := NewAOBLMOAConfig()
+	config.Rand = rand.New(rand.NewSource(42))
+	config.ObjectiveFunc = Sphere
+	config.ProblemSize = 2
+	config.LowerBound = -5.0
+	config.UpperBound = 5.0
+	config.MaxIterations = 100
+	config.AquilaWeight = 1
 
-// Non-convex Pareto front.
-func ZDT2(x []float64) []float64 {
-	n := len(x)
-	f1 := x[0]
+	initializeAOBLMOA(config)
 
-	g := 0.0
-	for i := 1; i < n; i++ {
-		g += x[i]
+	males := make([]*Mayfly, 4)
+	for i := range males {
+		males[i] = newMayfly(2)
+		males[i].Position = []float64{float64(i), float64(-i)}
+		males[i].Cost = Sphere(males[i].Position)
+		males[i].Best.Cost = math.Inf(1)
 	}
 
-	g = 1.0 + (9.0/float64(n-1))*g
-
-	h := 1.0 - math.Pow(f1/g, 2.0)
-
-	f2 := g * h
-
-	return []float64{f1, f2}
-}
+	var asked [][]float64
 
-// Spherical Pareto front.
-func DTLZ2(x []float64) []float64 {
-	// For simplicity, use 3 objectives
-	m := 3 // number of objectives
-
-	// Calculate g(xM)
-	g := 0.0
-	for i := m - 1; i < len(x); i++ {
-		g += math.Pow(x[i]-0.5, 2.0)
+	leader := func(position []float64) Best {
+		asked = append(asked, append([]float64(nil), position...))
+		return Best{Position: []float64{position[0], position[1]}, Cost: 0}
 	}
 
-	// Calculate objectives
-	objectives := make([]float64, m)
-
-	for i := 0; i < m; i++ {
-		objectives[i] = 1.0 + g
+	applyAOBLMOAToMales(males, leader, 10, 100, config)
 
-		for j := 0; j < m-i-1; j++ {
-			objectives[i] *= math.Cos(x[j] * math.Pi / 2.0)
-		}
+	if len(asked) != len(males) {
+		t.Fatalf("Expected one leader per male, got %d", len(asked))
+	}
 
-		if i > 0 {
-			objectives[i] *= math.Sin(x[m-i-1] * math.Pi / 2.0)
+	for i, position := range asked {
+		if position[0] != float64(i) || position[1] != float64(-i) {
+			t.Errorf("Leader of male %d requested for %v", i, position)
 		}
 	}
-
-	return objectives
 }
 
 // TestMultiObjectiveZDT1 tests AOBLMOA on ZDT1 multi-objective problem.
@@ -1010,7 +984,7 @@ func TestMultiObjectiveZDT1(t *testing.T) {
 
 	// Reference point should be slightly worse than worst point
 	referencePoint := []float64{maxF1 + 1.0, maxF2 + 1.0}
-	hv := calculateHypervolume(firstFrontSolutions, referencePoint)
+	hv := CalculateHypervolume(firstFrontSolutions, referencePoint)
 
 	// Hypervolume should be positive if reference point is valid
 	// If still zero, it might mean all solutions are identical or dominate reference
@@ -1089,7 +1063,7 @@ func TestMultiObjectiveDTLZ2(t *testing.T) {
 			x[j] = rng.Float64()
 		}
 
-		obj := DTLZ2(x)
+		obj := DTLZ2(3)(x)
 		solutions[i] = &ParetoSolution{
 			Position:        x,
 			ObjectiveValues: obj,
@@ -1119,8 +1093,6 @@ func TestMultiObjectiveDTLZ2(t *testing.T) {
 			}
 		}
 	}
-	// Note: Hypervolume calculation only supports 2D, so skip for DTLZ2
-	// IGD would require true Pareto front which we don't have
 }
 
 // TestMultiObjectiveArchiveManagement tests Pareto archive with multi-objective problems.
@@ -0,0 +1,619 @@
+// Package mayfly - Non-dominated archives
+//
+// Implements archive strategies for the non-dominated solutions of a
+// multi-objective search behind the Archive interface:
+//   - ParetoArchive: bounded by crowding distance (NSGA-II)
+//   - EpsilonArchive: epsilon-box dominance, bounded by the box size
+//     (Laumanns et al., 2002)
+//   - GridArchive: bounded by an adaptive grid that evicts from the most
+//     crowded cell (PAES, Knowles & Corne, 2000)
+//   - NDTreeArchive: unbounded, with sublinear updates (ND-Tree,
+//     Jaszkiewicz & Lust, 2018)
+//
+// All archives reject a dominated (or duplicate) solution as soon as it is
+// offered and remove the members that a new solution dominates, using
+// constrained domination: feasible solutions beat infeasible ones, and of
+// two infeasible solutions the one with the smaller ConstraintViolation
+// wins. They store the offered *ParetoSolution itself, not a copy.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Archive stores mutually non-dominated solutions.
+type Archive interface {
+	// Add offers solution to the archive and reports whether it was stored.
+	Add(solution *ParetoSolution) bool
+
+	// Front returns the stored solutions.
+	Front() []*ParetoSolution
+
+	// Len returns the number of stored solutions.
+	Len() int
+}
+
+// Sizes for an unset GridArchive.Divisions or NDTreeArchive.MaxLeafSize: 16
+// grid cells per objective, and leaves of 20 solutions as in the ND-Tree
+// paper.
+const (
+	defaultGridDivisions = 16
+	defaultNDTreeLeaf    = 20
+)
+
+// insertNonDominated adds candidate to the non-dominated set front unless a
+// member weakly constrained-dominates it, and removes the members it
+// constrained-dominates. It reports whether candidate was added.
+func insertNonDominated(front []*ParetoSolution, candidate *ParetoSolution) ([]*ParetoSolution, bool) {
+	for _, member := range front {
+		if solutionWeaklyDominates(member, candidate) {
+			return front, false
+		}
+	}
+
+	kept := front[:0]
+	for _, member := range front {
+		if !solutionDominates(candidate, member) {
+			kept = append(kept, member)
+		}
+	}
+
+	return append(kept, candidate), true
+}
+
+// ParetoArchive maintains a set of non-dominated solutions for multi-objective problems.
+type ParetoArchive struct {
+	Solutions []*ParetoSolution
+	MaxSize   int // Maximum number of solutions; 0 means unbounded
+}
+
+// NewParetoArchive creates a new Pareto archive with specified maximum size.
+func NewParetoArchive(maxSize int) *ParetoArchive {
+	return &ParetoArchive{
+		Solutions: make([]*ParetoSolution, 0, maxSize),
+		MaxSize:   maxSize,
+	}
+}
+
+// Add adds a solution to the Pareto archive unless it is dominated.
+// If the archive is full, the most crowded solution is removed.
+func (pa *ParetoArchive) Add(solution *ParetoSolution) bool {
+	var added bool
+
+	pa.Solutions, added = insertNonDominated(pa.Solutions, solution)
+	if !added {
+		return false
+	}
+
+	solution.Rank = 1
+
+	if pa.MaxSize > 0 && len(pa.Solutions) > pa.MaxSize {
+		pa.Solutions = truncateByCrowding(pa.Solutions, pa.MaxSize)
+
+		for _, member := range pa.Solutions {
+			if member == solution {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// Front returns the archived solutions.
+func (pa *ParetoArchive) Front() []*ParetoSolution {
+	return pa.Solutions
+}
+
+// Len returns the number of archived solutions.
+func (pa *ParetoArchive) Len() int {
+	return len(pa.Solutions)
+}
+
+// AddFromMayfly converts a Mayfly to a ParetoSolution and adds it to the archive.
+// For single-objective problems, the objective value is just the cost.
+func (pa *ParetoArchive) AddFromMayfly(mayfly *Mayfly) {
+	solution := &ParetoSolution{
+		Position:         make([]float64, len(mayfly.Position)),
+		ObjectiveValues:  []float64{mayfly.Cost},
+		Rank:             0,
+		CrowdingDistance: 0,
+	}
+	copy(solution.Position, mayfly.Position)
+	pa.Add(solution)
+}
+
+// GetBestSolution returns the solution with the lowest first objective value.
+// This is useful for single-objective optimization.
+func (pa *ParetoArchive) GetBestSolution() *ParetoSolution {
+	if len(pa.Solutions) == 0 {
+		return nil
+	}
+
+	best := pa.Solutions[0]
+	for _, sol := range pa.Solutions[1:] {
+		if sol.ObjectiveValues[0] < best.ObjectiveValues[0] {
+			best = sol
+		}
+	}
+
+	return best
+}
+
+// EpsilonArchive keeps at most one solution per epsilon box. Objective space
+// is divided into boxes of size Epsilon; a solution is rejected if its box
+// is constrained-dominated by the box of a member, and within a box the
+// solution closest to the lower box corner is kept. Every offered feasible
+// solution f is epsilon-dominated by a member a: a_j <= f_j + epsilon_j for
+// all j.
+type EpsilonArchive struct {
+	Epsilon []float64 // Box size per objective, or one value for all (> 0)
+
+	solutions []*ParetoSolution
+	boxes     [][]float64
+}
+
+// NewEpsilonArchive creates an epsilon-dominance archive with the given box
+// sizes: one per objective, or a single value for all objectives. It returns
+// an error if no box size is given or one is not positive and finite.
+func NewEpsilonArchive(epsilon ...float64) (*EpsilonArchive, error) {
+	if len(epsilon) == 0 {
+		return nil, fmt.Errorf("epsilon archive needs at least one box size")
+	}
+
+	for j, e := range epsilon {
+		if !(e > 0) || math.IsInf(e, 1) {
+			return nil, fmt.Errorf("epsilon %d must be positive and finite, got %v", j, e)
+		}
+	}
+
+	return &EpsilonArchive{Epsilon: epsilon}, nil
+}
+
+// Add adds solution unless its box is dominated or taken by a better solution.
+func (ea *EpsilonArchive) Add(solution *ParetoSolution) bool {
+	f, v := solution.ObjectiveValues, solution.ConstraintViolation
+	box := ea.box(f)
+
+	for i, member := range ea.solutions {
+		if !equalObjectives(ea.boxes[i], box) || member.ConstraintViolation != v {
+			if constrainedDominates(ea.boxes[i], member.ConstraintViolation, box, v) {
+				return false
+			}
+
+			continue
+		}
+
+		// Same box: keep the dominating solution, otherwise the one
+		// closer to the box corner
+		g := member.ObjectiveValues
+		if weaklyDominates(g, f) || (!dominates(f, g) && ea.cornerDistance(g, box) <= ea.cornerDistance(f, box)) {
+			return false
+		}
+
+		ea.solutions[i] = solution
+
+		return true
+	}
+
+	kept, keptBoxes := ea.solutions[:0], ea.boxes[:0]
+	for i, member := range ea.solutions {
+		if !constrainedDominates(box, v, ea.boxes[i], member.ConstraintViolation) {
+			kept = append(kept, member)
+			keptBoxes = append(keptBoxes, ea.boxes[i])
+		}
+	}
+
+	ea.solutions = append(kept, solution)
+	ea.boxes = append(keptBoxes, box)
+
+	return true
+}
+
+// Front returns the archived solutions.
+func (ea *EpsilonArchive) Front() []*ParetoSolution {
+	return ea.solutions
+}
+
+// Len returns the number of archived solutions.
+func (ea *EpsilonArchive) Len() int {
+	return len(ea.solutions)
+}
+
+// epsilon returns the box size of objective j.
+func (ea *EpsilonArchive) epsilon(j int) float64 {
+	if j < len(ea.Epsilon) {
+		return ea.Epsilon[j]
+	}
+
+	return ea.Epsilon[len(ea.Epsilon)-1]
+}
+
+// box returns the box index of f.
+func (ea *EpsilonArchive) box(f []float64) []float64 {
+	box := make([]float64, len(f))
+	for j, v := range f {
+		box[j] = math.Floor(v / ea.epsilon(j))
+	}
+
+	return box
+}
+
+// cornerDistance returns the distance of f from the lower corner of box.
+func (ea *EpsilonArchive) cornerDistance(f, box []float64) float64 {
+	sum := 0.0
+	for j, v := range f {
+		d := v - box[j]*ea.epsilon(j)
+		sum += d * d
+	}
+
+	return math.Sqrt(sum)
+}
+
+// GridArchive holds at most MaxSize non-dominated solutions. When it is
+// full, objective space between the extremes of the archive is divided into
+// Divisions cells per objective; a new solution replaces a member of the
+// most crowded cell if its own cell is less crowded or it extends the
+// archive's range, and is rejected otherwise.
+type GridArchive struct {
+	MaxSize   int // Maximum number of solutions; 0 means unbounded
+	Divisions int // Cells per objective (default 16)
+
+	solutions []*ParetoSolution
+}
+
+// NewGridArchive creates an adaptive grid archive of at most maxSize solutions.
+func NewGridArchive(maxSize, divisions int) *GridArchive {
+	return &GridArchive{MaxSize: maxSize, Divisions: divisions}
+}
+
+// Add adds solution unless it is dominated or lies in the most crowded cell
+// of a full archive.
+func (ga *GridArchive) Add(solution *ParetoSolution) bool {
+	var added bool
+
+	ga.solutions, added = insertNonDominated(ga.solutions, solution)
+	if !added || ga.MaxSize == 0 || len(ga.solutions) <= ga.MaxSize {
+		return added
+	}
+
+	members := ga.solutions[:len(ga.solutions)-1]
+	lower, upper := IdealPoint(members), NadirPoint(members)
+	extends := !weaklyDominates(lower, solution.ObjectiveValues) || !weaklyDominates(solution.ObjectiveValues, upper)
+
+	// The grid spans the archive including the new solution
+	lower, upper = IdealPoint(ga.solutions), NadirPoint(ga.solutions)
+
+	cells := make([]string, len(members))
+	counts := make(map[string]int)
+
+	crowded := ""
+	for i, member := range members {
+		cells[i] = ga.cell(member.ObjectiveValues, lower, upper)
+		counts[cells[i]]++
+
+		if counts[cells[i]] > counts[crowded] {
+			crowded = cells[i]
+		}
+	}
+
+	if !extends && counts[ga.cell(solution.ObjectiveValues, lower, upper)] >= counts[crowded] {
+		ga.solutions = members
+		return false
+	}
+
+	for i := range members {
+		if cells[i] == crowded {
+			ga.solutions = append(ga.solutions[:i], ga.solutions[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+// Front returns the archived solutions.
+func (ga *GridArchive) Front() []*ParetoSolution {
+	return ga.solutions
+}
+
+// Len returns the number of archived solutions.
+func (ga *GridArchive) Len() int {
+	return len(ga.solutions)
+}
+
+// cell returns the key of the grid cell that contains f.
+func (ga *GridArchive) cell(f, lower, upper []float64) string {
+	divisions := ga.Divisions
+	if divisions == 0 {
+		divisions = defaultGridDivisions
+	}
+
+	key := make([]byte, 0, 4*len(f))
+	for j, v := range f {
+		index := 0
+		if width := upper[j] - lower[j]; width > 0 {
+			index = int(float64(divisions) * (v - lower[j]) / width)
+			if index == divisions {
+				index--
+			}
+		}
+
+		key = strconv.AppendInt(key, int64(index), 10)
+		key = append(key, ',')
+	}
+
+	return string(key)
+}
+
+// NDTreeArchive holds all non-dominated solutions offered to it. The
+// solutions are stored in the leaves of a tree whose nodes know the ideal
+// and nadir points of their subtree, so that most subtrees are skipped or
+// accepted or rejected as a whole by comparing the new solution with these
+// two points.
+type NDTreeArchive struct {
+	MaxLeafSize int // Solutions per leaf before it is split (default 20)
+	Branching   int // Children of a split leaf (default: objectives + 1)
+
+	root      *ndNode
+	size      int
+	violation float64 // Constraint violation of all stored solutions
+}
+
+// ndNode is a node of an ND-Tree: a leaf with solutions or an inner node
+// with children. ideal and nadir bound the solutions of the subtree; they
+// are widened on insertion only, which keeps them valid bounds.
+type ndNode struct {
+	ideal, nadir []float64
+	solutions    []*ParetoSolution
+	children     []*ndNode
+}
+
+// NewNDTreeArchive creates an empty, unbounded ND-Tree archive.
+func NewNDTreeArchive() *NDTreeArchive {
+	return &NDTreeArchive{}
+}
+
+// Add adds solution unless it is dominated, removing the solutions it dominates.
+func (nt *NDTreeArchive) Add(solution *ParetoSolution) bool {
+	f, v := solution.ObjectiveValues, solution.ConstraintViolation
+
+	// Stored solutions do not constrained-dominate each other, so they share
+	// one violation, and the tree compares objectives only if v ties with it
+	switch {
+	case nt.size > 0 && v > nt.violation:
+		return false
+
+	case nt.size == 0 || v < nt.violation:
+		nt.root, nt.size, nt.violation = nil, 0, v
+
+	case v > 0:
+		// Infeasible solutions of equal violation are mutually non-dominated
+		for _, s := range nt.Front() {
+			if equalObjectives(s.ObjectiveValues, f) {
+				return false
+			}
+		}
+
+	case nt.update(nt.root, f):
+		return false
+	}
+
+	if nt.root == nil || nt.root.empty() {
+		nt.root = &ndNode{}
+	}
+
+	nt.insert(nt.root, solution)
+	nt.size++
+
+	return true
+}
+
+// Front returns the archived solutions.
+func (nt *NDTreeArchive) Front() []*ParetoSolution {
+	front := make([]*ParetoSolution, 0, nt.size)
+
+	var collect func(n *ndNode)
+	collect = func(n *ndNode) {
+		front = append(front, n.solutions...)
+		for _, child := range n.children {
+			collect(child)
+		}
+	}
+
+	if nt.root != nil {
+		collect(nt.root)
+	}
+
+	return front
+}
+
+// Len returns the number of archived solutions.
+func (nt *NDTreeArchive) Len() int {
+	return nt.size
+}
+
+// update removes the solutions of subtree n that f dominates. It reports
+// whether a solution of the subtree weakly dominates f.
+func (nt *NDTreeArchive) update(n *ndNode, f []float64) bool {
+	switch {
+	case weaklyDominates(n.nadir, f):
+		// Every solution of the subtree is at least as good as f
+		return true
+
+	case weaklyDominates(f, n.ideal):
+		// f dominates the whole subtree
+		nt.size -= n.count()
+		n.solutions, n.children = nil, nil
+
+		return false
+
+	case !weaklyDominates(n.ideal, f) && !weaklyDominates(f, n.nadir):
+		// f and the subtree are mutually non-dominated
+		return false
+	}
+
+	if n.children == nil {
+		kept := n.solutions[:0]
+		for _, s := range n.solutions {
+			if weaklyDominates(s.ObjectiveValues, f) {
+				return true
+			}
+
+			if !dominates(f, s.ObjectiveValues) {
+				kept = append(kept, s)
+			}
+		}
+
+		nt.size -= len(n.solutions) - len(kept)
+		n.solutions = kept
+
+		return false
+	}
+
+	kept := n.children[:0]
+	for _, child := range n.children {
+		if nt.update(child, f) {
+			return true
+		}
+
+		if !child.empty() {
+			kept = append(kept, child)
+		}
+	}
+
+	n.children = kept
+
+	return false
+}
+
+// insert adds solution to the leaf below n with the closest midpoint.
+func (nt *NDTreeArchive) insert(n *ndNode, solution *ParetoSolution) {
+	f := solution.ObjectiveValues
+
+	for {
+		n.widen(f)
+
+		if n.children == nil {
+			break
+		}
+
+		n = closestChild(n.children, f)
+	}
+
+	n.solutions = append(n.solutions, solution)
+
+	size := nt.MaxLeafSize
+	if size == 0 {
+		size = defaultNDTreeLeaf
+	}
+
+	if len(n.solutions) > size {
+		nt.split(n)
+	}
+}
+
+// split turns leaf n into an inner node. The children are seeded with the
+// solutions farthest apart, and the other solutions join the child with the
+// closest midpoint.
+func (nt *NDTreeArchive) split(n *ndNode) {
+	branching := nt.Branching
+	if branching == 0 {
+		branching = len(n.solutions[0].ObjectiveValues) + 1
+	}
+
+	branching = int(math.Max(2, math.Min(float64(branching), float64(len(n.solutions)))))
+
+	rest := n.solutions
+	n.solutions = nil
+
+	var seeds []*ParetoSolution
+
+	for len(seeds) < branching {
+		// The first seed is far from all solutions, later seeds far from the seeds
+		reference := seeds
+		if reference == nil {
+			reference = rest
+		}
+
+		farthest, distance := 0, -1.0
+		for i, s := range rest {
+			sum := 0.0
+			for _, r := range reference {
+				sum += euclideanDistance(s.ObjectiveValues, r.ObjectiveValues)
+			}
+
+			if sum > distance {
+				farthest, distance = i, sum
+			}
+		}
+
+		seeds = append(seeds, rest[farthest])
+		rest = append(rest[:farthest:farthest], rest[farthest+1:]...)
+	}
+
+	n.children = make([]*ndNode, len(seeds))
+	for i, s := range seeds {
+		n.children[i] = &ndNode{solutions: []*ParetoSolution{s}}
+		n.children[i].widen(s.ObjectiveValues)
+	}
+
+	for _, s := range rest {
+		child := closestChild(n.children, s.ObjectiveValues)
+		child.solutions = append(child.solutions, s)
+		child.widen(s.ObjectiveValues)
+	}
+}
+
+// closestChild returns the child whose midpoint is closest to f.
+func closestChild(children []*ndNode, f []float64) *ndNode {
+	best, distance := children[0], math.Inf(1)
+
+	for _, child := range children {
+		sum := 0.0
+		for j, v := range f {
+			d := v - 0.5*(child.ideal[j]+child.nadir[j])
+			sum += d * d
+		}
+
+		if sum < distance {
+			best, distance = child, sum
+		}
+	}
+
+	return best
+}
+
+// widen extends the bounds of n to include f.
+func (n *ndNode) widen(f []float64) {
+	if n.ideal == nil {
+		n.ideal = append([]float64(nil), f...)
+		n.nadir = append([]float64(nil), f...)
+
+		return
+	}
+
+	for j, v := range f {
+		n.ideal[j] = math.Min(n.ideal[j], v)
+		n.nadir[j] = math.Max(n.nadir[j], v)
+	}
+}
+
+// empty reports whether the subtree of n holds no solutions.
+func (n *ndNode) empty() bool {
+	return len(n.solutions) == 0 && len(n.children) == 0
+}
+
+// count returns the number of solutions in the subtree of n.
+func (n *ndNode) count() int {
+	total := len(n.solutions)
+	for _, child := range n.children {
+		total += child.count()
+	}
+
+	return total
+}
@@ -0,0 +1,298 @@
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for archive.go - Non-dominated archives
+// =============================================================================
+
+func newTestArchives() map[string]Archive {
+	return map[string]Archive{
+		"pareto":  NewParetoArchive(0),
+		"epsilon": &EpsilonArchive{Epsilon: []float64{1e-6}},
+		"grid":    NewGridArchive(0, 0),
+		"ndtree":  NewNDTreeArchive(),
+	}
+}
+
+// randomFrontPoint returns a point close to the positive unit sphere.
+func randomFrontPoint(rng *rand.Rand, objectives int) []float64 {
+	f := make([]float64, objectives)
+	for j := range f {
+		f[j] = math.Abs(rng.NormFloat64())
+	}
+
+	scale := (1 + 0.2*rng.Float64()) / norm(f)
+	for j := range f {
+		f[j] *= scale
+	}
+
+	return f
+}
+
+func checkMutuallyNonDominated(t *testing.T, front []*ParetoSolution) {
+	t.Helper()
+
+	for i, a := range front {
+		for k, b := range front {
+			if i != k && weaklyDominates(a.ObjectiveValues, b.ObjectiveValues) {
+				t.Fatalf("Archive contains %v, which %v weakly dominates", b.ObjectiveValues, a.ObjectiveValues)
+			}
+		}
+	}
+}
+
+func TestArchivesRejectDominated(t *testing.T) {
+	for name, archive := range newTestArchives() {
+		t.Run(name, func(t *testing.T) {
+			if !archive.Add(&ParetoSolution{ObjectiveValues: []float64{1, 1}}) {
+				t.Fatal("First solution should be stored")
+			}
+
+			if archive.Add(&ParetoSolution{ObjectiveValues: []float64{2, 2}}) {
+				t.Error("Dominated solution should be rejected")
+			}
+
+			if archive.Add(&ParetoSolution{ObjectiveValues: []float64{1, 1}}) {
+				t.Error("Duplicate solution should be rejected")
+			}
+
+			if !archive.Add(&ParetoSolution{ObjectiveValues: []float64{0, 3}}) {
+				t.Error("Non-dominated solution should be stored")
+			}
+
+			if !archive.Add(&ParetoSolution{ObjectiveValues: []float64{0.5, 0.5}}) {
+				t.Error("Dominating solution should be stored")
+			}
+
+			if archive.Len() != 2 || len(archive.Front()) != 2 {
+				t.Fatalf("Expected 2 solutions, got %d", archive.Len())
+			}
+
+			checkMutuallyNonDominated(t, archive.Front())
+		})
+	}
+}
+
+func TestArchivesConstrainedDomination(t *testing.T) {
+	for name, archive := range newTestArchives() {
+		t.Run(name, func(t *testing.T) {
+			if !archive.Add(&ParetoSolution{ObjectiveValues: []float64{0, 0}, ConstraintViolation: 2}) {
+				t.Fatal("First solution should be stored")
+			}
+
+			if archive.Add(&ParetoSolution{ObjectiveValues: []float64{-1, -1}, ConstraintViolation: 3}) {
+				t.Error("Solution with a larger violation should be rejected")
+			}
+
+			if !archive.Add(&ParetoSolution{ObjectiveValues: []float64{5, 5}, ConstraintViolation: 1}) {
+				t.Error("Solution with a smaller violation should be stored")
+			}
+
+			if archive.Add(&ParetoSolution{ObjectiveValues: []float64{5, 5}, ConstraintViolation: 1}) {
+				t.Error("Duplicate infeasible solution should be rejected")
+			}
+
+			if !archive.Add(&ParetoSolution{ObjectiveValues: []float64{9, 9}}) {
+				t.Error("Feasible solution should be stored")
+			}
+
+			if archive.Add(&ParetoSolution{ObjectiveValues: []float64{-2, -2}, ConstraintViolation: 0.1}) {
+				t.Error("Infeasible solution should be rejected by a feasible one")
+			}
+
+			if !archive.Add(&ParetoSolution{ObjectiveValues: []float64{8, 10}}) {
+				t.Error("Non-dominated feasible solution should be stored")
+			}
+
+			if archive.Len() != 2 {
+				t.Fatalf("Expected 2 solutions, got %d", archive.Len())
+			}
+
+			for _, s := range archive.Front() {
+				if !s.Feasible() {
+					t.Errorf("Infeasible solution %v is stored", s.ObjectiveValues)
+				}
+			}
+		})
+	}
+}
+
+func TestNDTreeArchiveMatchesBruteForce(t *testing.T) {
+	for _, objectives := range []int{2, 3, 5} {
+		rng := rand.New(rand.NewSource(int64(objectives)))
+
+		tree := NewNDTreeArchive()
+		tree.MaxLeafSize = 4
+
+		var reference []*ParetoSolution
+
+		for i := 0; i < 3000; i++ {
+			solution := &ParetoSolution{ObjectiveValues: randomFrontPoint(rng, objectives)}
+
+			var added bool
+
+			reference, added = insertNonDominated(reference, solution)
+			if tree.Add(solution) != added {
+				t.Fatalf("%d objectives: tree and brute force disagree on solution %d", objectives, i)
+			}
+		}
+
+		front := tree.Front()
+		if tree.Len() != len(reference) || len(front) != len(reference) {
+			t.Fatalf("%d objectives: tree holds %d (Len %d), expected %d", objectives, len(front), tree.Len(), len(reference))
+		}
+
+		stored := make(map[*ParetoSolution]bool)
+		for _, s := range front {
+			stored[s] = true
+		}
+
+		for _, s := range reference {
+			if !stored[s] {
+				t.Fatalf("%d objectives: tree misses %v", objectives, s.ObjectiveValues)
+			}
+		}
+	}
+}
+
+func TestEpsilonArchive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	archive, err := NewEpsilonArchive(0.05, 0.1)
+	if err != nil {
+		t.Fatalf("NewEpsilonArchive failed: %v", err)
+	}
+
+	var offered [][]float64
+
+	for i := 0; i < 2000; i++ {
+		f := randomFrontPoint(rng, 2)
+		offered = append(offered, f)
+		archive.Add(&ParetoSolution{ObjectiveValues: f})
+	}
+
+	front := archive.Front()
+	checkMutuallyNonDominated(t, front)
+
+	// The box size bounds the archive: at most one member per box column
+	if len(front) > int(1.2/0.05)+1 {
+		t.Errorf("Archive holds %d solutions", len(front))
+	}
+
+	boxes := make(map[[2]float64]bool)
+	for _, s := range front {
+		box := archive.box(s.ObjectiveValues)
+		if boxes[[2]float64{box[0], box[1]}] {
+			t.Fatalf("Two solutions share box %v", box)
+		}
+
+		boxes[[2]float64{box[0], box[1]}] = true
+	}
+
+	// Every offered point is epsilon-dominated by a member
+	for _, f := range offered {
+		covered := false
+		for _, s := range front {
+			if s.ObjectiveValues[0] <= f[0]+0.05 && s.ObjectiveValues[1] <= f[1]+0.1 {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			t.Fatalf("Offered point %v is not epsilon-dominated by the archive", f)
+		}
+	}
+}
+
+func TestEpsilonArchiveValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		epsilon []float64
+	}{
+		{"no box size", nil},
+		{"zero", []float64{0.1, 0}},
+		{"negative", []float64{-0.1}},
+		{"NaN", []float64{math.NaN()}},
+		{"infinite", []float64{math.Inf(1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if archive, err := NewEpsilonArchive(tt.epsilon...); err == nil || archive != nil {
+				t.Errorf("Expected an error for %v", tt.epsilon)
+			}
+		})
+	}
+}
+
+func TestGridArchive(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	archive := NewGridArchive(20, 8)
+
+	// A linear front, sampled mostly near one end
+	for i := 0; i < 1000; i++ {
+		x := rng.Float64()
+		if i%4 != 0 {
+			x *= 0.1
+		}
+
+		archive.Add(&ParetoSolution{ObjectiveValues: []float64{x, 1 - x}})
+	}
+
+	front := archive.Front()
+	if len(front) != 20 {
+		t.Fatalf("Expected a full archive of 20, got %d", len(front))
+	}
+
+	checkMutuallyNonDominated(t, front)
+
+	// The grid keeps the archive spread over the whole front
+	covered := make(map[int]bool)
+	for _, s := range front {
+		covered[int(math.Min(s.ObjectiveValues[0]*4, 3))] = true
+	}
+
+	if len(covered) != 4 {
+		t.Errorf("Archive covers only %d of 4 quarters of the front", len(covered))
+	}
+}
+
+func TestParetoArchiveBounded(t *testing.T) {
+	archive := NewParetoArchive(10)
+
+	for i := 0; i <= 100; i++ {
+		x := float64(i) / 100
+		archive.Add(&ParetoSolution{ObjectiveValues: []float64{x, 1 - x}})
+	}
+
+	if archive.Len() != 10 {
+		t.Fatalf("Expected 10 solutions, got %d", archive.Len())
+	}
+
+	// Crowding truncation keeps the extremes
+	best := archive.GetBestSolution()
+	if best.ObjectiveValues[0] != 0 {
+		t.Errorf("Extreme solution was removed, best is %v", best.ObjectiveValues)
+	}
+}
+
+func BenchmarkArchiveAdd(b *testing.B) {
+	for name := range newTestArchives() {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				rng := rand.New(rand.NewSource(1))
+				archive := newTestArchives()[name]
+
+				for k := 0; k < 2000; k++ {
+					archive.Add(&ParetoSolution{ObjectiveValues: randomFrontPoint(rng, 3)})
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,471 @@
+// Package mayfly - Baseline optimizers
+//
+// Implements classic metaheuristics so that the Mayfly variants can be
+// compared against them with ComparisonRunner:
+//   - OptimizePSO: particle swarm optimization with constriction coefficients
+//   - OptimizeDE: differential evolution DE/rand/1/bin
+//   - OptimizeJADE: adaptive differential evolution DE/current-to-pbest/1
+//     with an external archive
+//   - OptimizeGA: generational real-coded genetic algorithm with tournament
+//     selection, SBX crossover, polynomial mutation and elitism
+//   - OptimizeRandomSearch: uniform random sampling, as a sanity baseline
+//
+// References:
+// Kennedy, J., & Eberhart, R. (1995). Particle swarm optimization. ICNN'95.
+// Clerc, M., & Kennedy, J. (2002). The particle swarm - explosion, stability,
+// and convergence in a multidimensional complex space. IEEE TEC, 6(1).
+// Storn, R., & Price, K. (1997). Differential evolution - a simple and
+// efficient heuristic for global optimization over continuous spaces.
+// Journal of Global Optimization, 11(4).
+// Zhang, J., & Sanderson, A. C. (2009). JADE: Adaptive differential evolution
+// with optional external archive. IEEE TEC, 13(5).
+// Deb, K., & Agrawal, R. B. (1995). Simulated binary crossover for continuous
+// search space. Complex Systems, 9(2).
+//
+// The baselines use the Config and Result types of the Mayfly variants and
+// the evaluation budget of OptimizeCMAES: an iteration is NPop + NPopF + NC
+// + NM evaluations, and Result.BestSolution holds the best cost after every
+// iteration. The population size is NPop + NPopF, the size of the Mayfly
+// population. DE uses DEScaleFactor and DECrossoverRate, the GA uses
+// TournamentSize, CrossoverEta, Mu and MutationEta, and PSO limits the
+// velocity to VelMax.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// PSO constriction coefficients of Clerc & Kennedy (2002).
+const (
+	psoInertia   = 0.7298
+	psoCognitive = 1.49618
+	psoSocial    = 1.49618
+)
+
+// JADE parameters of Zhang & Sanderson (2009).
+const (
+	jadeGreediness = 0.05 // Fraction p of the population the pbest is drawn from
+	jadeLearning   = 0.1  // Adaptation rate c of μF and μCR
+	jadeInitial    = 0.5  // Initial μF and μCR
+)
+
+// gaCrossoverRate is the probability that a pair of GA parents is recombined.
+const gaCrossoverRate = 0.9
+
+// baselineRun evaluates the candidates of a baseline optimizer within the
+// budget of MaxIterations Mayfly iterations and records the best cost after
+// every iteration budget.
+type baselineRun struct {
+	config       *Config
+	rng          *rand.Rand
+	seed         int64
+	budget       int
+	perIteration int
+	evals        int
+	history      []float64
+	best         Best
+}
+
+// newBaselineRun validates config and prepares a run of a baseline
+// optimizer that needs a population of at least minPopulation. restarts
+// tells whether the optimizer implements RestartStrategy.
+func newBaselineRun(config *Config, minPopulation int, restarts bool) (*baselineRun, error) {
+	if err := validateBaselineConfig(config, restarts); err != nil {
+		return nil, err
+	}
+
+	if config.NPop+config.NPopF < minPopulation {
+		return nil, fmt.Errorf("NPop + NPopF must be at least %d, got %d", minPopulation, config.NPop+config.NPopF)
+	}
+
+	rng, seed := prepareRun(config)
+	perIteration := config.NPop + config.NPopF + config.NC + config.NM
+
+	return &baselineRun{
+		config:       config,
+		rng:          rng,
+		seed:         seed,
+		budget:       config.MaxIterations * perIteration,
+		perIteration: perIteration,
+		history:      make([]float64, config.MaxIterations),
+		best:         Best{Position: make([]float64, config.ProblemSize), Cost: math.Inf(1)},
+	}, nil
+}
+
+// validateBaselineConfig checks config like Optimize and rejects the
+// options that only the Mayfly main loop implements, including
+// RestartStrategy unless restarts is set.
+func validateBaselineConfig(config *Config, restarts bool) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+
+	if option := mayflyOnlyOption(config); option != "" {
+		return fmt.Errorf("%s is only supported by Optimize", option)
+	}
+
+	if nichingEnabled(config) {
+		return fmt.Errorf("niching is only supported by Optimize")
+	}
+
+	if !restarts && restartsEnabled(config) {
+		return fmt.Errorf("restarts are only supported by Optimize and OptimizeCMAES")
+	}
+
+	return validateOptimizeConfig(config)
+}
+
+// exhausted reports whether the evaluation budget is used up.
+func (b *baselineRun) exhausted() bool {
+	return b.evals >= b.budget
+}
+
+// evaluate computes the cost of x, updates the best solution and records
+// the convergence history after every iteration budget.
+func (b *baselineRun) evaluate(x []float64) float64 {
+	cost := sanitizeCost(b.config.ObjectiveFunc(x))
+	b.evals++
+
+	if cost < b.best.Cost {
+		b.best.Cost = cost
+		copy(b.best.Position, x)
+	}
+
+	if b.evals%b.perIteration == 0 {
+		b.history[b.evals/b.perIteration-1] = b.best.Cost
+	}
+
+	return cost
+}
+
+// result returns the result of the run. The history of an unused budget
+// repeats the final best cost.
+func (b *baselineRun) result() *Result {
+	for i := b.evals / b.perIteration; i < len(b.history); i++ {
+		b.history[i] = b.best.Cost
+	}
+
+	return &Result{
+		GlobalBest:     b.best,
+		BestSolution:   b.history,
+		FuncEvalCount:  b.evals,
+		IterationCount: b.config.MaxIterations,
+		Seed:           b.seed,
+	}
+}
+
+// initPopulation evaluates uniformly random positions until the population
+// has size members or the budget is used up.
+func (b *baselineRun) initPopulation(size int) []*Mayfly {
+	config := b.config
+	population := make([]*Mayfly, 0, size)
+
+	for len(population) < size && !b.exhausted() {
+		m := newMayfly(config.ProblemSize)
+		m.Position = unifrndVec(config.LowerBound, config.UpperBound, config.ProblemSize, b.rng)
+		m.Cost = b.evaluate(m.Position)
+		m.Best.Cost = m.Cost
+		copy(m.Best.Position, m.Position)
+		population = append(population, m)
+	}
+
+	return population
+}
+
+// OptimizePSO minimizes config.ObjectiveFunc with a global-best particle
+// swarm. Each particle is a Mayfly whose Best is its personal best.
+func OptimizePSO(config *Config) (*Result, error) {
+	b, err := newBaselineRun(config, 2, false)
+	if err != nil {
+		return nil, err
+	}
+
+	swarm := b.initPopulation(config.NPop + config.NPopF)
+
+	for _, p := range swarm {
+		p.Velocity = unifrndVec(config.VelMin, config.VelMax, config.ProblemSize, b.rng)
+	}
+
+	for !b.exhausted() {
+		for _, p := range swarm {
+			if b.exhausted() {
+				break
+			}
+
+			for j := range p.Position {
+				p.Velocity[j] = psoInertia*p.Velocity[j] +
+					psoCognitive*b.rng.Float64()*(p.Best.Position[j]-p.Position[j]) +
+					psoSocial*b.rng.Float64()*(b.best.Position[j]-p.Position[j])
+			}
+
+			clampVec(p.Velocity, config.VelMin, config.VelMax)
+
+			for j := range p.Position {
+				p.Position[j] += p.Velocity[j]
+			}
+
+			clampVec(p.Position, config.LowerBound, config.UpperBound)
+
+			p.Cost = b.evaluate(p.Position)
+			if p.Cost < p.Best.Cost {
+				p.Best.Cost = p.Cost
+				copy(p.Best.Position, p.Position)
+			}
+		}
+	}
+
+	return b.result(), nil
+}
+
+// OptimizeDE minimizes config.ObjectiveFunc with DE/rand/1/bin. Trial
+// vectors replace their targets if they are at least as good.
+func OptimizeDE(config *Config) (*Result, error) {
+	b, err := newBaselineRun(config, 4, false)
+	if err != nil {
+		return nil, err
+	}
+
+	f := orDefault(config.DEScaleFactor, defaultDEScaleFactor)
+	cr := orDefault(config.DECrossoverRate, defaultDECrossoverRate)
+	population := b.initPopulation(config.NPop + config.NPopF)
+
+	for !b.exhausted() {
+		for i, target := range population {
+			if b.exhausted() {
+				break
+			}
+
+			r := distinctIndices(len(population), i, 3, b.rng)
+			trial := CrossoverDE(target.Position, population[r[0]].Position, population[r[1]].Position,
+				population[r[2]].Position, f, cr, config.LowerBound, config.UpperBound, b.rng)
+
+			if cost := b.evaluate(trial); cost <= target.Cost {
+				target.Position = trial
+				target.Cost = cost
+			}
+		}
+	}
+
+	return b.result(), nil
+}
+
+// OptimizeJADE minimizes config.ObjectiveFunc with JADE. Every trial vector
+// draws its scale factor from Cauchy(μF, 0.1) and its crossover rate from
+// N(μCR, 0.1); μF and μCR move towards the Lehmer and arithmetic means of
+// the successful values. Replaced targets enter an archive of the
+// population size that supplies the second difference vector.
+func OptimizeJADE(config *Config) (*Result, error) {
+	b, err := newBaselineRun(config, 4, false)
+	if err != nil {
+		return nil, err
+	}
+
+	n := config.ProblemSize
+	population := b.initPopulation(config.NPop + config.NPopF)
+	size := len(population)
+	pBest := int(math.Max(2, math.Ceil(jadeGreediness*float64(size))))
+	archive := make([][]float64, 0, size)
+	muF, muCR := jadeInitial, jadeInitial
+	order := make([]int, size)
+
+	for !b.exhausted() {
+		for i := range order {
+			order[i] = i
+		}
+
+		sort.SliceStable(order, func(a, c int) bool { return population[order[a]].Cost < population[order[c]].Cost })
+
+		var successF, successCR []float64
+
+		for i, target := range population {
+			if b.exhausted() {
+				break
+			}
+
+			f := cauchyRand(muF, 0.1, b.rng)
+			for f <= 0 {
+				f = cauchyRand(muF, 0.1, b.rng)
+			}
+
+			f = math.Min(f, 1)
+			cr := math.Max(0, math.Min(1, muCR+0.1*b.rng.NormFloat64()))
+
+			best := population[order[b.rng.Intn(pBest)]].Position
+			r1 := distinctIndices(size, i, 1, b.rng)[0]
+
+			// The second difference vector comes from the population or the archive
+			var x2 []float64
+			for x2 == nil {
+				r2 := b.rng.Intn(size + len(archive))
+				if r2 >= size {
+					x2 = archive[r2-size]
+				} else if r2 != i && r2 != r1 {
+					x2 = population[r2].Position
+				}
+			}
+
+			trial := make([]float64, n)
+			jRand := b.rng.Intn(n)
+
+			for j := range trial {
+				if j != jRand && b.rng.Float64() >= cr {
+					trial[j] = target.Position[j]
+					continue
+				}
+
+				trial[j] = target.Position[j] + f*(best[j]-target.Position[j]) +
+					f*(population[r1].Position[j]-x2[j])
+
+				// Bounce back halfway between the parent and the violated bound
+				if trial[j] < config.LowerBound {
+					trial[j] = (config.LowerBound + target.Position[j]) / 2
+				} else if trial[j] > config.UpperBound {
+					trial[j] = (config.UpperBound + target.Position[j]) / 2
+				}
+			}
+
+			cost := b.evaluate(trial)
+			if cost > target.Cost {
+				continue
+			}
+
+			if cost < target.Cost {
+				successF = append(successF, f)
+				successCR = append(successCR, cr)
+
+				if len(archive) < size {
+					archive = append(archive, target.Position)
+				} else {
+					archive[b.rng.Intn(size)] = target.Position
+				}
+			}
+
+			target.Position = trial
+			target.Cost = cost
+		}
+
+		if len(successF) > 0 {
+			sumF, sumSquaresF, sumCR := 0.0, 0.0, 0.0
+
+			for k, f := range successF {
+				sumF += f
+				sumSquaresF += f * f
+				sumCR += successCR[k]
+			}
+
+			muF = (1-jadeLearning)*muF + jadeLearning*sumSquaresF/sumF
+			muCR = (1-jadeLearning)*muCR + jadeLearning*sumCR/float64(len(successCR))
+		}
+	}
+
+	return b.result(), nil
+}
+
+// OptimizeGA minimizes config.ObjectiveFunc with a generational real-coded
+// genetic algorithm. The best member of each generation survives in place
+// of the worst offspring.
+func OptimizeGA(config *Config) (*Result, error) {
+	b, err := newBaselineRun(config, 2, false)
+	if err != nil {
+		return nil, err
+	}
+
+	eta := orDefault(config.CrossoverEta, defaultCrossoverEta)
+	mutationEta := orDefault(config.MutationEta, defaultMutationEta)
+	size := tournamentSize(config)
+	population := b.initPopulation(config.NPop + config.NPopF)
+
+	for !b.exhausted() {
+		elite := population[0]
+		for _, m := range population {
+			if m.Cost < elite.Cost {
+				elite = m
+			}
+		}
+
+		offspring := make([]*Mayfly, 0, len(population))
+
+		for len(offspring) < len(population) && !b.exhausted() {
+			p1 := tournament(population, size, b.rng).Position
+			p2 := tournament(population, size, b.rng).Position
+
+			c1, c2 := append([]float64(nil), p1...), append([]float64(nil), p2...)
+			if b.rng.Float64() < gaCrossoverRate {
+				c1, c2 = CrossoverSBX(p1, p2, eta, config.LowerBound, config.UpperBound, b.rng)
+			}
+
+			for _, c := range [][]float64{c1, c2} {
+				if len(offspring) == len(population) || b.exhausted() {
+					break
+				}
+
+				child := newMayfly(config.ProblemSize)
+				child.Position = MutatePolynomial(c, config.Mu, mutationEta, config.LowerBound, config.UpperBound, b.rng)
+				child.Cost = b.evaluate(child.Position)
+				offspring = append(offspring, child)
+			}
+		}
+
+		// A generation cut short by the budget keeps the old population
+		if len(offspring) < len(population) {
+			break
+		}
+
+		worst := 0
+		for i, m := range offspring {
+			if m.Cost > offspring[worst].Cost {
+				worst = i
+			}
+		}
+
+		if elite.Cost < offspring[worst].Cost {
+			offspring[worst] = elite
+		}
+
+		population = offspring
+	}
+
+	return b.result(), nil
+}
+
+// OptimizeRandomSearch samples uniformly random positions until the budget
+// is used up.
+func OptimizeRandomSearch(config *Config) (*Result, error) {
+	b, err := newBaselineRun(config, 0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for !b.exhausted() {
+		b.evaluate(unifrndVec(config.LowerBound, config.UpperBound, config.ProblemSize, b.rng))
+	}
+
+	return b.result(), nil
+}
+
+// distinctIndices draws count distinct indices from [0, n) that differ
+// from exclude.
+func distinctIndices(n, exclude, count int, rng *rand.Rand) []int {
+	indices := make([]int, 0, count)
+
+	for len(indices) < count {
+		r := rng.Intn(n)
+		if r == exclude {
+			continue
+		}
+
+		duplicate := false
+		for _, k := range indices {
+			duplicate = duplicate || k == r
+		}
+
+		if !duplicate {
+			indices = append(indices, r)
+		}
+	}
+
+	return indices
+}
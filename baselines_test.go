@@ -0,0 +1,184 @@
+package mayfly
+
+import (
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Tests for baselines.go - PSO, DE, JADE, GA and random search
+// =============================================================================
+
+var baselineOptimizers = []struct {
+	name     string
+	variant  string
+	optimize func(*Config) (*Result, error)
+}{
+	{"PSO", "pso", OptimizePSO},
+	{"DE", "de", OptimizeDE},
+	{"JADE", "jade", OptimizeJADE},
+	{"GA", "ga", OptimizeGA},
+	{"RandomSearch", "random-search", OptimizeRandomSearch},
+}
+
+func TestBaselineBudgetAndHistory(t *testing.T) {
+	for _, tt := range baselineOptimizers {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.optimize(newTestConfig(Rastrigin, 10, 5, 200, 1))
+			if err != nil {
+				t.Fatalf("%s failed: %v", tt.name, err)
+			}
+
+			// NPop + NPopF + NC + NM evaluations per iteration
+			if budget := 200 * (20 + 20 + 20 + 1); result.FuncEvalCount != budget {
+				t.Errorf("Expected %d evaluations, got %d", budget, result.FuncEvalCount)
+			}
+
+			if len(result.BestSolution) != 200 || result.IterationCount != 200 {
+				t.Fatalf("Expected 200 iterations of history, got %d", len(result.BestSolution))
+			}
+
+			for i := 1; i < len(result.BestSolution); i++ {
+				if result.BestSolution[i] > result.BestSolution[i-1] {
+					t.Fatalf("History increases at iteration %d", i)
+				}
+			}
+
+			if result.BestSolution[199] != result.GlobalBest.Cost {
+				t.Errorf("History ends at %g, expected %g", result.BestSolution[199], result.GlobalBest.Cost)
+			}
+
+			if got := Rastrigin(result.GlobalBest.Position); got != result.GlobalBest.Cost {
+				t.Errorf("Reported cost %g, position evaluates to %g", result.GlobalBest.Cost, got)
+			}
+
+			for _, x := range result.GlobalBest.Position {
+				if x < -5 || x > 5 {
+					t.Fatalf("Best position %v leaves the bounds", result.GlobalBest.Position)
+				}
+			}
+
+			again, err := tt.optimize(newTestConfig(Rastrigin, 10, 5, 200, 1))
+			if err != nil {
+				t.Fatalf("%s failed: %v", tt.name, err)
+			}
+
+			if again.GlobalBest.Cost != result.GlobalBest.Cost {
+				t.Error("Seeded runs are not reproducible")
+			}
+		})
+	}
+}
+
+func TestBaselinesConverge(t *testing.T) {
+	random, err := OptimizeRandomSearch(newTestConfig(Sphere, 10, 5, 200, 1))
+	if err != nil {
+		t.Fatalf("OptimizeRandomSearch failed: %v", err)
+	}
+
+	limits := map[string]float64{"PSO": 1e-10, "DE": 1e-10, "JADE": 1e-10, "GA": 1e-1}
+
+	for _, tt := range baselineOptimizers[:4] {
+		result, err := tt.optimize(newTestConfig(Sphere, 10, 5, 200, 1))
+		if err != nil {
+			t.Fatalf("%s failed: %v", tt.name, err)
+		}
+
+		if result.GlobalBest.Cost > limits[tt.name] {
+			t.Errorf("%s reached %g on Sphere, expected below %g", tt.name, result.GlobalBest.Cost, limits[tt.name])
+		}
+
+		if result.GlobalBest.Cost >= random.GlobalBest.Cost {
+			t.Errorf("%s (%g) does not beat random search (%g)", tt.name, result.GlobalBest.Cost, random.GlobalBest.Cost)
+		}
+	}
+
+	// JADE adapts to Rosenbrock where DE/rand/1/bin stalls
+	de, err := OptimizeDE(newTestConfig(Rosenbrock, 10, 5, 200, 1))
+	if err != nil {
+		t.Fatalf("OptimizeDE failed: %v", err)
+	}
+
+	jade, err := OptimizeJADE(newTestConfig(Rosenbrock, 10, 5, 200, 1))
+	if err != nil {
+		t.Fatalf("OptimizeJADE failed: %v", err)
+	}
+
+	if jade.GlobalBest.Cost >= de.GlobalBest.Cost {
+		t.Errorf("Expected JADE (%g) to beat DE (%g) on Rosenbrock", jade.GlobalBest.Cost, de.GlobalBest.Cost)
+	}
+}
+
+func TestBaselineValidation(t *testing.T) {
+	for _, tt := range baselineOptimizers {
+		config := newTestConfig(Sphere, 10, 5, 200, 1)
+		config.ObjectiveFunc = nil
+
+		if _, err := tt.optimize(config); err == nil {
+			t.Errorf("%s should require an objective function", tt.name)
+		}
+	}
+
+	config := newTestConfig(Sphere, 10, 5, 200, 1)
+	config.NPop = 2
+	config.NPopF = 1
+
+	if _, err := OptimizeDE(config); err == nil {
+		t.Error("DE should require a population of at least 4")
+	}
+}
+
+func TestBaselineUnsupportedOptions(t *testing.T) {
+	options := append([]configOption{
+		{"niching", func(c *Config) { c.NichingMethod = NichingClearing }},
+		{"restarts", func(c *Config) { c.RestartStrategy = RestartStrategyRestart }},
+	}, mayflyOnlyOptions...)
+
+	for _, tt := range baselineOptimizers {
+		for _, o := range options {
+			config := newTestConfig(Sphere, 10, 5, 200, 1)
+			o.modify(config)
+
+			if _, err := tt.optimize(config); err == nil || !strings.Contains(err.Error(), o.option) {
+				t.Errorf("%s: expected an error naming %s, got %v", tt.name, o.option, err)
+			}
+		}
+	}
+}
+
+func TestBaselineVariants(t *testing.T) {
+	runner := NewComparisonRunner().WithRuns(3).WithIterations(30)
+	runner.Variants = nil
+
+	for _, tt := range baselineOptimizers {
+		variant := NewVariant(tt.variant)
+		if variant == nil || variant.Name() != tt.name {
+			t.Fatalf("NewVariant(%q) = %v, expected %s", tt.variant, variant, tt.name)
+		}
+
+		if _, ok := variant.(VariantOptimizer); !ok {
+			t.Errorf("%s should supply its own optimizer", tt.name)
+		}
+
+		runner.Variants = append(runner.Variants, variant)
+	}
+
+	runner.Variants = append(runner.Variants, NewVariant("mpma"))
+
+	comparison := runner.Compare("Sphere", Sphere, 5, -5, 5)
+
+	if comparison.FriedmanResult == nil {
+		t.Fatal("Expected a Friedman test across the baselines")
+	}
+
+	random := comparison.Statistics[4]
+	for i, stats := range comparison.Statistics {
+		if i != 4 && stats.Mean >= random.Mean {
+			t.Errorf("%s (%g) does not beat random search (%g)", comparison.AlgorithmNames[i], stats.Mean, random.Mean)
+		}
+	}
+
+	if _, err := NewHybridVariant("pso", "desma"); err == nil {
+		t.Error("PSO should not combine with pipeline variants")
+	}
+}
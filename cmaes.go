@@ -0,0 +1,484 @@
+// Package mayfly - CMA-ES
+//
+// Implements the covariance matrix adaptation evolution strategy as a
+// baseline for ill-conditioned problems such as Rosenbrock, BentCigar and
+// Discus, optionally with the IPOP restart scheme.
+//
+// References:
+// Hansen, N., & Ostermeier, A. (2001). Completely derandomized
+// self-adaptation in evolution strategies. Evolutionary Computation, 9(2).
+// Hansen, N. (2016). The CMA evolution strategy: A tutorial. arXiv:1604.00772.
+// Auger, A., & Hansen, N. (2005). A restart CMA evolution strategy with
+// increasing population size. IEEE CEC 2005.
+//
+// Every generation samples λ candidates from N(m, σ²C), moves the mean to
+// the weighted mean of the best μ = λ/2, adapts σ by cumulative step-size
+// adaptation and C by rank-one and rank-μ updates. Samples outside the
+// bounds are projected onto them.
+//
+// To compare with the Mayfly variants at equal cost, an iteration of
+// OptimizeCMAES is the evaluation budget of one Mayfly iteration with the
+// same population sizes (NPop + NPopF + NC + NM evaluations), and
+// Result.BestSolution holds the best cost after every such iteration.
+//
+// A run has converged when the best costs of its recent generations differ
+// by a relative 1e-12 ("stagnation"), or when its step size has vanished or
+// C has become ill-conditioned ("diversity"). Config.RestartStrategy then
+// selects:
+//   - "" or "none": the run stops and the remaining budget is not used
+//   - "restart": a new run with the same λ from a random mean
+//   - "ipop": a new run with λ multiplied by RestartPopulationFactor
+//
+// Restarts are limited by MaxRestarts and RestartMaxPopulation (the largest
+// λ) and recorded in Result.Restarts.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// defaultCMASigma is the initial step size for an unset CMASigma, relative
+// to the search range, as recommended by Hansen (2016) for an optimum
+// expected anywhere in the box.
+const defaultCMASigma = 0.3
+
+// CMA-ES convergence tolerances.
+const (
+	cmaTolFun       = 1e-12 // Relative to the costs
+	cmaTolX         = 1e-12 // Relative to the search range
+	cmaMaxCondition = 1e14
+)
+
+// validateCMAESConfig checks the CMA-ES parameters.
+func validateCMAESConfig(config *Config) error {
+	if config.CMAPopulation < 0 || config.CMAPopulation == 1 {
+		return fmt.Errorf("CMAPopulation must be 0 or at least 2, got %d", config.CMAPopulation)
+	}
+
+	if config.CMASigma < 0 || config.CMASigma > 1 {
+		return fmt.Errorf("CMASigma must be in [0, 1], got %v", config.CMASigma)
+	}
+
+	return nil
+}
+
+// OptimizeCMAES minimizes config.ObjectiveFunc with CMA-ES. The Mayfly
+// parameters only determine the evaluation budget of an iteration.
+func OptimizeCMAES(config *Config) (*Result, error) {
+	if config != nil {
+		switch config.RestartStrategy {
+		case "", RestartStrategyNone, RestartStrategyRestart, RestartStrategyIPOP:
+		default:
+			return nil, fmt.Errorf("restart strategy '%s' is not supported by CMA-ES (expected none, restart or ipop)",
+				config.RestartStrategy)
+		}
+	}
+
+	b, err := newBaselineRun(config, 0, true)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &cmaesOptimizer{baselineRun: b}
+	o.run()
+
+	result := o.result()
+	result.Restarts = o.restarts
+
+	return result, nil
+}
+
+// cmaesOptimizer holds the state shared by the runs of one optimization.
+type cmaesOptimizer struct {
+	*baselineRun
+	restarts []RestartEvent
+}
+
+// run performs CMA-ES runs until the budget is used up or a run has
+// converged and no restart is allowed.
+func (o *cmaesOptimizer) run() {
+	config := o.config
+	n := config.ProblemSize
+
+	lambda := config.CMAPopulation
+	if lambda == 0 {
+		lambda = 4 + int(3*math.Log(float64(n)))
+	}
+
+	maxLambda := config.RestartMaxPopulation
+	if maxLambda == 0 {
+		maxLambda = defaultRestartMaxPopulation * lambda
+	}
+
+	maxRestarts := config.MaxRestarts
+	if maxRestarts == 0 {
+		maxRestarts = defaultMaxRestarts
+	}
+
+	factor := config.RestartPopulationFactor
+	restarts := restartsEnabled(config)
+
+	for {
+		run := newCMAESRun(o, lambda)
+
+		reason := run.optimize()
+		if reason == "" || !restarts || len(o.restarts) >= maxRestarts {
+			break
+		}
+
+		regime := RestartStrategyRestart
+		if config.RestartStrategy == RestartStrategyIPOP {
+			regime = "large"
+			if next := int(math.Round(float64(lambda) * factor)); next <= maxLambda {
+				lambda = next
+			}
+		}
+
+		o.restarts = append(o.restarts, RestartEvent{
+			Iteration:   o.evals / o.perIteration,
+			FuncEvals:   o.evals,
+			Reason:      reason,
+			RunBestCost: run.best,
+			BestCost:    o.best.Cost,
+			Regime:      regime,
+			NPop:        lambda,
+		})
+	}
+}
+
+// cmaesRun is one CMA-ES run from a random mean.
+type cmaesRun struct {
+	o       *cmaesOptimizer
+	lambda  int
+	mu      int
+	weights []float64
+	mueff   float64
+	cs      float64 // Step-size cumulation
+	ds      float64 // Step-size damping
+	cc      float64 // Covariance cumulation
+	c1      float64 // Rank-one learning rate
+	cmu     float64 // Rank-μ learning rate
+	chiN    float64 // E‖N(0, I)‖
+
+	mean  []float64
+	sigma float64
+	c     [][]float64 // Covariance matrix
+	b     [][]float64 // Eigenvectors of c (columns)
+	d     []float64   // Square roots of the eigenvalues of c
+	pc    []float64
+	ps    []float64
+
+	generation int
+	eigenAt    int       // Generation of the last eigendecomposition
+	recent     []float64 // Best costs of the recent generations
+	best       float64
+}
+
+// newCMAESRun initializes a run with population size lambda and the
+// default strategy parameters of Hansen (2016).
+func newCMAESRun(o *cmaesOptimizer, lambda int) *cmaesRun {
+	config := o.config
+	n := config.ProblemSize
+	nf := float64(n)
+
+	r := &cmaesRun{o: o, lambda: lambda, mu: lambda / 2, best: math.Inf(1)}
+
+	r.weights = make([]float64, r.mu)
+	sum, sumSquares := 0.0, 0.0
+
+	for i := range r.weights {
+		r.weights[i] = math.Log(float64(lambda+1)/2) - math.Log(float64(i+1))
+		sum += r.weights[i]
+	}
+
+	for i := range r.weights {
+		r.weights[i] /= sum
+		sumSquares += r.weights[i] * r.weights[i]
+	}
+
+	r.mueff = 1 / sumSquares
+	r.cs = (r.mueff + 2) / (nf + r.mueff + 5)
+	r.ds = 1 + 2*math.Max(0, math.Sqrt((r.mueff-1)/(nf+1))-1) + r.cs
+	r.cc = (4 + r.mueff/nf) / (nf + 4 + 2*r.mueff/nf)
+	r.c1 = 2 / ((nf+1.3)*(nf+1.3) + r.mueff)
+	r.cmu = math.Min(1-r.c1, 2*(r.mueff-2+1/r.mueff)/((nf+2)*(nf+2)+r.mueff))
+	r.chiN = math.Sqrt(nf) * (1 - 1/(4*nf) + 1/(21*nf*nf))
+
+	r.mean = unifrndVec(config.LowerBound, config.UpperBound, n, o.rng)
+	r.sigma = orDefault(config.CMASigma, defaultCMASigma) * (config.UpperBound - config.LowerBound)
+	r.c = identityMatrix(n)
+	r.b = identityMatrix(n)
+	r.d = make([]float64, n)
+	r.pc = make([]float64, n)
+	r.ps = make([]float64, n)
+
+	for j := range r.d {
+		r.d[j] = 1
+	}
+
+	return r
+}
+
+// identityMatrix returns the n x n identity matrix.
+func identityMatrix(n int) [][]float64 {
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+		m[i][i] = 1
+	}
+
+	return m
+}
+
+// optimize runs generations until the budget is used up ("") or the run
+// has converged (the restart reason).
+func (r *cmaesRun) optimize() string {
+	o := r.o
+	config := o.config
+	n := config.ProblemSize
+
+	window := 10 + int(math.Ceil(30*float64(n)/float64(r.lambda)))
+	xs := make([][]float64, r.lambda)
+	ys := make([][]float64, r.lambda)
+	costs := make([]float64, r.lambda)
+	order := make([]int, r.lambda)
+
+	for {
+		if o.evals+r.lambda > o.budget {
+			// Spend the rest of the budget on a last, partial generation
+			for o.evals < o.budget {
+				x, _ := r.sample()
+				o.evaluate(x)
+			}
+
+			return ""
+		}
+
+		for k := range xs {
+			xs[k], ys[k] = r.sample()
+			costs[k] = o.evaluate(xs[k])
+			order[k] = k
+		}
+
+		sort.SliceStable(order, func(a, b int) bool { return costs[order[a]] < costs[order[b]] })
+
+		r.update(ys, order)
+		r.generation++
+
+		best := costs[order[0]]
+		r.best = math.Min(r.best, best)
+
+		r.recent = append(r.recent, best)
+		if len(r.recent) > window {
+			r.recent = r.recent[1:]
+		}
+
+		if reason := r.converged(costs, window); reason != "" {
+			return reason
+		}
+	}
+}
+
+// sample draws a candidate x = m + σ B D z, projected onto the bounds, and
+// returns it with its step y = (x - m) / σ.
+func (r *cmaesRun) sample() ([]float64, []float64) {
+	config := r.o.config
+	n := len(r.mean)
+
+	z := make([]float64, n)
+	for j := range z {
+		z[j] = r.d[j] * r.o.rng.NormFloat64()
+	}
+
+	x := make([]float64, n)
+	y := make([]float64, n)
+
+	for i := range x {
+		step := 0.0
+		for j := range z {
+			step += r.b[i][j] * z[j]
+		}
+
+		x[i] = math.Max(config.LowerBound, math.Min(config.UpperBound, r.mean[i]+r.sigma*step))
+		y[i] = (x[i] - r.mean[i]) / r.sigma
+	}
+
+	return x, y
+}
+
+// update moves the mean and adapts the evolution paths, σ and C from the
+// steps ys ranked by order.
+func (r *cmaesRun) update(ys [][]float64, order []int) {
+	n := len(r.mean)
+
+	yw := make([]float64, n)
+	for i, w := range r.weights {
+		for j := range yw {
+			yw[j] += w * ys[order[i]][j]
+		}
+	}
+
+	for j := range r.mean {
+		r.mean[j] += r.sigma * yw[j]
+	}
+
+	// ps uses C^(-1/2) yw = B D^(-1) Bᵀ yw
+	rotated := make([]float64, n)
+	for k := range rotated {
+		for j := range yw {
+			rotated[k] += r.b[j][k] * yw[j]
+		}
+
+		rotated[k] /= r.d[k]
+	}
+
+	scale := math.Sqrt(r.cs * (2 - r.cs) * r.mueff)
+	for i := range r.ps {
+		whitened := 0.0
+		for k := range rotated {
+			whitened += r.b[i][k] * rotated[k]
+		}
+
+		r.ps[i] = (1-r.cs)*r.ps[i] + scale*whitened
+	}
+
+	psNorm := norm(r.ps)
+	correction := math.Sqrt(1 - math.Pow(1-r.cs, float64(2*(r.generation+1))))
+
+	hsig := 0.0
+	if psNorm/correction/r.chiN < 1.4+2/float64(n+1) {
+		hsig = 1
+	}
+
+	scale = hsig * math.Sqrt(r.cc*(2-r.cc)*r.mueff)
+	for j := range r.pc {
+		r.pc[j] = (1-r.cc)*r.pc[j] + scale*yw[j]
+	}
+
+	decay := 1 - r.c1 - r.cmu + (1-hsig)*r.c1*r.cc*(2-r.cc)
+	for i := range r.c {
+		for j := 0; j <= i; j++ {
+			rankMu := 0.0
+			for k, w := range r.weights {
+				rankMu += w * ys[order[k]][i] * ys[order[k]][j]
+			}
+
+			v := decay*r.c[i][j] + r.c1*r.pc[i]*r.pc[j] + r.cmu*rankMu
+			r.c[i][j], r.c[j][i] = v, v
+		}
+	}
+
+	r.sigma *= math.Exp(r.cs / r.ds * (psNorm/r.chiN - 1))
+
+	// Decompose C every few generations, as its change is O(c1 + cμ)
+	lag := max(1, int(1/((r.c1+r.cmu)*float64(n)*10)))
+	if r.generation-r.eigenAt >= lag {
+		r.eigenAt = r.generation
+		r.decompose()
+	}
+}
+
+// decompose updates B and D from C.
+func (r *cmaesRun) decompose() {
+	values, vectors := symmetricEigen(r.c)
+
+	r.b = vectors
+	for j, v := range values {
+		r.d[j] = math.Sqrt(math.Max(v, 1e-300))
+	}
+}
+
+// converged returns the restart reason if the run has converged, or "".
+func (r *cmaesRun) converged(costs []float64, window int) string {
+	config := r.o.config
+
+	if len(r.recent) == window {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, f := range append(r.recent, costs...) {
+			lo, hi = math.Min(lo, f), math.Max(hi, f)
+		}
+
+		if hi-lo < cmaTolFun*math.Max(math.Abs(lo), math.Abs(hi)) {
+			return "stagnation"
+		}
+	}
+
+	largest, smallest := 0.0, math.Inf(1)
+	for _, v := range r.d {
+		largest, smallest = math.Max(largest, v), math.Min(smallest, v)
+	}
+
+	if r.sigma*largest < cmaTolX*(config.UpperBound-config.LowerBound) ||
+		(largest/smallest)*(largest/smallest) > cmaMaxCondition {
+		return "diversity"
+	}
+
+	return ""
+}
+
+// symmetricEigen returns the eigenvalues and the eigenvectors (as columns)
+// of the symmetric matrix a by cyclic Jacobi rotations.
+func symmetricEigen(a [][]float64) ([]float64, [][]float64) {
+	n := len(a)
+	m := make([][]float64, n)
+
+	for i := range m {
+		m[i] = append([]float64(nil), a[i]...)
+	}
+
+	v := identityMatrix(n)
+
+	for sweep := 0; sweep < 50; sweep++ {
+		off := 0.0
+		for i := range m {
+			for j := i + 1; j < n; j++ {
+				off += m[i][j] * m[i][j]
+			}
+		}
+
+		if off < 1e-30 {
+			break
+		}
+
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if math.Abs(m[p][q]) < 1e-300 {
+					continue
+				}
+
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < n; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p], m[k][q] = c*mkp-s*mkq, s*mkp+c*mkq
+				}
+
+				for k := 0; k < n; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k], m[q][k] = c*mpk-s*mqk, s*mpk+c*mqk
+				}
+
+				for k := 0; k < n; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p], v[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = m[i][i]
+	}
+
+	return values, v
+}
@@ -0,0 +1,247 @@
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Tests for cmaes.go - CMA-ES
+// =============================================================================
+
+func TestSymmetricEigen(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	n := 6
+
+	a := make([][]float64, n)
+	for i := range a {
+		a[i] = make([]float64, n)
+	}
+
+	for i := range a {
+		for j := 0; j <= i; j++ {
+			v := rng.NormFloat64()
+			a[i][j], a[j][i] = v, v
+		}
+	}
+
+	values, vectors := symmetricEigen(a)
+
+	for k, lambda := range values {
+		v := make([]float64, n)
+		for i := range v {
+			v[i] = vectors[i][k]
+		}
+
+		if math.Abs(norm(v)-1) > 1e-10 {
+			t.Errorf("Eigenvector %d has norm %g", k, norm(v))
+		}
+
+		for i := range a {
+			if got := dot(a[i], v); math.Abs(got-lambda*v[i]) > 1e-10 {
+				t.Fatalf("Eigenpair %d: (Av)_%d = %g, expected %g", k, i, got, lambda*v[i])
+			}
+		}
+	}
+}
+
+func TestCMAESIllConditioned(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		f    ObjectiveFunction
+	}{
+		{"Rosenbrock", Rosenbrock},
+		{"BentCigar", BentCigar},
+		{"Discus", Discus},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := OptimizeCMAES(newTestConfig(tt.f, 10, 5, 300, 1))
+			if err != nil {
+				t.Fatalf("OptimizeCMAES failed: %v", err)
+			}
+
+			if result.GlobalBest.Cost > 1e-15 {
+				t.Errorf("Expected convergence below 1e-15, got %g", result.GlobalBest.Cost)
+			}
+
+			if got := tt.f(result.GlobalBest.Position); got != result.GlobalBest.Cost {
+				t.Errorf("Reported cost %g, position evaluates to %g", result.GlobalBest.Cost, got)
+			}
+		})
+	}
+
+	// CMA-ES beats MPMA on Rosenbrock with the same budget
+	config := NewMPMAConfig()
+	config.ObjectiveFunc = Rosenbrock
+	config.ProblemSize = 10
+	config.LowerBound = -5
+	config.UpperBound = 5
+	config.MaxIterations = 300
+	config.Rand = rand.New(rand.NewSource(1))
+
+	mpma, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	cmaes, err := OptimizeCMAES(newTestConfig(Rosenbrock, 10, 5, 300, 1))
+	if err != nil {
+		t.Fatalf("OptimizeCMAES failed: %v", err)
+	}
+
+	if cmaes.GlobalBest.Cost >= mpma.GlobalBest.Cost || cmaes.FuncEvalCount > mpma.FuncEvalCount {
+		t.Errorf("Expected CMA-ES (%g, %d evaluations) to beat MPMA (%g, %d evaluations)",
+			cmaes.GlobalBest.Cost, cmaes.FuncEvalCount, mpma.GlobalBest.Cost, mpma.FuncEvalCount)
+	}
+}
+
+func TestCMAESBudgetAndHistory(t *testing.T) {
+	config := newTestConfig(Rastrigin, 10, 5, 300, 3)
+	config.RestartStrategy = RestartStrategyRestart
+
+	result, err := OptimizeCMAES(config)
+	if err != nil {
+		t.Fatalf("OptimizeCMAES failed: %v", err)
+	}
+
+	// NPop + NPopF + NC + NM evaluations per iteration
+	if budget := 300 * (20 + 20 + 20 + 1); result.FuncEvalCount != budget {
+		t.Errorf("Expected the budget of %d evaluations to be used, got %d", budget, result.FuncEvalCount)
+	}
+
+	if len(result.BestSolution) != 300 || result.IterationCount != 300 {
+		t.Fatalf("Expected 300 iterations of history, got %d", len(result.BestSolution))
+	}
+
+	for i := 1; i < len(result.BestSolution); i++ {
+		if result.BestSolution[i] > result.BestSolution[i-1] {
+			t.Fatalf("History increases at iteration %d", i)
+		}
+	}
+
+	if result.BestSolution[299] != result.GlobalBest.Cost {
+		t.Errorf("History ends at %g, expected %g", result.BestSolution[299], result.GlobalBest.Cost)
+	}
+
+	again, err := OptimizeCMAES(newTestConfig(Rastrigin, 10, 5, 300, 3))
+	if err != nil {
+		t.Fatalf("OptimizeCMAES failed: %v", err)
+	}
+
+	first, err := OptimizeCMAES(newTestConfig(Rastrigin, 10, 5, 300, 3))
+	if err != nil {
+		t.Fatalf("OptimizeCMAES failed: %v", err)
+	}
+
+	if again.GlobalBest.Cost != first.GlobalBest.Cost {
+		t.Error("Seeded CMA-ES runs are not reproducible")
+	}
+}
+
+func TestIPOPCMAES(t *testing.T) {
+	config := newTestConfig(Rastrigin, 10, 5, 300, 1)
+	config.RestartStrategy = RestartStrategyIPOP
+
+	result, err := OptimizeCMAES(config)
+	if err != nil {
+		t.Fatalf("OptimizeCMAES failed: %v", err)
+	}
+
+	if len(result.Restarts) == 0 {
+		t.Fatal("Expected restarts on Rastrigin")
+	}
+
+	lambda := 4 + int(3*math.Log(10))
+	for i, event := range result.Restarts {
+		lambda *= 2
+		if event.NPop != lambda || event.Regime != "large" {
+			t.Errorf("Restart %d: expected λ = %d in the large regime, got %d (%s)", i, lambda, event.NPop, event.Regime)
+		}
+	}
+
+	plain, err := OptimizeCMAES(newTestConfig(Rastrigin, 10, 5, 300, 1))
+	if err != nil {
+		t.Fatalf("OptimizeCMAES failed: %v", err)
+	}
+
+	if len(plain.Restarts) != 0 || plain.FuncEvalCount >= result.FuncEvalCount {
+		t.Errorf("Expected a single run without restarts to stop early, got %d restarts and %d evaluations",
+			len(plain.Restarts), plain.FuncEvalCount)
+	}
+}
+
+func TestCMAESValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"population of 1", func(c *Config) { c.CMAPopulation = 1 }},
+		{"sigma above 1", func(c *Config) { c.CMASigma = 2 }},
+		{"bipop", func(c *Config) { c.RestartStrategy = RestartStrategyBIPOP }},
+		{"niching", func(c *Config) { c.NichingMethod = NichingClearing }},
+		{"adaptive parameters", func(c *Config) { c.AdaptiveParameters = true }},
+		{"surrogate", func(c *Config) { c.UseSurrogate = true }},
+		{"no objective", func(c *Config) { c.ObjectiveFunc = nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newTestConfig(Sphere, 10, 5, 300, 1)
+			tt.modify(config)
+
+			if _, err := OptimizeCMAES(config); err == nil {
+				t.Error("Expected a validation error")
+			}
+		})
+	}
+}
+
+func TestCMAESVariant(t *testing.T) {
+	for name, expected := range map[string]string{"cmaes": "CMA-ES", "ipop-cmaes": "IPOP-CMA-ES"} {
+		variant := NewVariant(name)
+		if variant == nil || variant.Name() != expected {
+			t.Fatalf("NewVariant(%q) = %v, expected %s", name, variant, expected)
+		}
+
+		if _, ok := variant.(VariantOptimizer); !ok {
+			t.Errorf("%s should supply its own optimizer", expected)
+		}
+	}
+
+	if _, err := NewHybridVariant("cmaes", "desma"); err == nil {
+		t.Error("CMA-ES should not combine with pipeline variants")
+	}
+
+	result, err := NewBuilder("ipop-cmaes").ForProblem(Sphere, 5, -5, 5).WithIterations(50).Optimize()
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if result.GlobalBest.Cost > 1e-10 {
+		t.Errorf("Expected IPOP-CMA-ES to solve Sphere, got %g", result.GlobalBest.Cost)
+	}
+
+	// Scored by the selector among the best for ill-conditioned problems
+	recs := NewAlgorithmSelector().RecommendAlgorithms(ProblemCharacteristics{
+		Dimensionality: 30,
+		Modality:       Unimodal,
+		Landscape:      NarrowValley,
+	})
+
+	for _, rec := range recs {
+		if rec.Variant.Name() != "CMA-ES" {
+			continue
+		}
+
+		if rec.Score < recs[0].Score || rec.Confidence < 0.9 {
+			t.Errorf("Expected CMA-ES to rank first with high confidence, got score %.2f (best %.2f) and confidence %.2f",
+				rec.Score, recs[0].Score, rec.Confidence)
+		}
+
+		if !strings.Contains(rec.Reasoning, "Covariance adaptation") {
+			t.Errorf("Unexpected reasoning %q", rec.Reasoning)
+		}
+	}
+}
@@ -0,0 +1,649 @@
+// Package mayfly - Constrained multi-objective benchmark problems
+//
+// CF1-CF10 of the CEC 2009 competition (Zhang et al., 2009) and the C-DTLZ
+// problems (Jain & Deb, 2014), each with a constraint function and its
+// true Pareto front. Constraint values c_j(x) >= 0 are feasible.
+//
+// As in mobenchmarks.go, every problem is defined on [0, 1]^n: CF variables
+// x_2, ..., x_n (x_3, ..., x_n for CF8-CF10) are mapped linearly to their
+// original domains. The C-DTLZ objectives are the DTLZ functions themselves.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+)
+
+// cfProblemSize is the standard problem size of CF1-CF10.
+const cfProblemSize = 10
+
+// CFProblems returns the constrained problems CF1-CF10 with their standard
+// problem size 10; CF1-CF7 have two objectives, CF8-CF10 three.
+func CFProblems() []MultiObjectiveProblem {
+	problem := func(i int, f MultiObjectiveFunction, c ConstraintFunction, objectives int, front func(int) []*ParetoSolution) MultiObjectiveProblem {
+		return MultiObjectiveProblem{fmt.Sprintf("CF%d", i), f, cfProblemSize, objectives, 0, 1, front, c}
+	}
+
+	cf45Front := func(f1 float64) float64 {
+		switch {
+		case f1 <= 0.5:
+			return 1 - f1
+		case f1 <= 0.75:
+			return 0.75 - 0.5*f1
+		default:
+			return 1.125 - f1
+		}
+	}
+
+	cf67Front := func(f1 float64) float64 {
+		switch {
+		case f1 <= 0.5:
+			return (1 - f1) * (1 - f1)
+		case f1 <= 0.75:
+			return 0.5 * (1 - f1)
+		default:
+			return 0.25 * math.Sqrt(1-f1)
+		}
+	}
+
+	// On the CF9 and CF10 fronts f_1² / (1 - f_3²) lies in these intervals
+	cf910Ratios := [][2]float64{{0, 0}, {0.25, 0.5}, {0.75, 1}}
+
+	return []MultiObjectiveProblem{
+		problem(1, CF1, CF1Constraints, 2, func(int) []*ParetoSolution {
+			// The constraint leaves 21 points of the line f1 + f2 = 1
+			front := make([][]float64, 21)
+			for i := range front {
+				f1 := float64(i) / 20
+				front[i] = []float64{f1, 1 - f1}
+			}
+
+			return nondominatedFront(front)
+		}),
+		problem(2, CF2, CF2Constraints, 2, func(points int) []*ParetoSolution {
+			return cfCurve(points, [][2]float64{{0, 0}, {1.0 / 16, 0.25}, {9.0 / 16, 1}}, func(f1 float64) float64 {
+				return 1 - math.Sqrt(f1)
+			})
+		}),
+		problem(3, CF3, CF3Constraints, 2, func(points int) []*ParetoSolution {
+			return cfCurve(points, [][2]float64{{0, 0}, {0.5, math.Sqrt(0.5)}, {math.Sqrt(0.75), 1}}, func(f1 float64) float64 {
+				return 1 - f1*f1
+			})
+		}),
+		problem(4, CF4, CF4Constraints, 2, func(points int) []*ParetoSolution {
+			return cfCurve(points, [][2]float64{{0, 1}}, cf45Front)
+		}),
+		problem(5, CF5, CF5Constraints, 2, func(points int) []*ParetoSolution {
+			return cfCurve(points, [][2]float64{{0, 1}}, cf45Front)
+		}),
+		problem(6, CF6, CF6Constraints, 2, func(points int) []*ParetoSolution {
+			return cfCurve(points, [][2]float64{{0, 1}}, cf67Front)
+		}),
+		problem(7, CF7, CF7Constraints, 2, func(points int) []*ParetoSolution {
+			return cfCurve(points, [][2]float64{{0, 1}}, cf67Front)
+		}),
+		problem(8, CF8, CF8Constraints, 3, func(points int) []*ParetoSolution {
+			// Five curves on the unit sphere with f_1² / (1 - f_3²) = i/4
+			return cfSphere(points, [][2]float64{{0, 0}, {0.25, 0.25}, {0.5, 0.5}, {0.75, 0.75}, {1, 1}})
+		}),
+		problem(9, CF9, CF9Constraints, 3, func(points int) []*ParetoSolution {
+			return cfSphere(points, cf910Ratios)
+		}),
+		problem(10, CF10, CF10Constraints, 3, func(points int) []*ParetoSolution {
+			return cfSphere(points, cf910Ratios)
+		}),
+	}
+}
+
+// CDTLZProblems returns C1-DTLZ1, C1-DTLZ3, C2-DTLZ2, C3-DTLZ1 and C3-DTLZ4
+// for the given number of objectives (at least 2), with the problem sizes of
+// the underlying DTLZ problems.
+func CDTLZProblems(objectives int) []MultiObjectiveProblem {
+	dtlz := DTLZProblems(objectives)
+
+	problem := func(name string, base MultiObjectiveProblem, c ConstraintFunction, front func(int) []*ParetoSolution) MultiObjectiveProblem {
+		return MultiObjectiveProblem{name, base.Func, base.ProblemSize, objectives, 0, 1, front, c}
+	}
+
+	return []MultiObjectiveProblem{
+		problem("C1-DTLZ1", dtlz[0], C1DTLZ1Constraints(objectives), dtlz[0].TrueFront),
+		problem("C1-DTLZ3", dtlz[2], C1DTLZ3Constraints(objectives), dtlz[2].TrueFront),
+		problem("C2-DTLZ2", dtlz[1], C2DTLZ2Constraints(objectives), func(points int) []*ParetoSolution {
+			// The parts of the sphere that satisfy the constraint
+			var front [][]float64
+			for _, s := range dtlz[1].TrueFront(points) {
+				if c2dtlz2Constraint(s.ObjectiveValues) >= 0 {
+					front = append(front, s.ObjectiveValues)
+				}
+			}
+
+			return nondominatedFront(front)
+		}),
+		problem("C3-DTLZ1", dtlz[0], C3DTLZ1Constraints(objectives), func(points int) []*ParetoSolution {
+			// Every direction p (Σ p = 0.5) is scaled onto the nearest constraint
+			front := DasDennisPoints(objectives, dasDennisDivisions(objectives, points))
+			for _, p := range front {
+				low := math.Inf(1)
+				for j := range p {
+					p[j] *= 0.5
+					low = math.Min(low, p[j])
+				}
+
+				for j := range p {
+					p[j] /= 0.5 + low
+				}
+			}
+
+			return nondominatedFront(front)
+		}),
+		problem("C3-DTLZ4", dtlz[3], C3DTLZ4Constraints(objectives), func(points int) []*ParetoSolution {
+			// Every direction u (‖u‖ = 1) is scaled onto the nearest constraint
+			front := DasDennisPoints(objectives, dasDennisDivisions(objectives, points))
+			for _, p := range front {
+				length := norm(p)
+
+				high := 0.0
+				for j := range p {
+					p[j] /= length
+					high = math.Max(high, p[j]*p[j])
+				}
+
+				for j := range p {
+					p[j] /= math.Sqrt(1 - 0.75*high)
+				}
+			}
+
+			return nondominatedFront(front)
+		}),
+	}
+}
+
+// =============================================================================
+// CF
+// =============================================================================
+
+// CF1 has 21 Pareto-optimal points on the line f1 + f2 = 1 at
+// x_j = x_1^(0.5·(1 + 3(j-2)/(n-2))).
+func CF1(x []float64) []float64 {
+	n := float64(len(x))
+	d := cfDistance(x, 2, true, func(j int) float64 {
+		t := x[j-1] - math.Pow(x[0], 0.5*(1+3*float64(j-2)/(n-2)))
+		return t * t
+	})
+
+	return []float64{x[0] + d[0], 1 - x[0] + d[1]}
+}
+
+// CF1Constraints returns f1 + f2 - |sin(10π(f1 - f2 + 1))| - 1 >= 0.
+func CF1Constraints(x []float64) []float64 {
+	f := CF1(x)
+	return []float64{f[0] + f[1] - math.Abs(math.Sin(10*math.Pi*(f[0]-f[1]+1))) - 1}
+}
+
+// CF2 has a disconnected front f2 = 1 - sqrt(f1) at x_j = sin(6πx_1 + jπ/n)
+// (odd j) and x_j = cos(6πx_1 + jπ/n) (even j). x_2, ..., x_n are mapped to
+// [-1, 1].
+func CF2(x []float64) []float64 {
+	v := cfScale(x, 1, 1)
+	d := cfDistance(v, 2, true, func(j int) float64 {
+		t := v[j-1] - cfWave(j, v[0], len(v), math.Sin, math.Cos)
+		return t * t
+	})
+
+	return []float64{v[0] + d[0], 1 - math.Sqrt(v[0]) + d[1]}
+}
+
+// CF2Constraints returns t / (1 + e^(4|t|)) >= 0 with
+// t = f2 + sqrt(f1) - sin(2π(sqrt(f1) - f2 + 1)) - 1.
+func CF2Constraints(x []float64) []float64 {
+	f := CF2(x)
+	t := f[1] + math.Sqrt(f[0]) - math.Sin(2*math.Pi*(math.Sqrt(f[0])-f[1]+1)) - 1
+
+	return []float64{t / (1 + math.Exp(4*math.Abs(t)))}
+}
+
+// CF3 has a disconnected front f2 = 1 - f1² at x_j = sin(6πx_1 + jπ/n) and
+// a multimodal distance function. x_2, ..., x_n are mapped to [-2, 2].
+func CF3(x []float64) []float64 {
+	v := cfScale(x, 1, 2)
+
+	var sums, products [2]float64
+	var counts [2]int
+
+	products[0], products[1] = 1, 1
+
+	for j := 2; j <= len(v); j++ {
+		y := v[j-1] - math.Sin(6*math.Pi*v[0]+float64(j)*math.Pi/float64(len(v)))
+		i := (j - 1) % 2
+		sums[i] += y * y
+		products[i] *= math.Cos(20 * y * math.Pi / math.Sqrt(float64(j)))
+		counts[i]++
+	}
+
+	f := []float64{v[0], 1 - v[0]*v[0]}
+	for i := range f {
+		if counts[i] > 0 {
+			f[i] += 2 / float64(counts[i]) * (4*sums[i] - 2*products[i] + 2)
+		}
+	}
+
+	return f
+}
+
+// CF3Constraints returns f2 + f1² - sin(2π(f1² - f2 + 1)) - 1 >= 0.
+func CF3Constraints(x []float64) []float64 {
+	f := CF3(x)
+	return []float64{f[1] + f[0]*f[0] - math.Sin(2*math.Pi*(f[0]*f[0]-f[1]+1)) - 1}
+}
+
+// CF4 has a piecewise linear front; the constraint moves x_2 away from
+// sin(6πx_1 + 2π/n) for x_1 > 0.5. x_2, ..., x_n are mapped to [-2, 2].
+func CF4(x []float64) []float64 {
+	v := cfScale(x, 1, 2)
+	d := cfDistance(v, 2, false, func(j int) float64 {
+		y := v[j-1] - math.Sin(6*math.Pi*v[0]+float64(j)*math.Pi/float64(len(v)))
+		if j == 2 {
+			return cf45H(y)
+		}
+
+		return y * y
+	})
+
+	return []float64{v[0] + d[0], 1 - v[0] + d[1]}
+}
+
+// CF4Constraints returns t / (1 + e^(4|t|)) >= 0 with
+// t = x_2 - sin(6πx_1 + 2π/n) - 0.5x_1 + 0.25.
+func CF4Constraints(x []float64) []float64 {
+	v := cfScale(x, 1, 2)
+	t := v[1] - math.Sin(6*math.Pi*v[0]+2*math.Pi/float64(len(v))) - 0.5*v[0] + 0.25
+
+	return []float64{t / (1 + math.Exp(4*math.Abs(t)))}
+}
+
+// CF5 has the front of CF4 with x_1-dependent distance variables and a
+// multimodal distance function. x_2, ..., x_n are mapped to [-2, 2].
+func CF5(x []float64) []float64 {
+	v := cfScale(x, 1, 2)
+	d := cfDistance(v, 2, false, func(j int) float64 {
+		y := v[j-1] - 0.8*v[0]*cfWave(j, v[0], len(v), math.Cos, math.Sin)
+		if j == 2 {
+			return cf45H(y)
+		}
+
+		return 2*y*y - math.Cos(4*math.Pi*y) + 1
+	})
+
+	return []float64{v[0] + d[0], 1 - v[0] + d[1]}
+}
+
+// CF5Constraints returns x_2 - 0.8x_1·sin(6πx_1 + 2π/n) - 0.5x_1 + 0.25 >= 0.
+func CF5Constraints(x []float64) []float64 {
+	v := cfScale(x, 1, 2)
+	return []float64{v[1] - 0.8*v[0]*math.Sin(6*math.Pi*v[0]+2*math.Pi/float64(len(v))) - 0.5*v[0] + 0.25}
+}
+
+// CF6 has a front of three parts that two constraints on x_2 and x_4
+// shape. x_2, ..., x_n are mapped to [-2, 2].
+func CF6(x []float64) []float64 {
+	v := cfScale(x, 1, 2)
+	d := cfDistance(v, 2, false, func(j int) float64 {
+		y := v[j-1] - 0.8*v[0]*cfWave(j, v[0], len(v), math.Cos, math.Sin)
+		return y * y
+	})
+
+	return []float64{v[0] + d[0], (1-v[0])*(1-v[0]) + d[1]}
+}
+
+// CF6Constraints returns the two constraints on x_2 and x_4 of CF6.
+func CF6Constraints(x []float64) []float64 {
+	v := cfScale(x, 1, 2)
+	return cf67Constraints(v, 0.8*v[0])
+}
+
+// CF7 has the front of CF6 with a multimodal distance function. x_2, ...,
+// x_n are mapped to [-2, 2].
+func CF7(x []float64) []float64 {
+	v := cfScale(x, 1, 2)
+	d := cfDistance(v, 2, false, func(j int) float64 {
+		y := v[j-1] - cfWave(j, v[0], len(v), math.Cos, math.Sin)
+		if j == 2 || j == 4 {
+			return y * y
+		}
+
+		return 2*y*y - math.Cos(4*math.Pi*y) + 1
+	})
+
+	return []float64{v[0] + d[0], (1-v[0])*(1-v[0]) + d[1]}
+}
+
+// CF7Constraints returns the two constraints on x_2 and x_4 of CF7.
+func CF7Constraints(x []float64) []float64 {
+	return cf67Constraints(cfScale(x, 1, 2), 1)
+}
+
+// CF8 has a front of five curves on the unit sphere at
+// x_j = 2x_2·sin(2πx_1 + jπ/n). x_3, ..., x_n are mapped to [-4, 4].
+func CF8(x []float64) []float64 {
+	v := cfScale(x, 2, 4)
+	return cfSphereObjectives(v, cfDistance(v, 3, true, func(j int) float64 {
+		y := v[j-1] - 2*v[1]*math.Sin(2*math.Pi*v[0]+float64(j)*math.Pi/float64(len(v)))
+		return y * y
+	}))
+}
+
+// CF8Constraints returns the constraint of CF8 with a = 4.
+func CF8Constraints(x []float64) []float64 {
+	return []float64{cfSphereConstraint(CF8(x), 4, true)}
+}
+
+// CF9 has a disconnected front on the unit sphere at
+// x_j = 2x_2·sin(2πx_1 + jπ/n). x_3, ..., x_n are mapped to [-4, 4].
+func CF9(x []float64) []float64 {
+	v := cfScale(x, 2, 4)
+	return cfSphereObjectives(v, cfDistance(v, 3, true, func(j int) float64 {
+		y := v[j-1] - 2*v[1]*math.Sin(2*math.Pi*v[0]+float64(j)*math.Pi/float64(len(v)))
+		return y * y
+	}))
+}
+
+// CF9Constraints returns the constraint of CF9 with a = 3.
+func CF9Constraints(x []float64) []float64 {
+	return []float64{cfSphereConstraint(CF9(x), 3, false)}
+}
+
+// CF10 has the front of CF9 with a multimodal distance function. x_3, ...,
+// x_n are mapped to [-4, 4].
+func CF10(x []float64) []float64 {
+	v := cfScale(x, 2, 4)
+	return cfSphereObjectives(v, cfDistance(v, 3, true, func(j int) float64 {
+		y := v[j-1] - 2*v[1]*math.Sin(2*math.Pi*v[0]+float64(j)*math.Pi/float64(len(v)))
+		return 4*y*y - math.Cos(8*math.Pi*y) + 1
+	}))
+}
+
+// CF10Constraints returns the constraint of CF10 with a = 1.
+func CF10Constraints(x []float64) []float64 {
+	return []float64{cfSphereConstraint(CF10(x), 1, false)}
+}
+
+// cfScale maps x_(fixed+1), ..., x_n from [0, 1] to [-radius, radius].
+func cfScale(x []float64, fixed int, radius float64) []float64 {
+	v := append([]float64(nil), x...)
+	for j := fixed; j < len(v); j++ {
+		v[j] = radius * (2*v[j] - 1)
+	}
+
+	return v
+}
+
+// cfDistance returns the distance term of each of the m objectives: the sum
+// (or, if mean is set, twice the mean) of term(j) over the 1-based indices
+// j >= m with (j - 1) mod m = objective.
+func cfDistance(v []float64, m int, mean bool, term func(j int) float64) []float64 {
+	sums := make([]float64, m)
+	counts := make([]int, m)
+
+	for j := m; j <= len(v); j++ {
+		i := (j - 1) % m
+		sums[i] += term(j)
+		counts[i]++
+	}
+
+	if mean {
+		for i := range sums {
+			if counts[i] > 0 {
+				sums[i] *= 2 / float64(counts[i])
+			}
+		}
+	}
+
+	return sums
+}
+
+// cfWave returns odd(6πx_1 + jπ/n) for odd j and even(6πx_1 + jπ/n) for even j.
+func cfWave(j int, x1 float64, n int, odd, even func(float64) float64) float64 {
+	angle := 6*math.Pi*x1 + float64(j)*math.Pi/float64(n)
+	if j%2 == 1 {
+		return odd(angle)
+	}
+
+	return even(angle)
+}
+
+// cf45H is the distance function of x_2 in CF4 and CF5.
+func cf45H(t float64) float64 {
+	if t < 1.5*(1-math.Sqrt2/2) {
+		return math.Abs(t)
+	}
+
+	return 0.125 + (t-1)*(t-1)
+}
+
+// cf67Constraints returns the constraints of CF6 and CF7 on x_2 and x_4,
+// whose optimal values are amplitude·sin(6πx_1 + jπ/n).
+func cf67Constraints(v []float64, amplitude float64) []float64 {
+	n := float64(len(v))
+	signedRoot := func(a float64) float64 {
+		if a < 0 {
+			return -math.Sqrt(-a)
+		}
+
+		return math.Sqrt(a)
+	}
+
+	rest := 1 - v[0]
+
+	return []float64{
+		v[1] - amplitude*math.Sin(6*math.Pi*v[0]+2*math.Pi/n) - signedRoot(0.5*rest-rest*rest),
+		v[3] - amplitude*math.Sin(6*math.Pi*v[0]+4*math.Pi/n) - signedRoot(0.25*math.Sqrt(rest)-0.5*rest),
+	}
+}
+
+// cfSphereObjectives returns the CF8-CF10 objectives: the point of the unit
+// sphere at angles x_1·π/2 and x_2·π/2 plus the distance terms d.
+func cfSphereObjectives(v, d []float64) []float64 {
+	a, b := 0.5*math.Pi*v[0], 0.5*math.Pi*v[1]
+
+	return []float64{
+		math.Cos(a)*math.Cos(b) + d[0],
+		math.Cos(a)*math.Sin(b) + d[1],
+		math.Sin(a) + d[2],
+	}
+}
+
+// cfSphereConstraint returns the CF8-CF10 constraint
+// (f1² + f2²)/(1 - f3²) - a·s(2π((f1² - f2²)/(1 - f3²) + 1)) - 1, where s is
+// |sin| if absolute is set and sin otherwise.
+func cfSphereConstraint(f []float64, a float64, absolute bool) float64 {
+	scale := 1 - f[2]*f[2]
+	wave := math.Sin(2 * math.Pi * ((f[0]*f[0]-f[1]*f[1])/scale + 1))
+
+	if absolute {
+		wave = math.Abs(wave)
+	}
+
+	return (f[0]*f[0]+f[1]*f[1])/scale - a*wave - 1
+}
+
+// cfCurve returns points of the two-objective front (f1, f2(f1)) for f1 in
+// the given intervals, spread in proportion to their lengths.
+func cfCurve(points int, intervals [][2]float64, f2 func(float64) float64) []*ParetoSolution {
+	total := 0.0
+	for _, interval := range intervals {
+		total += interval[1] - interval[0]
+	}
+
+	var front [][]float64
+
+	for _, interval := range intervals {
+		n := 1
+		if length := interval[1] - interval[0]; length > 0 {
+			n = int(math.Max(2, float64(points)*length/total))
+		}
+
+		for _, f1 := range linspace(interval[0], interval[1], n) {
+			front = append(front, []float64{f1, f2(f1)})
+		}
+	}
+
+	return nondominatedFront(front)
+}
+
+// cfSphere returns points of the unit sphere whose ratio f1² / (1 - f3²)
+// lies in one of the given intervals.
+func cfSphere(points int, ratios [][2]float64) []*ParetoSolution {
+	total := 0.0
+	for _, r := range ratios {
+		total += r[1] - r[0]
+	}
+
+	steps := int(math.Max(2, math.Sqrt(float64(points))))
+
+	var front [][]float64
+
+	for _, r := range ratios {
+		n := steps / len(ratios)
+		if total > 0 && r[1] > r[0] {
+			n = int(math.Max(2, float64(steps)*(r[1]-r[0])/total))
+		}
+
+		for _, ratio := range linspace(r[0], r[1], int(math.Max(1, float64(n)))) {
+			for _, f3 := range linspace(0, 1, steps) {
+				rest := 1 - f3*f3
+				front = append(front, []float64{math.Sqrt(ratio * rest), math.Sqrt((1 - ratio) * rest), f3})
+			}
+		}
+	}
+
+	return nondominatedFront(front)
+}
+
+// =============================================================================
+// C-DTLZ
+// =============================================================================
+
+// C1DTLZ1Constraints returns the constraint of C1-DTLZ1, which removes the
+// objective space far from the DTLZ1 front:
+// 1 - f_M/0.6 - Σ_(i<M) f_i/0.5 >= 0.
+func C1DTLZ1Constraints(objectives int) ConstraintFunction {
+	dtlz1 := DTLZ1(objectives)
+
+	return func(x []float64) []float64 {
+		f := dtlz1(x)
+
+		c := 1 - f[len(f)-1]/0.6
+		for _, v := range f[:len(f)-1] {
+			c -= v / 0.5
+		}
+
+		return []float64{c}
+	}
+}
+
+// C1DTLZ3Constraints returns the constraint of C1-DTLZ3, an infeasible band
+// of radii (4, r) in front of the DTLZ3 local fronts:
+// (‖f‖² - 16)(‖f‖² - r²) >= 0 with r = 9 (M <= 3), 12.5 (M <= 8) or 15.
+func C1DTLZ3Constraints(objectives int) ConstraintFunction {
+	dtlz3 := DTLZ3(objectives)
+
+	r := 15.0
+	switch {
+	case objectives <= 3:
+		r = 9
+	case objectives <= 8:
+		r = 12.5
+	}
+
+	return func(x []float64) []float64 {
+		f := dtlz3(x)
+		squared := dot(f, f)
+
+		return []float64{(squared - 16) * (squared - r*r)}
+	}
+}
+
+// C2DTLZ2Constraints returns the constraint of C2-DTLZ2, which leaves only
+// the regions of the DTLZ2 front around the corners and the center feasible.
+func C2DTLZ2Constraints(objectives int) ConstraintFunction {
+	dtlz2 := DTLZ2(objectives)
+
+	return func(x []float64) []float64 {
+		return []float64{c2dtlz2Constraint(dtlz2(x))}
+	}
+}
+
+// c2dtlz2Constraint returns the C2-DTLZ2 constraint of objective vector f,
+// with radius 0.4 for three objectives and 0.5 otherwise.
+func c2dtlz2Constraint(f []float64) float64 {
+	m := len(f)
+
+	r := 0.5
+	if m == 3 {
+		r = 0.4
+	}
+
+	squared := dot(f, f)
+
+	// Sphere of radius r around the corner e_i
+	closest := math.Inf(1)
+	for _, v := range f {
+		closest = math.Min(closest, squared-2*v+1-r*r)
+	}
+
+	// Sphere of radius r around the center of the front
+	center := -r * r
+	for _, v := range f {
+		d := v - 1/math.Sqrt(float64(m))
+		center += d * d
+	}
+
+	return -math.Min(closest, center)
+}
+
+// C3DTLZ1Constraints returns the M constraints of C3-DTLZ1, which cut off
+// the DTLZ1 front: Σ_(i≠j) f_i + f_j/0.5 - 1 >= 0 for every j.
+func C3DTLZ1Constraints(objectives int) ConstraintFunction {
+	dtlz1 := DTLZ1(objectives)
+
+	return func(x []float64) []float64 {
+		return c3dtlz1Constraints(dtlz1(x))
+	}
+}
+
+// c3dtlz1Constraints returns the C3-DTLZ1 constraints of objective vector f.
+func c3dtlz1Constraints(f []float64) []float64 {
+	sum := 0.0
+	for _, v := range f {
+		sum += v
+	}
+
+	c := make([]float64, len(f))
+	for j, v := range f {
+		c[j] = sum - v + v/0.5 - 1
+	}
+
+	return c
+}
+
+// C3DTLZ4Constraints returns the M constraints of C3-DTLZ4, which cut off
+// the DTLZ4 front: f_j²/4 + Σ_(i≠j) f_i² - 1 >= 0 for every j.
+func C3DTLZ4Constraints(objectives int) ConstraintFunction {
+	dtlz4 := DTLZ4(objectives)
+
+	return func(x []float64) []float64 {
+		return c3dtlz4Constraints(dtlz4(x))
+	}
+}
+
+// c3dtlz4Constraints returns the C3-DTLZ4 constraints of objective vector f.
+func c3dtlz4Constraints(f []float64) []float64 {
+	squared := dot(f, f)
+
+	c := make([]float64, len(f))
+	for j, v := range f {
+		c[j] = squared - v*v + v*v/4 - 1
+	}
+
+	return c
+}
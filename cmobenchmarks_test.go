@@ -0,0 +1,239 @@
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for cmobenchmarks.go - Constrained multi-objective benchmark problems
+// =============================================================================
+
+// cfOptimalPosition returns a random Pareto-optimal position of a CF problem:
+// a feasible x_1 (and x_2) and the optimal distance variables.
+func cfOptimalPosition(problem MultiObjectiveProblem, rng *rand.Rand) []float64 {
+	n := problem.ProblemSize
+	x := make([]float64, n)
+
+	// pick returns a uniform value from the union of intervals
+	pick := func(intervals ...[2]float64) float64 {
+		total := 0.0
+		for _, r := range intervals {
+			total += r[1] - r[0]
+		}
+
+		u := rng.Float64() * total
+		for _, r := range intervals {
+			if u <= r[1]-r[0] {
+				return r[0] + u
+			}
+
+			u -= r[1] - r[0]
+		}
+
+		return intervals[len(intervals)-1][1]
+	}
+
+	angle := func(j int) float64 {
+		return 6*math.Pi*x[0] + float64(j)*math.Pi/float64(n)
+	}
+
+	// unit maps a value of [-radius, radius] back to [0, 1]
+	unit := func(v, radius float64) float64 {
+		return (v/radius + 1) / 2
+	}
+
+	// cf67 returns the optimal offsets of x_2 and x_4 in CF6 and CF7
+	cf67 := func() (float64, float64) {
+		r := 1 - x[0]
+		return math.Sqrt(math.Max(0, 0.5*r-r*r)), math.Sqrt(math.Max(0, 0.25*math.Sqrt(r)-0.5*r))
+	}
+
+	switch problem.Name {
+	case "CF1":
+		x[0] = float64(rng.Intn(21)) / 20
+		for j := 2; j <= n; j++ {
+			x[j-1] = math.Pow(x[0], 0.5*(1+3*float64(j-2)/float64(n-2)))
+		}
+	case "CF2":
+		x[0] = pick([2]float64{1.0 / 16, 0.25}, [2]float64{9.0 / 16, 1})
+		for j := 2; j <= n; j++ {
+			x[j-1] = unit(cfWave(j, x[0], n, math.Sin, math.Cos), 1)
+		}
+	case "CF3":
+		x[0] = pick([2]float64{0.5, math.Sqrt(0.5)}, [2]float64{math.Sqrt(0.75), 1})
+		for j := 2; j <= n; j++ {
+			x[j-1] = unit(math.Sin(angle(j)), 2)
+		}
+	case "CF4", "CF5":
+		x[0] = rng.Float64()
+
+		amplitude := 1.0
+		if problem.Name == "CF5" {
+			amplitude = 0.8 * x[0]
+		}
+
+		for j := 2; j <= n; j++ {
+			if problem.Name == "CF4" {
+				x[j-1] = unit(math.Sin(angle(j)), 2)
+			} else {
+				x[j-1] = unit(amplitude*cfWave(j, x[0], n, math.Cos, math.Sin), 2)
+			}
+		}
+
+		offset := math.Max(0, 0.5*x[0]-0.25)
+		if x[0] > 0.75 {
+			offset = 1
+		}
+
+		x[1] = unit(amplitude*math.Sin(angle(2))+offset, 2)
+	case "CF6", "CF7":
+		x[0] = rng.Float64()
+
+		amplitude := 1.0
+		if problem.Name == "CF6" {
+			amplitude = 0.8 * x[0]
+		}
+
+		for j := 2; j <= n; j++ {
+			x[j-1] = unit(amplitude*cfWave(j, x[0], n, math.Cos, math.Sin), 2)
+		}
+
+		y2, y4 := cf67()
+		x[1] = unit(amplitude*math.Sin(angle(2))+y2, 2)
+		x[3] = unit(amplitude*math.Sin(angle(4))+y4, 2)
+	case "CF8", "CF9", "CF10":
+		x[0] = rng.Float64()
+		if problem.Name == "CF8" {
+			x[1] = float64([]int{0, 2, 3, 4, 6}[rng.Intn(5)]) / 6
+		} else {
+			x[1] = pick([2]float64{0, 1.0 / 3}, [2]float64{0.5, 2.0 / 3})
+		}
+
+		for j := 3; j <= n; j++ {
+			x[j-1] = unit(2*x[1]*math.Sin(2*math.Pi*x[0]+float64(j)*math.Pi/float64(n)), 4)
+		}
+	}
+
+	return x
+}
+
+// checkConstrainedFront checks that the given Pareto-optimal solutions lie on
+// the true front and that no feasible random position beats it.
+func checkConstrainedFront(t *testing.T, problem MultiObjectiveProblem, optimal []*ParetoSolution, rng *rand.Rand) {
+	t.Helper()
+
+	front := problem.TrueFront(1000)
+	if len(front) < 10 {
+		t.Fatalf("True front has only %d points", len(front))
+	}
+
+	ideal, nadir := IdealPoint(front), NadirPoint(front)
+	normalizedFront := NormalizeFront(front, ideal, nadir)
+
+	if len(optimal) > 0 {
+		if gd := CalculateGD(NormalizeFront(optimal, ideal, nadir), normalizedFront); gd > 0.05 {
+			t.Errorf("Pareto-optimal solutions have GD %.4f to the true front", gd)
+		}
+	}
+
+	for i := 0; i < 500; i++ {
+		x := make([]float64, problem.ProblemSize)
+		for j := range x {
+			x[j] = rng.Float64()
+		}
+
+		if TotalViolation(problem.Constraints(x)) > 0 {
+			continue
+		}
+
+		r := NormalizeFront([]*ParetoSolution{{ObjectiveValues: problem.Func(x)}}, ideal, nadir)
+		if eps := CalculateAdditiveEpsilon(normalizedFront, r); eps > 0.05 {
+			t.Fatalf("Feasible solution %v lies %.4f beyond the true front", r[0].ObjectiveValues, eps)
+		}
+	}
+}
+
+func TestCFProblems(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, problem := range CFProblems() {
+		t.Run(problem.Name, func(t *testing.T) {
+			var optimal []*ParetoSolution
+
+			for i := 0; i < 50; i++ {
+				x := cfOptimalPosition(problem, rng)
+
+				f := problem.Func(x)
+				if len(f) != problem.Objectives {
+					t.Fatalf("Expected %d objectives, got %d", problem.Objectives, len(f))
+				}
+
+				if v := TotalViolation(problem.Constraints(x)); v > 1e-9 {
+					t.Fatalf("Pareto-optimal position %v violates the constraints by %g", x, v)
+				}
+
+				optimal = append(optimal, &ParetoSolution{ObjectiveValues: f})
+			}
+
+			checkConstrainedFront(t, problem, optimal, rng)
+		})
+	}
+}
+
+func TestCDTLZProblems(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	for _, objectives := range []int{2, 3} {
+		for _, problem := range CDTLZProblems(objectives) {
+			t.Run(problem.Name, func(t *testing.T) {
+				// The C1 and C2 fronts are (parts of) the DTLZ fronts
+				var optimal []*ParetoSolution
+
+				if problem.Name[:2] != "C3" {
+					base := problem
+					base.Name = problem.Name[3:]
+
+					for i := 0; i < 50; i++ {
+						x := paretoOptimalPosition(base, rng)
+						if TotalViolation(problem.Constraints(x)) == 0 {
+							optimal = append(optimal, &ParetoSolution{ObjectiveValues: problem.Func(x)})
+						}
+					}
+
+					if len(optimal) < 5 {
+						t.Fatalf("Only %d of 50 Pareto-optimal positions are feasible", len(optimal))
+					}
+				}
+
+				checkConstrainedFront(t, problem, optimal, rng)
+			})
+		}
+	}
+}
+
+func TestC3DTLZFrontsLieOnConstraints(t *testing.T) {
+	constraints := map[string]func([]float64) []float64{
+		"C3-DTLZ1": c3dtlz1Constraints,
+		"C3-DTLZ4": c3dtlz4Constraints,
+	}
+
+	for _, problem := range CDTLZProblems(3) {
+		c, ok := constraints[problem.Name]
+		if !ok {
+			continue
+		}
+
+		for _, s := range problem.TrueFront(200) {
+			low := math.Inf(1)
+			for _, v := range c(s.ObjectiveValues) {
+				low = math.Min(low, v)
+			}
+
+			if math.Abs(low) > 1e-9 {
+				t.Fatalf("%s front point %v is %g from the nearest constraint", problem.Name, s.ObjectiveValues, low)
+			}
+		}
+	}
+}
@@ -153,7 +153,7 @@ func (cr *ComparisonRunner) Compare(
 			config.MaxIterations = cr.MaxIterations
 
 			start := time.Now()
-			result, err := Optimize(config)
+			result, err := OptimizeVariant(variant, config)
 			elapsed := time.Since(start).Seconds()
 
 			if err != nil {
@@ -26,9 +26,41 @@ func NewDefaultConfig() *Config {
 		SearchRange:     0, // Will be auto-calculated
 		EnlargeFactor:   1.05,
 		ReductionFactor: 0.95,
+		// Restart defaults
+		RestartDiversity:        1e-4,
+		RestartPopulationFactor: 2.0,
+		// Surrogate defaults
+		SurrogateFraction: 0.1,
+		LCBKappa:          2.0,
+		// Local search defaults
+		LocalSearchStep: 0.01,
+		// MOEA/D defaults
+		PBITheta:                5.0,
+		NeighborhoodProbability: 0.9,
 	}
 }
 
+// NewCMAESConfig creates a default configuration for the CMA-ES variant.
+// The Mayfly population sizes set the evaluation budget of an iteration.
+// You must set ObjectiveFunc, ProblemSize, LowerBound, and UpperBound.
+func NewCMAESConfig() *Config {
+	config := NewDefaultConfig()
+	config.CMASigma = 0.3 // Initial step size relative to the search range
+
+	return config
+}
+
+// NewIPOPCMAESConfig creates a default configuration for CMA-ES with IPOP
+// restarts, which double the population size on every restart.
+// You must set ObjectiveFunc, ProblemSize, LowerBound, and UpperBound.
+func NewIPOPCMAESConfig() *Config {
+	config := NewCMAESConfig()
+	config.RestartStrategy = RestartStrategyIPOP
+	config.RestartPopulationFactor = 2
+
+	return config
+}
+
 // NewDESMAConfig creates a default configuration for the DESMA variant.
 // You must set ObjectiveFunc, ProblemSize, LowerBound, and UpperBound.
 func NewDESMAConfig() *Config {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 )
 
 // ConfigPreset represents predefined configurations for common problem types.
@@ -22,15 +23,16 @@ const (
 	PresetMultiObjective    ConfigPreset = "multi_objective"
 )
 
-// LoadConfigFromFile loads a Config from a JSON file.
-// Note: ObjectiveFunc and Rand must be set separately as they cannot be serialized.
+// LoadConfigFromFile loads a Config from a JSON file. Fields missing from
+// the file keep the values of NewDefaultConfig.
+// Note: ObjectiveFunc, Gradient and Rand must be set separately as they cannot be serialized.
 func LoadConfigFromFile(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	config := &Config{}
+	config := NewDefaultConfig()
 	if err := json.Unmarshal(data, config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
@@ -44,7 +46,7 @@ func LoadConfigFromFile(path string) (*Config, error) {
 }
 
 // SaveConfigToFile saves a Config to a JSON file.
-// Note: ObjectiveFunc and Rand are not saved as they cannot be serialized.
+// Note: ObjectiveFunc, Gradient and Rand are not saved as they cannot be serialized.
 func SaveConfigToFile(config *Config, path string) error {
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
@@ -107,6 +109,46 @@ func ValidateConfig(config *Config) error {
 		return fmt.Errorf("mu (mutation rate) should be in [0,1] (got %f)", config.Mu)
 	}
 
+	if err := validateCrossoverConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateMutationConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateSelectionConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateRestartConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateNichingConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateMultiObjectiveConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateSurrogateConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateLocalSearchConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateCMAESConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateAdaptationConfig(config); err != nil {
+		return err
+	}
+
 	// Validate variant-specific parameters
 	if config.UseDESMA {
 		if config.EliteCount < 0 {
@@ -187,33 +229,8 @@ func ValidateConfig(config *Config) error {
 	}
 
 	// Check for conflicting variants
-	activeVariants := 0
-	if config.UseDESMA {
-		activeVariants++
-	}
-
-	if config.UseOLCE {
-		activeVariants++
-	}
-
-	if config.UseEOBBMA {
-		activeVariants++
-	}
-
-	if config.UseMPMA {
-		activeVariants++
-	}
-
-	if config.UseGSASMA {
-		activeVariants++
-	}
-
-	if config.UseAOBLMOA {
-		activeVariants++
-	}
-
-	if activeVariants > 1 {
-		return fmt.Errorf("multiple algorithm variants enabled (only one can be active at a time)")
+	if err := validateVariantCombination(config); err != nil {
+		return err
 	}
 
 	return nil
@@ -266,14 +283,100 @@ func NewPresetConfig(preset ConfigPreset) (*Config, error) {
 		config = NewAOBLMOAConfig()
 
 	default:
-		return nil, fmt.Errorf("unknown preset: %s", preset)
+		customPresetsMu.RLock()
+		custom, ok := customPresets[preset]
+		customPresetsMu.RUnlock()
+
+		if !ok {
+			return nil, fmt.Errorf("unknown preset: %s", preset)
+		}
+
+		variant := NewVariant(custom.variant)
+		if variant == nil {
+			return nil, fmt.Errorf("preset %s refers to unknown variant: %s", preset, custom.variant)
+		}
+
+		config = variant.GetConfig()
 	}
 
 	return config, nil
 }
 
-// ListPresets returns all available configuration presets with descriptions.
+// customPreset maps a user-defined preset to a registered variant.
+type customPreset struct {
+	variant     string
+	description string
+}
+
+var (
+	customPresets   = map[ConfigPreset]customPreset{}
+	customPresetsMu sync.RWMutex
+)
+
+// RegisterPreset adds a preset that returns the default configuration of the
+// named variant (typically one added with RegisterVariant).
+// Returns an error if the preset already exists or the variant is unknown.
+func RegisterPreset(preset ConfigPreset, variantName, description string) error {
+	if preset == "" {
+		return fmt.Errorf("preset name cannot be empty")
+	}
+
+	if _, builtin := builtinPresets()[preset]; builtin {
+		return fmt.Errorf("built-in preset %s cannot be replaced", preset)
+	}
+
+	variant := NewVariant(variantName)
+	if variant == nil {
+		return fmt.Errorf("unknown variant: %s", variantName)
+	}
+
+	if description == "" {
+		description = variant.Name() + " - " + variant.Description()
+	}
+
+	customPresetsMu.Lock()
+	defer customPresetsMu.Unlock()
+
+	if _, exists := customPresets[preset]; exists {
+		return fmt.Errorf("preset %s is already registered", preset)
+	}
+
+	customPresets[preset] = customPreset{variant: variantName, description: description}
+
+	return nil
+}
+
+// UnregisterPreset removes a preset added with RegisterPreset.
+func UnregisterPreset(preset ConfigPreset) error {
+	customPresetsMu.Lock()
+	defer customPresetsMu.Unlock()
+
+	if _, exists := customPresets[preset]; !exists {
+		return fmt.Errorf("preset %s is not registered", preset)
+	}
+
+	delete(customPresets, preset)
+
+	return nil
+}
+
+// ListPresets returns all available configuration presets with descriptions,
+// including presets added with RegisterPreset.
 func ListPresets() map[ConfigPreset]string {
+	presets := builtinPresets()
+
+	customPresetsMu.RLock()
+	defer customPresetsMu.RUnlock()
+
+	for preset, custom := range customPresets {
+		presets[preset] = custom.description
+	}
+
+	return presets
+}
+
+// builtinPresets returns the presets shipped with the package.
+func builtinPresets() map[ConfigPreset]string {
 	return map[ConfigPreset]string{
 		PresetUnimodal:          "Standard MA - For unimodal problems with single optimum",
 		PresetMultimodal:        "DESMA - For multimodal problems with several local optima",
@@ -406,11 +509,78 @@ func ExportConfigTemplate(path string, variant string) error {
 	fmt.Fprintf(file, "  \"nm\": %d,\n", config.NM)
 	fmt.Fprintf(file, "  \"mu\": %f,\n", config.Mu)
 	fmt.Fprintf(file, "\n")
+	fmt.Fprintf(file, "  // Crossover operator: arithmetic, sbx, blx, de, undx, spx (0 = default parameter)\n")
+	fmt.Fprintf(file, "  \"crossover_type\": \"%s\",\n", config.CrossoverType)
+	fmt.Fprintf(file, "  \"crossover_eta\": %f,\n", config.CrossoverEta)
+	fmt.Fprintf(file, "  \"crossover_alpha\": %f,\n", config.CrossoverAlpha)
+	fmt.Fprintf(file, "  \"de_scale_factor\": %f,\n", config.DEScaleFactor)
+	fmt.Fprintf(file, "  \"de_crossover_rate\": %f,\n", config.DECrossoverRate)
+	fmt.Fprintf(file, "  \"crossover_parents\": %d,\n", config.CrossoverParents)
+	fmt.Fprintf(file, "\n")
+	fmt.Fprintf(file, "  // Mutation operator: gaussian, cauchy, hybrid, polynomial, nonuniform, selfadaptive (empty = variant default)\n")
+	fmt.Fprintf(file, "  \"mutation_type\": \"%s\",\n", config.MutationType)
+	fmt.Fprintf(file, "  \"mutation_eta\": %f,\n", config.MutationEta)
+	fmt.Fprintf(file, "  \"nonuniform_shape\": %f,\n", config.NonUniformShape)
+	fmt.Fprintf(file, "  \"mutation_sigma\": %f,\n", config.MutationSigma)
+	fmt.Fprintf(file, "  \"mutation_tau\": %f,\n", config.MutationTau)
+	fmt.Fprintf(file, "\n")
+	fmt.Fprintf(file, "  // Parent selection: rank, tournament, proportional, sus, elite, assortative, disassortative\n")
+	fmt.Fprintf(file, "  // Mutation parent: random, tournament, best, population\n")
+	fmt.Fprintf(file, "  \"parent_selection\": \"%s\",\n", config.ParentSelection)
+	fmt.Fprintf(file, "  \"mutation_parent_selection\": \"%s\",\n", config.MutationParentSelection)
+	fmt.Fprintf(file, "  \"tournament_size\": %d,\n", config.TournamentSize)
+	fmt.Fprintf(file, "\n")
+	fmt.Fprintf(file, "  // Restarts: none, restart, ipop, bipop (0 = default parameter)\n")
+	fmt.Fprintf(file, "  \"restart_strategy\": \"%s\",\n", config.RestartStrategy)
+	fmt.Fprintf(file, "  \"restart_stagnation\": %d,\n", config.RestartStagnation)
+	fmt.Fprintf(file, "  \"restart_diversity\": %g,\n", config.RestartDiversity)
+	fmt.Fprintf(file, "  \"restart_population_factor\": %f,\n", config.RestartPopulationFactor)
+	fmt.Fprintf(file, "  \"max_restarts\": %d,\n", config.MaxRestarts)
+	fmt.Fprintf(file, "  \"restart_max_population\": %d,\n", config.RestartMaxPopulation)
+	fmt.Fprintf(file, "\n")
+	fmt.Fprintf(file, "  // Niching: none, clearing, sharing, speciation (0 = default parameter)\n")
+	fmt.Fprintf(file, "  \"niching_method\": \"%s\",\n", config.NichingMethod)
+	fmt.Fprintf(file, "  \"niche_radius\": %f,\n", config.NicheRadius)
+	fmt.Fprintf(file, "  \"niche_capacity\": %d,\n", config.NicheCapacity)
+	fmt.Fprintf(file, "  \"sharing_alpha\": %f,\n", config.SharingAlpha)
+	fmt.Fprintf(file, "  \"max_optima\": %d,\n", config.MaxOptima)
+	fmt.Fprintf(file, "\n")
+	fmt.Fprintf(file, "  // OptimizeMulti mode: nsga2, nsga3, moead; scalarization: tchebycheff, weightedsum, pbi (0 = default parameter)\n")
+	fmt.Fprintf(file, "  \"multi_objective_mode\": \"%s\",\n", config.MultiObjectiveMode)
+	fmt.Fprintf(file, "  \"reference_divisions\": %d,\n", config.ReferenceDivisions)
+	fmt.Fprintf(file, "  \"scalarization\": \"%s\",\n", config.Scalarization)
+	fmt.Fprintf(file, "  \"pbi_theta\": %f,\n", config.PBITheta)
+	fmt.Fprintf(file, "  \"neighborhood_size\": %d,\n", config.NeighborhoodSize)
+	fmt.Fprintf(file, "  \"neighborhood_probability\": %f,\n", config.NeighborhoodProbability)
+	fmt.Fprintf(file, "  \"max_replacements\": %d,\n", config.MaxReplacements)
+	fmt.Fprintf(file, "\n")
+	fmt.Fprintf(file, "  // Surrogate: rbf, kriging; infill: ei, lcb (0 = default parameter)\n")
+	fmt.Fprintf(file, "  \"use_surrogate\": %t,\n", config.UseSurrogate)
+	fmt.Fprintf(file, "  \"surrogate_model\": \"%s\",\n", config.SurrogateModel)
+	fmt.Fprintf(file, "  \"infill_criterion\": \"%s\",\n", config.InfillCriterion)
+	fmt.Fprintf(file, "  \"surrogate_samples\": %d,\n", config.SurrogateSamples)
+	fmt.Fprintf(file, "  \"surrogate_fraction\": %f,\n", config.SurrogateFraction)
+	fmt.Fprintf(file, "  \"lcb_kappa\": %f,\n", config.LCBKappa)
+	fmt.Fprintf(file, "\n")
+	fmt.Fprintf(file, "  // Local search: none, nelder-mead, hooke-jeeves, lbfgsb (0 = default parameter)\n")
+	fmt.Fprintf(file, "  \"local_search\": \"%s\",\n", config.LocalSearch)
+	fmt.Fprintf(file, "  \"local_search_interval\": %d,\n", config.LocalSearchInterval)
+	fmt.Fprintf(file, "  \"local_search_evals\": %d,\n", config.LocalSearchEvals)
+	fmt.Fprintf(file, "  \"local_search_step\": %f,\n", config.LocalSearchStep)
+	fmt.Fprintf(file, "\n")
+	fmt.Fprintf(file, "  // CMA-ES variant: population size and initial step size (0 = default parameter)\n")
+	fmt.Fprintf(file, "  \"cma_population\": %d,\n", config.CMAPopulation)
+	fmt.Fprintf(file, "  \"cma_sigma\": %f,\n", config.CMASigma)
+	fmt.Fprintf(file, "\n")
+	fmt.Fprintf(file, "  // Success-history adaptation of a1, a2, a3, beta, dance and fl (0 = default parameter)\n")
+	fmt.Fprintf(file, "  \"adaptive_parameters\": %t,\n", config.AdaptiveParameters)
+	fmt.Fprintf(file, "  \"adaptation_memory\": %d,\n", config.AdaptationMemory)
+	fmt.Fprintf(file, "\n")
 	fmt.Fprintf(file, "  // Velocity limits (0 = auto-calculated)\n")
 	fmt.Fprintf(file, "  \"vel_max\": %f,\n", config.VelMax)
 	fmt.Fprintf(file, "  \"vel_min\": %f,\n", config.VelMin)
 	fmt.Fprintf(file, "\n")
-	fmt.Fprintf(file, "  // Variant flags (only one should be true)\n")
+	fmt.Fprintf(file, "  // Variant flags (at most one of eobbma, mpma, aoblmoa; desma, olce, gsasma combine freely)\n")
 	fmt.Fprintf(file, "  \"use_desma\": %t,\n", config.UseDESMA)
 	fmt.Fprintf(file, "  \"use_olce\": %t,\n", config.UseOLCE)
 	fmt.Fprintf(file, "  \"use_eobbma\": %t,\n", config.UseEOBBMA)
@@ -3,6 +3,8 @@ package mayfly
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -62,9 +64,10 @@ func TestSaveAndLoadConfig(t *testing.T) {
 
 func TestValidateConfig(t *testing.T) {
 	tests := []struct {
-		config  *Config
-		name    string
-		wantErr bool
+		config      *Config
+		name        string
+		wantErr     bool
+		errContains string
 	}{
 		{
 			name:    "Nil config",
@@ -127,7 +130,7 @@ func TestValidateConfig(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "Multiple variants enabled",
+			name: "Conflicting movement variants",
 			config: &Config{
 				ProblemSize:   10,
 				LowerBound:    -10,
@@ -142,10 +145,37 @@ func TestValidateConfig(t *testing.T) {
 				A3:            1.5,
 				Beta:          2.0,
 				Mu:            0.01,
-				UseDESMA:      true,
-				UseOLCE:       true, // Both enabled - invalid
+				UseEOBBMA:     true,
+				LevyAlpha:     1.5,
+				LevyBeta:      1.0,
+				UseMPMA:       true, // Both replace the movement - invalid
+				GravityType:   "linear",
 			},
-			wantErr: true,
+			wantErr:     true,
+			errContains: "replace the movement operator",
+		},
+		{
+			name: "Combined stage variants",
+			config: &Config{
+				ProblemSize:     10,
+				LowerBound:      -10,
+				UpperBound:      10,
+				MaxIterations:   100,
+				NPop:            20,
+				NPopF:           20,
+				G:               0.8,
+				GDamp:           1.0,
+				A1:              1.0,
+				A2:              1.5,
+				A3:              1.5,
+				Beta:            2.0,
+				Mu:              0.01,
+				UseDESMA:        true,
+				EnlargeFactor:   1.05,
+				ReductionFactor: 0.95,
+				UseOLCE:         true, // Stages combine freely
+			},
+			wantErr: false,
 		},
 		{
 			name: "Invalid MPMA gravity type",
@@ -176,6 +206,10 @@ func TestValidateConfig(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
 			}
+
+			if err != nil && !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("ValidateConfig() error = %v, expected it to mention %q", err, tt.errContains)
+			}
 		})
 	}
 }
@@ -353,6 +387,62 @@ func TestInvalidPreset(t *testing.T) {
 	}
 }
 
+func TestLoadMinimalConfigFile(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "minimal_config.json")
+
+	data := `{"problem_size": 5, "lower_bound": -10, "upper_bound": 10, "max_iterations": 50, "use_surrogate": true}`
+	if err := os.WriteFile(tmpFile, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	config, err := LoadConfigFromFile(tmpFile)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile failed: %v", err)
+	}
+
+	if config.ProblemSize != 5 || config.MaxIterations != 50 || !config.UseSurrogate {
+		t.Errorf("Fields from the file were not loaded: %+v", config)
+	}
+
+	// Omitted fields keep their defaults, including those where 0 is a valid setting
+	defaults := NewDefaultConfig()
+	if config.NPop != defaults.NPop || config.PBITheta != defaults.PBITheta ||
+		config.NeighborhoodProbability != defaults.NeighborhoodProbability ||
+		config.LCBKappa != defaults.LCBKappa || config.SurrogateFraction != defaults.SurrogateFraction ||
+		config.LocalSearchStep != defaults.LocalSearchStep || config.RestartDiversity != defaults.RestartDiversity ||
+		config.RestartPopulationFactor != defaults.RestartPopulationFactor {
+		t.Errorf("Omitted fields lost their defaults: %+v", config)
+	}
+
+	// The loaded config round-trips through SaveConfigToFile
+	if err := SaveConfigToFile(config, tmpFile); err != nil {
+		t.Fatalf("SaveConfigToFile failed: %v", err)
+	}
+
+	again, err := LoadConfigFromFile(tmpFile)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(again, config) {
+		t.Errorf("Round trip changed the config: %+v, expected %+v", again, config)
+	}
+
+	// An explicit zero is kept
+	data = `{"problem_size": 5, "lower_bound": -10, "upper_bound": 10, "pbi_theta": 0, "lcb_kappa": 0}`
+	if err := os.WriteFile(tmpFile, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	if config, err = LoadConfigFromFile(tmpFile); err != nil {
+		t.Fatalf("LoadConfigFromFile failed: %v", err)
+	}
+
+	if config.PBITheta != 0 || config.LCBKappa != 0 {
+		t.Errorf("Explicit zeros were replaced: PBITheta %v, LCBKappa %v", config.PBITheta, config.LCBKappa)
+	}
+}
+
 func TestLoadInvalidConfigFile(t *testing.T) {
 	// Test loading non-existent file
 	_, err := LoadConfigFromFile(filepath.Join(os.TempDir(), "nonexistent_file.json"))
@@ -0,0 +1,386 @@
+// Package mayfly - Crossover Operators
+//
+// Implements the selectable mating operators used by the pipeline.
+//
+// Config.CrossoverType selects the operator:
+//   - "arithmetic" (default): per-dimension blend with a uniform weight
+//   - "sbx": simulated binary crossover (Deb & Agrawal, 1995)
+//   - "blx": BLX-α blend crossover (Eshelman & Schaffer, 1993)
+//   - "de": DE/rand/1 mutant vector with binomial crossover (Storn & Price, 1997)
+//   - "undx": unimodal normal distribution crossover (Ono & Kobayashi, 1997)
+//   - "spx": simplex crossover (Tsutsui et al., 1999)
+//
+// DE, UNDX and SPX are multi-parent operators. Besides the paired male and
+// female they draw additional parents uniformly from both populations.
+// UNDX and SPX are rotation invariant.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Crossover type names accepted by Config.CrossoverType.
+const (
+	CrossoverTypeArithmetic = "arithmetic"
+	CrossoverTypeSBX        = "sbx"
+	CrossoverTypeBLX        = "blx"
+	CrossoverTypeDE         = "de"
+	CrossoverTypeUNDX       = "undx"
+	CrossoverTypeSPX        = "spx"
+)
+
+// Operator settings for an unset CrossoverEta, CrossoverAlpha, DEScaleFactor,
+// DECrossoverRate or CrossoverParents, as commonly used with SBX, BLX-α, DE
+// and SPX.
+const (
+	defaultCrossoverEta     = 20.0 // SBX distribution index
+	defaultCrossoverAlpha   = 0.5  // BLX-α extension
+	defaultDEScaleFactor    = 0.5  // DE differential weight F
+	defaultDECrossoverRate  = 0.9  // DE binomial crossover rate CR
+	defaultCrossoverParents = 3    // SPX parent count
+)
+
+// UNDX standard deviations recommended by Kita et al. (1999).
+const (
+	undxSigmaXi  = 0.5
+	undxSigmaEta = 0.35
+)
+
+// NewCrossoverOperator returns the CrossoverOperator for a Config.CrossoverType value.
+// An empty name selects the arithmetic crossover.
+func NewCrossoverOperator(crossoverType string) (CrossoverOperator, error) {
+	switch crossoverType {
+	case "", CrossoverTypeArithmetic:
+		return ArithmeticCrossover{}, nil
+	case CrossoverTypeSBX:
+		return SBXCrossover{}, nil
+	case CrossoverTypeBLX:
+		return BLXCrossover{}, nil
+	case CrossoverTypeDE:
+		return DECrossover{}, nil
+	case CrossoverTypeUNDX:
+		return UNDXCrossover{}, nil
+	case CrossoverTypeSPX:
+		return SPXCrossover{}, nil
+	default:
+		return nil, fmt.Errorf("unknown crossover type '%s' (expected arithmetic, sbx, blx, de, undx or spx)", crossoverType)
+	}
+}
+
+// validateCrossoverConfig checks the crossover type and its parameters.
+func validateCrossoverConfig(config *Config) error {
+	if _, err := NewCrossoverOperator(config.CrossoverType); err != nil {
+		return err
+	}
+
+	if config.CrossoverEta < 0 {
+		return fmt.Errorf("CrossoverEta must be non-negative, got %v", config.CrossoverEta)
+	}
+
+	if config.CrossoverAlpha < 0 {
+		return fmt.Errorf("CrossoverAlpha must be non-negative, got %v", config.CrossoverAlpha)
+	}
+
+	if config.DEScaleFactor < 0 || config.DEScaleFactor > 2 {
+		return fmt.Errorf("DEScaleFactor must be in [0, 2], got %v", config.DEScaleFactor)
+	}
+
+	if config.DECrossoverRate < 0 || config.DECrossoverRate > 1 {
+		return fmt.Errorf("DECrossoverRate must be in [0, 1], got %v", config.DECrossoverRate)
+	}
+
+	if config.CrossoverParents != 0 && config.CrossoverParents < 2 {
+		return fmt.Errorf("CrossoverParents must be at least 2, got %d", config.CrossoverParents)
+	}
+
+	return nil
+}
+
+// orDefault returns value, or def if value is zero. Optional Config fields
+// whose zero value is no useful setting are read through it; fields for
+// which zero is meaningful get their defaults in NewDefaultConfig instead.
+func orDefault(value, def float64) float64 {
+	if value == 0 {
+		return def
+	}
+
+	return value
+}
+
+// clampVec limits every element of x to [lowerBound, upperBound].
+func clampVec(x []float64, lowerBound, upperBound float64) {
+	maxVec(x, lowerBound)
+	minVec(x, upperBound)
+}
+
+// randomPopulationPosition returns the position of a mayfly chosen uniformly
+// from the male and female populations.
+func randomPopulationPosition(s *State) []float64 {
+	i := s.Rand.Intn(len(s.Males) + len(s.Females))
+	if i < len(s.Males) {
+		return s.Males[i].Position
+	}
+
+	return s.Females[i-len(s.Males)].Position
+}
+
+// CrossoverSBX performs simulated binary crossover with distribution index eta.
+// Larger eta values produce offspring closer to the parents.
+func CrossoverSBX(x1, x2 []float64, eta, lowerBound, upperBound float64, rng *rand.Rand) ([]float64, []float64) {
+	size := len(x1)
+	off1 := make([]float64, size)
+	off2 := make([]float64, size)
+
+	for i := 0; i < size; i++ {
+		u := unifrnd(0, 1, rng)
+
+		var beta float64
+		if u <= 0.5 {
+			beta = math.Pow(2*u, 1/(eta+1))
+		} else {
+			beta = math.Pow(1/(2*(1-u)), 1/(eta+1))
+		}
+
+		off1[i] = 0.5 * ((1+beta)*x1[i] + (1-beta)*x2[i])
+		off2[i] = 0.5 * ((1-beta)*x1[i] + (1+beta)*x2[i])
+	}
+
+	clampVec(off1, lowerBound, upperBound)
+	clampVec(off2, lowerBound, upperBound)
+
+	return off1, off2
+}
+
+// CrossoverBLX performs BLX-α crossover. Each offspring gene is drawn
+// uniformly from the parents' interval extended by alpha times its width
+// on both sides.
+func CrossoverBLX(x1, x2 []float64, alpha, lowerBound, upperBound float64, rng *rand.Rand) ([]float64, []float64) {
+	size := len(x1)
+	off1 := make([]float64, size)
+	off2 := make([]float64, size)
+
+	for i := 0; i < size; i++ {
+		lo := math.Min(x1[i], x2[i])
+		hi := math.Max(x1[i], x2[i])
+		d := alpha * (hi - lo)
+
+		off1[i] = unifrnd(lo-d, hi+d, rng)
+		off2[i] = unifrnd(lo-d, hi+d, rng)
+	}
+
+	clampVec(off1, lowerBound, upperBound)
+	clampVec(off2, lowerBound, upperBound)
+
+	return off1, off2
+}
+
+// CrossoverDE builds the DE/rand/1 mutant r1 + f*(r2 - r3) and combines it
+// with target by binomial crossover with rate cr. At least one gene is
+// always taken from the mutant.
+func CrossoverDE(target, r1, r2, r3 []float64, f, cr, lowerBound, upperBound float64, rng *rand.Rand) []float64 {
+	size := len(target)
+	trial := make([]float64, size)
+	jRand := rng.Intn(size)
+
+	for j := 0; j < size; j++ {
+		if j == jRand || rng.Float64() < cr {
+			trial[j] = r1[j] + f*(r2[j]-r3[j])
+		} else {
+			trial[j] = target[j]
+		}
+	}
+
+	clampVec(trial, lowerBound, upperBound)
+
+	return trial
+}
+
+// CrossoverUNDX performs unimodal normal distribution crossover. Offspring
+// are sampled around the midpoint of x1 and x2, along the line through them
+// and, scaled by the distance of x3 to that line, in the orthogonal
+// subspace. The two offspring are mirrored around the midpoint.
+func CrossoverUNDX(x1, x2, x3 []float64, lowerBound, upperBound float64, rng *rand.Rand) ([]float64, []float64) {
+	size := len(x1)
+	mid := make([]float64, size)
+	d := make([]float64, size)
+
+	for i := 0; i < size; i++ {
+		mid[i] = 0.5 * (x1[i] + x2[i])
+		d[i] = x2[i] - x1[i]
+	}
+
+	dNorm := norm(d)
+
+	// Distance of the third parent to the primary search line
+	v := make([]float64, size)
+	for i := 0; i < size; i++ {
+		v[i] = x3[i] - x1[i]
+	}
+
+	if dNorm > 0 {
+		projection := dot(v, d) / (dNorm * dNorm)
+		for i := 0; i < size; i++ {
+			v[i] -= projection * d[i]
+		}
+	}
+
+	distance := norm(v)
+
+	// Isotropic Gaussian in the subspace orthogonal to d
+	sigmaEta := undxSigmaEta / math.Sqrt(float64(size))
+	eta := make([]float64, size)
+
+	for i := 0; i < size; i++ {
+		eta[i] = distance * sigmaEta * randn(rng)
+	}
+
+	if dNorm > 0 {
+		projection := dot(eta, d) / (dNorm * dNorm)
+		for i := 0; i < size; i++ {
+			eta[i] -= projection * d[i]
+		}
+	}
+
+	xi := undxSigmaXi * randn(rng)
+	off1 := make([]float64, size)
+	off2 := make([]float64, size)
+
+	for i := 0; i < size; i++ {
+		step := xi*d[i] + eta[i]
+		off1[i] = mid[i] + step
+		off2[i] = mid[i] - step
+	}
+
+	clampVec(off1, lowerBound, upperBound)
+	clampVec(off2, lowerBound, upperBound)
+
+	return off1, off2
+}
+
+// CrossoverSPX performs simplex crossover. The simplex spanned by the parents
+// is expanded around its centroid by sqrt(len(parents)+1) and one offspring
+// is sampled uniformly from it.
+func CrossoverSPX(parents [][]float64, lowerBound, upperBound float64, rng *rand.Rand) []float64 {
+	np := len(parents)
+	size := len(parents[0])
+	epsilon := math.Sqrt(float64(np + 1))
+
+	center := make([]float64, size)
+	for _, p := range parents {
+		for i := range center {
+			center[i] += p[i] / float64(np)
+		}
+	}
+
+	y := make([][]float64, np)
+	for k, p := range parents {
+		y[k] = make([]float64, size)
+		for i := range center {
+			y[k][i] = center[i] + epsilon*(p[i]-center[i])
+		}
+	}
+
+	c := make([]float64, size)
+	for k := 1; k < np; k++ {
+		r := math.Pow(unifrnd(0, 1, rng), 1/float64(k))
+		for i := range c {
+			c[i] = r * (y[k-1][i] - y[k][i] + c[i])
+		}
+	}
+
+	off := make([]float64, size)
+	for i := range off {
+		off[i] = y[np-1][i] + c[i]
+	}
+
+	clampVec(off, lowerBound, upperBound)
+
+	return off
+}
+
+// dot returns the inner product of a and b.
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+
+	return sum
+}
+
+// norm returns the Euclidean length of a.
+func norm(a []float64) float64 {
+	return math.Sqrt(dot(a, a))
+}
+
+// SBXCrossover is a CrossoverOperator using CrossoverSBX with Config.CrossoverEta.
+type SBXCrossover struct{}
+
+// Cross implements CrossoverOperator.
+func (SBXCrossover) Cross(s *State, p1, p2 []float64) ([]float64, []float64) {
+	eta := orDefault(s.Config.CrossoverEta, defaultCrossoverEta)
+	return CrossoverSBX(p1, p2, eta, s.Config.LowerBound, s.Config.UpperBound, s.Rand)
+}
+
+// BLXCrossover is a CrossoverOperator using CrossoverBLX with Config.CrossoverAlpha.
+type BLXCrossover struct{}
+
+// Cross implements CrossoverOperator.
+func (BLXCrossover) Cross(s *State, p1, p2 []float64) ([]float64, []float64) {
+	alpha := orDefault(s.Config.CrossoverAlpha, defaultCrossoverAlpha)
+	return CrossoverBLX(p1, p2, alpha, s.Config.LowerBound, s.Config.UpperBound, s.Rand)
+}
+
+// DECrossover is a CrossoverOperator using DE/rand/1/bin. Each parent is used
+// as the target vector of one trial, with mutants built from random members
+// of the population.
+type DECrossover struct{}
+
+// Cross implements CrossoverOperator.
+func (DECrossover) Cross(s *State, p1, p2 []float64) ([]float64, []float64) {
+	f := orDefault(s.Config.DEScaleFactor, defaultDEScaleFactor)
+	cr := orDefault(s.Config.DECrossoverRate, defaultDECrossoverRate)
+	lb, ub := s.Config.LowerBound, s.Config.UpperBound
+
+	off1 := CrossoverDE(p1, randomPopulationPosition(s), randomPopulationPosition(s),
+		randomPopulationPosition(s), f, cr, lb, ub, s.Rand)
+	off2 := CrossoverDE(p2, randomPopulationPosition(s), randomPopulationPosition(s),
+		randomPopulationPosition(s), f, cr, lb, ub, s.Rand)
+
+	return off1, off2
+}
+
+// UNDXCrossover is a CrossoverOperator using CrossoverUNDX with a third
+// parent drawn from the population.
+type UNDXCrossover struct{}
+
+// Cross implements CrossoverOperator.
+func (UNDXCrossover) Cross(s *State, p1, p2 []float64) ([]float64, []float64) {
+	return CrossoverUNDX(p1, p2, randomPopulationPosition(s), s.Config.LowerBound, s.Config.UpperBound, s.Rand)
+}
+
+// SPXCrossover is a CrossoverOperator using CrossoverSPX with
+// Config.CrossoverParents parents: the mating pair plus random members of
+// the population.
+type SPXCrossover struct{}
+
+// Cross implements CrossoverOperator.
+func (SPXCrossover) Cross(s *State, p1, p2 []float64) ([]float64, []float64) {
+	np := s.Config.CrossoverParents
+	if np == 0 {
+		np = defaultCrossoverParents
+	}
+
+	parents := make([][]float64, 0, np)
+	parents = append(parents, p1, p2)
+
+	for len(parents) < np {
+		parents = append(parents, randomPopulationPosition(s))
+	}
+
+	lb, ub := s.Config.LowerBound, s.Config.UpperBound
+
+	return CrossoverSPX(parents, lb, ub, s.Rand), CrossoverSPX(parents, lb, ub, s.Rand)
+}
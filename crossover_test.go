@@ -0,0 +1,165 @@
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for crossover.go - Selectable crossover operators
+// =============================================================================
+
+func TestCrossoverSBXPreservesMean(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	x1 := []float64{1, -2, 3, 0.5}
+	x2 := []float64{-1, 2, 0, 0.5}
+
+	off1, off2 := CrossoverSBX(x1, x2, 20, -10, 10, rng)
+
+	for i := range x1 {
+		if math.Abs(off1[i]+off2[i]-(x1[i]+x2[i])) > 1e-12 {
+			t.Errorf("SBX offspring mean differs from parent mean at %d", i)
+		}
+	}
+}
+
+func TestCrossoverBLXRange(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	x1 := []float64{0, 1}
+	x2 := []float64{2, 1}
+
+	for trial := 0; trial < 100; trial++ {
+		off1, off2 := CrossoverBLX(x1, x2, 0.5, -10, 10, rng)
+
+		for _, off := range [][]float64{off1, off2} {
+			if off[0] < -1 || off[0] > 3 {
+				t.Fatalf("BLX gene %v outside extended interval [-1, 3]", off[0])
+			}
+
+			if off[1] != 1 {
+				t.Fatalf("BLX gene should equal identical parents, got %v", off[1])
+			}
+		}
+	}
+}
+
+func TestCrossoverDEBinomial(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	target := []float64{0, 0, 0, 0, 0}
+	r1 := []float64{1, 1, 1, 1, 1}
+	zero := make([]float64, 5)
+
+	// With CR = 0 exactly one gene comes from the mutant
+	trial := CrossoverDE(target, r1, zero, zero, 0.5, 0, -10, 10, rng)
+
+	changed := 0
+	for _, v := range trial {
+		if v != 0 {
+			changed++
+		}
+	}
+
+	if changed != 1 {
+		t.Errorf("Expected exactly one mutant gene, got %d", changed)
+	}
+
+	// With CR = 1 the trial equals the mutant
+	trial = CrossoverDE(target, r1, zero, zero, 0.5, 1, -10, 10, rng)
+	for i, v := range trial {
+		if v != 1 {
+			t.Errorf("Gene %d = %v, want mutant value 1", i, v)
+		}
+	}
+}
+
+func TestCrossoverMultiParentDegenerate(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	p := []float64{1, 2, 3}
+
+	off1, off2 := CrossoverUNDX(p, p, p, -10, 10, rng)
+	spx := CrossoverSPX([][]float64{p, p, p}, -10, 10, rng)
+
+	for i := range p {
+		if off1[i] != p[i] || off2[i] != p[i] || math.Abs(spx[i]-p[i]) > 1e-12 {
+			t.Errorf("Identical parents should reproduce the parent, got %v %v %v", off1, off2, spx)
+		}
+	}
+}
+
+func TestCrossoverSPXWithinExpandedSimplex(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	parents := [][]float64{{0, 0}, {1, 0}, {0, 1}}
+
+	// Expansion sqrt(4) = 2 around the centroid (1/3, 1/3)
+	for trial := 0; trial < 200; trial++ {
+		off := CrossoverSPX(parents, -10, 10, rng)
+		if off[0] < -1.0/3-1e-9 || off[1] < -1.0/3-1e-9 || off[0]+off[1] > 5.0/3+1e-9 {
+			t.Fatalf("SPX offspring %v outside expanded simplex", off)
+		}
+	}
+}
+
+func TestCrossoverTypesOptimize(t *testing.T) {
+	for _, crossoverType := range []string{
+		CrossoverTypeArithmetic, CrossoverTypeSBX, CrossoverTypeBLX,
+		CrossoverTypeDE, CrossoverTypeUNDX, CrossoverTypeSPX,
+	} {
+		t.Run(crossoverType, func(t *testing.T) {
+			config := newTestConfig(Sphere, 5, 10, 30, 7)
+			config.MaxIterations = 100
+			config.CrossoverType = crossoverType
+
+			if err := ValidateConfig(config); err != nil {
+				t.Fatalf("ValidateConfig failed: %v", err)
+			}
+
+			result, err := Optimize(config)
+			if err != nil {
+				t.Fatalf("Optimize failed: %v", err)
+			}
+
+			if result.GlobalBest.Cost > 1.0 {
+				t.Errorf("%s did not converge on Sphere: %v", crossoverType, result.GlobalBest.Cost)
+			}
+		})
+	}
+}
+
+func TestNewPipelineCrossoverType(t *testing.T) {
+	config := NewGSASMAConfig()
+	config.CrossoverType = CrossoverTypeSBX
+
+	if _, ok := NewPipeline(config).Crossover.(SBXCrossover); !ok {
+		t.Error("NewPipeline should use the configured crossover operator")
+	}
+}
+
+func TestCrossoverConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"Unknown type", func(c *Config) { c.CrossoverType = "uniform" }},
+		{"Negative eta", func(c *Config) { c.CrossoverEta = -1 }},
+		{"Negative alpha", func(c *Config) { c.CrossoverAlpha = -0.1 }},
+		{"Large F", func(c *Config) { c.DEScaleFactor = 3 }},
+		{"CR above one", func(c *Config) { c.DECrossoverRate = 1.5 }},
+		{"Single parent", func(c *Config) { c.CrossoverParents = 1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newTestConfig(Sphere, 5, 10, 30, 1)
+			tt.modify(config)
+
+			if err := ValidateConfig(config); err == nil {
+				t.Error("ValidateConfig should reject the config")
+			}
+
+			if _, err := Optimize(config); err == nil {
+				t.Error("Optimize should reject the config")
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// Package mayfly - Decision support for multi-objective results
+//
+// Helpers for choosing one solution of a Pareto front, e.g. MultiResult.Front
+// or the Front of an Archive. Each returns the solutions ordered from most to
+// least preferred together with the score of the order:
+//   - KneePoints: distance beyond the hyperplane through the extreme solutions
+//   - RankByWeightedSum, RankByTchebycheff: scalarization with user weights
+//   - RankByReferencePoint: projection of an aspiration level onto the front
+//   - RankByTOPSIS: relative closeness to the ideal point
+//
+// Objectives are first normalized to [0, 1] by the ideal and nadir points of
+// the front, so weights do not depend on the scales of the objectives. All
+// objectives are minimized.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// augmentationFactor is the weight of the sum term of the augmented
+// achievement scalarizing function, which prefers non-dominated solutions
+// among those with the same maximum.
+const augmentationFactor = 1e-6
+
+// RankedSolution is a solution of a front with its decision-support score.
+type RankedSolution struct {
+	Solution *ParetoSolution
+	Score    float64
+}
+
+// KneePoints ranks the front by its knees: the solutions that give up much
+// in the other objectives for a small gain in one. The score is the distance
+// of a solution beyond the hyperplane through the extreme solutions (higher
+// is better); solutions behind the hyperplane have negative scores.
+func KneePoints(front []*ParetoSolution) []RankedSolution {
+	if len(front) == 0 {
+		return nil
+	}
+
+	all := make([]int, len(front))
+	for i := range all {
+		all[i] = i
+	}
+
+	// After normalization the hyperplane through the extremes is Σ f = 1
+	normalized := normalizeObjectives(front, all)
+	scale := math.Sqrt(float64(len(normalized[0])))
+
+	return rankSolutions(front, true, func(i int) float64 {
+		sum := 0.0
+		for _, v := range normalized[i] {
+			sum += v
+		}
+
+		return (1 - sum) / scale
+	})
+}
+
+// RankByWeightedSum ranks the front by the weighted sum of the normalized
+// objectives (lower is better). Weights hold one entry per objective; nil
+// weights all objectives equally. The weighted sum only finds solutions on
+// the convex hull of the front. It returns an error if the number of weights
+// does not match the number of objectives.
+func RankByWeightedSum(front []*ParetoSolution, weights []float64) ([]RankedSolution, error) {
+	return rankByScalarization(front, weights, ScalarizationWeightedSum)
+}
+
+// RankByTchebycheff ranks the front by the weighted Tchebycheff distance of
+// the normalized objectives to the ideal point (lower is better). Weights
+// hold one entry per objective; nil weights all objectives equally. Unlike
+// the weighted sum it reaches every solution of a non-convex front. It
+// returns an error if the number of weights does not match the number of
+// objectives.
+func RankByTchebycheff(front []*ParetoSolution, weights []float64) ([]RankedSolution, error) {
+	return rankByScalarization(front, weights, ScalarizationTchebycheff)
+}
+
+// RankByReferencePoint ranks the front by the augmented achievement
+// scalarizing function of an aspiration level (Wierzbicki): the weighted
+// maximum amount by which a solution misses the reference point (lower is
+// better; negative if it is better than the reference point in every
+// objective). The first solution is the projection of the reference point
+// onto the front along the weight direction. The reference point is given
+// in objective units. Weights hold one entry per objective; nil weights all
+// objectives equally. It returns an error if the reference point or the
+// weights do not have one entry per objective.
+func RankByReferencePoint(front []*ParetoSolution, reference, weights []float64) ([]RankedSolution, error) {
+	if len(front) == 0 {
+		return nil, nil
+	}
+
+	ideal, nadir := IdealPoint(front), NadirPoint(front)
+	if len(reference) != len(ideal) {
+		return nil, fmt.Errorf("reference point has %d entries, expected %d objectives", len(reference), len(ideal))
+	}
+
+	w, err := decisionWeights(weights, len(ideal))
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizeFront(front, ideal, nadir)
+	z := normalizedPoint(reference, ideal, nadir)
+
+	return rankSolutions(front, false, func(i int) float64 {
+		worst, sum := math.Inf(-1), 0.0
+		for j, f := range normalized[i].ObjectiveValues {
+			v := w[j] * (f - z[j])
+			worst = math.Max(worst, v)
+			sum += v
+		}
+
+		return worst + augmentationFactor*sum
+	}), nil
+}
+
+// RankByTOPSIS ranks the front by TOPSIS: the relative closeness
+// d⁻ / (d⁺ + d⁻) of the weighted normalized objectives to the ideal point
+// (distance d⁺) and away from the nadir point (distance d⁻). Scores lie in
+// [0, 1] (higher is better). Weights hold one entry per objective; nil
+// weights all objectives equally. It returns an error if the number of
+// weights does not match the number of objectives.
+func RankByTOPSIS(front []*ParetoSolution, weights []float64) ([]RankedSolution, error) {
+	if len(front) == 0 {
+		return nil, nil
+	}
+
+	ideal, nadir := IdealPoint(front), NadirPoint(front)
+
+	w, err := decisionWeights(weights, len(ideal))
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizeFront(front, ideal, nadir)
+
+	return rankSolutions(front, true, func(i int) float64 {
+		toIdeal, toNadir := 0.0, 0.0
+		for j, f := range normalized[i].ObjectiveValues {
+			if nadir[j] == ideal[j] {
+				continue
+			}
+
+			toIdeal += w[j] * w[j] * f * f
+			toNadir += w[j] * w[j] * (1 - f) * (1 - f)
+		}
+
+		toIdeal, toNadir = math.Sqrt(toIdeal), math.Sqrt(toNadir)
+		if toIdeal+toNadir == 0 {
+			return 1
+		}
+
+		return toNadir / (toIdeal + toNadir)
+	}), nil
+}
+
+// rankByScalarization ranks the normalized front by a scalarizing function
+// with the ideal point at the origin.
+func rankByScalarization(front []*ParetoSolution, weights []float64, method string) ([]RankedSolution, error) {
+	if len(front) == 0 {
+		return nil, nil
+	}
+
+	normalized := NormalizeFront(front, IdealPoint(front), NadirPoint(front))
+	m := len(normalized[0].ObjectiveValues)
+
+	w, err := decisionWeights(weights, m)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := make([]float64, m)
+
+	return rankSolutions(front, false, func(i int) float64 {
+		return scalarize(method, normalized[i].ObjectiveValues, w, origin, 0)
+	}), nil
+}
+
+// rankSolutions scores every solution and orders the front by score,
+// descending if higher is better. Equal scores keep the front order.
+func rankSolutions(front []*ParetoSolution, higherIsBetter bool, score func(i int) float64) []RankedSolution {
+	ranked := make([]RankedSolution, len(front))
+	for i, sol := range front {
+		ranked[i] = RankedSolution{Solution: sol, Score: score(i)}
+	}
+
+	sort.SliceStable(ranked, func(a, b int) bool {
+		if higherIsBetter {
+			return ranked[a].Score > ranked[b].Score
+		}
+
+		return ranked[a].Score < ranked[b].Score
+	})
+
+	return ranked
+}
+
+// decisionWeights returns the weights, or equal weights summing to 1 if
+// weights is nil. It returns an error if weights does not have m entries.
+func decisionWeights(weights []float64, m int) ([]float64, error) {
+	if weights != nil {
+		if len(weights) != m {
+			return nil, fmt.Errorf("got %d weights, expected %d objectives", len(weights), m)
+		}
+
+		return weights, nil
+	}
+
+	equal := make([]float64, m)
+	for j := range equal {
+		equal[j] = 1 / float64(m)
+	}
+
+	return equal, nil
+}
+
+// normalizedPoint maps point from [ideal, nadir] to [0, 1] like
+// NormalizeFront, leaving objectives with ideal == nadir at 0.
+func normalizedPoint(point, ideal, nadir []float64) []float64 {
+	normalized := make([]float64, len(point))
+	for j, v := range point {
+		if span := nadir[j] - ideal[j]; span > 0 {
+			normalized[j] = (v - ideal[j]) / span
+		}
+	}
+
+	return normalized
+}
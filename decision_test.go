@@ -0,0 +1,204 @@
+package mayfly
+
+import (
+	"math"
+	"testing"
+)
+
+// =============================================================================
+// Tests for decision.go - Decision support
+// =============================================================================
+
+// sampledFront returns the points (f1, f2(f1)) for n + 1 equidistant f1 in [0, 1].
+func sampledFront(n int, f2 func(float64) float64) []*ParetoSolution {
+	front := make([]*ParetoSolution, n+1)
+	for i := range front {
+		f1 := float64(i) / float64(n)
+		front[i] = &ParetoSolution{ObjectiveValues: []float64{f1, f2(f1)}}
+	}
+
+	return front
+}
+
+func checkRanking(t *testing.T, ranked []RankedSolution, n int, higherIsBetter bool) {
+	t.Helper()
+
+	if len(ranked) != n {
+		t.Fatalf("Expected %d ranked solutions, got %d", n, len(ranked))
+	}
+
+	for i := 1; i < len(ranked); i++ {
+		if higherIsBetter && ranked[i].Score > ranked[i-1].Score ||
+			!higherIsBetter && ranked[i].Score < ranked[i-1].Score {
+			t.Fatalf("Solutions not ordered by score at %d", i)
+		}
+	}
+}
+
+// mustRank fails the test if a ranking returned an error.
+func mustRank(t *testing.T, ranked []RankedSolution, err error) []RankedSolution {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("Ranking failed: %v", err)
+	}
+
+	return ranked
+}
+
+func TestKneePoints(t *testing.T) {
+	front := []*ParetoSolution{
+		{ObjectiveValues: []float64{1, 0}},
+		{ObjectiveValues: []float64{0.6, 0.1}},
+		{ObjectiveValues: []float64{0.2, 0.2}},
+		{ObjectiveValues: []float64{0, 1}},
+	}
+
+	ranked := KneePoints(front)
+	checkRanking(t, ranked, 4, true)
+
+	if ranked[0].Solution != front[2] {
+		t.Errorf("Expected knee (0.2, 0.2), got %v", ranked[0].Solution.ObjectiveValues)
+	}
+
+	// The extremes lie on the hyperplane
+	for _, r := range ranked[2:] {
+		if math.Abs(r.Score) > 1e-12 {
+			t.Errorf("Extreme %v has score %g", r.Solution.ObjectiveValues, r.Score)
+		}
+	}
+
+	// The knee of ZDT1 is where the slope is -1
+	zdt1 := sampledFront(100, func(f1 float64) float64 { return 1 - math.Sqrt(f1) })
+	if f1 := KneePoints(zdt1)[0].Solution.ObjectiveValues[0]; math.Abs(f1-0.25) > 0.01 {
+		t.Errorf("Expected the ZDT1 knee at f1 = 0.25, got %g", f1)
+	}
+}
+
+func TestRankByWeightedSumAndTchebycheff(t *testing.T) {
+	concave := sampledFront(1000, func(f1 float64) float64 { return 1 - f1*f1 })
+
+	// The weighted sum only finds the extremes of a concave front
+	ranked, err := RankByWeightedSum(concave, nil)
+	ranked = mustRank(t, ranked, err)
+	checkRanking(t, ranked, len(concave), false)
+
+	if f1 := ranked[0].Solution.ObjectiveValues[0]; f1 != 0 && f1 != 1 {
+		t.Errorf("Weighted sum picked interior solution f1 = %g", f1)
+	}
+
+	ranked, err = RankByWeightedSum(concave, []float64{0, 1})
+	if f := mustRank(t, ranked, err)[0].Solution.ObjectiveValues; f[1] != 0 {
+		t.Errorf("Weighted sum with weights (0, 1) picked %v", f)
+	}
+
+	// Tchebycheff with equal weights picks f1 = f2
+	ranked, err = RankByTchebycheff(concave, nil)
+	ranked = mustRank(t, ranked, err)
+	checkRanking(t, ranked, len(concave), false)
+
+	if f1 := ranked[0].Solution.ObjectiveValues[0]; math.Abs(f1-(math.Sqrt(5)-1)/2) > 0.001 {
+		t.Errorf("Expected Tchebycheff choice f1 = 0.618, got %g", f1)
+	}
+}
+
+func TestRankByReferencePoint(t *testing.T) {
+	zdt1 := sampledFront(1000, func(f1 float64) float64 { return 1 - math.Sqrt(f1) })
+
+	// Projecting (0.5, 0.5) along the diagonal reaches f1 = f2
+	ranked, err := RankByReferencePoint(zdt1, []float64{0.5, 0.5}, nil)
+	ranked = mustRank(t, ranked, err)
+	checkRanking(t, ranked, len(zdt1), false)
+
+	expected := math.Pow((math.Sqrt(5)-1)/2, 2)
+	if f1 := ranked[0].Solution.ObjectiveValues[0]; math.Abs(f1-expected) > 0.002 {
+		t.Errorf("Expected projection at f1 = %.4f, got %g", expected, f1)
+	}
+
+	// A reachable aspiration level gives a negative score
+	if ranked[0].Score >= 0 {
+		t.Errorf("Expected negative score, got %g", ranked[0].Score)
+	}
+
+	// An unreachable one does not
+	ranked, err = RankByReferencePoint(zdt1, []float64{0, 0}, nil)
+	if ranked = mustRank(t, ranked, err); ranked[0].Score <= 0 {
+		t.Errorf("Expected positive score, got %g", ranked[0].Score)
+	}
+}
+
+func TestRankByTOPSIS(t *testing.T) {
+	front := []*ParetoSolution{
+		{ObjectiveValues: []float64{0, 10}},
+		{ObjectiveValues: []float64{4, 4}},
+		{ObjectiveValues: []float64{10, 0}},
+	}
+
+	ranked, err := RankByTOPSIS(front, nil)
+	ranked = mustRank(t, ranked, err)
+	checkRanking(t, ranked, 3, true)
+
+	if ranked[0].Solution != front[1] {
+		t.Errorf("Expected the balanced solution first, got %v", ranked[0].Solution.ObjectiveValues)
+	}
+
+	for _, r := range ranked {
+		if r.Score < 0 || r.Score > 1 {
+			t.Errorf("Score %g outside [0, 1]", r.Score)
+		}
+	}
+
+	ranked, err = RankByTOPSIS(front, []float64{1, 0})
+	if ranked = mustRank(t, ranked, err); ranked[0].Solution != front[0] || ranked[0].Score != 1 {
+		t.Errorf("Expected the best first objective with score 1, got %v (%g)", ranked[0].Solution.ObjectiveValues, ranked[0].Score)
+	}
+}
+
+func TestDecisionSupportEmptyFront(t *testing.T) {
+	if KneePoints(nil) != nil {
+		t.Error("Empty front should give no knee points")
+	}
+
+	for name, rank := range map[string]func([]*ParetoSolution, []float64) ([]RankedSolution, error){
+		"weighted sum": RankByWeightedSum,
+		"tchebycheff":  RankByTchebycheff,
+		"topsis":       RankByTOPSIS,
+		"reference point": func(f []*ParetoSolution, w []float64) ([]RankedSolution, error) {
+			return RankByReferencePoint(f, nil, w)
+		},
+	} {
+		if ranked, err := rank(nil, nil); ranked != nil || err != nil {
+			t.Errorf("%s: empty front should give no ranking, got %v (%v)", name, ranked, err)
+		}
+	}
+}
+
+func TestDecisionSupportLengths(t *testing.T) {
+	front := []*ParetoSolution{
+		{ObjectiveValues: []float64{0, 1}},
+		{ObjectiveValues: []float64{1, 0}},
+	}
+
+	short, long := []float64{1}, []float64{1, 1, 1}
+
+	for name, rank := range map[string]func([]*ParetoSolution, []float64) ([]RankedSolution, error){
+		"weighted sum": RankByWeightedSum,
+		"tchebycheff":  RankByTchebycheff,
+		"topsis":       RankByTOPSIS,
+		"reference point": func(f []*ParetoSolution, w []float64) ([]RankedSolution, error) {
+			return RankByReferencePoint(f, []float64{0.5, 0.5}, w)
+		},
+	} {
+		for _, weights := range [][]float64{short, long} {
+			if _, err := rank(front, weights); err == nil {
+				t.Errorf("%s: expected an error for %d weights", name, len(weights))
+			}
+		}
+	}
+
+	for _, reference := range [][]float64{short, long} {
+		if _, err := RankByReferencePoint(front, reference, nil); err == nil {
+			t.Errorf("Expected an error for a reference point with %d entries", len(reference))
+		}
+	}
+}
@@ -32,16 +32,7 @@ func generateEliteMayflies(currentBest Best, searchRange float64, eliteCount, pr
 	for i := 0; i < eliteCount; i++ {
 		elite := newMayfly(problemSize)
 
-		// Generate elite position: egbest = cgbest + r1 * R
-		// where r1 is random vector in [-1, 1]
-		for j := 0; j < problemSize; j++ {
-			r1 := unifrnd(-1, 1, rng)
-			elite.Position[j] = currentBest.Position[j] + r1*searchRange
-		}
-
-		// Apply boundary constraints
-		maxVec(elite.Position, lowerBound)
-		minVec(elite.Position, upperBound)
+		elitePosition(elite.Position, currentBest.Position, searchRange, lowerBound, upperBound, rng)
 
 		// Evaluate elite mayfly
 		elite.Cost = objFunc(elite.Position)
@@ -58,3 +49,123 @@ func generateEliteMayflies(currentBest Best, searchRange float64, eliteCount, pr
 
 	return bestElite, funcEvals
 }
+
+// elitePosition sets position to a random elite position around center:
+// egbest = cgbest + r1 * R, where r1 is a random vector in [-1, 1].
+func elitePosition(position, center []float64, searchRange, lowerBound, upperBound float64, rng *rand.Rand) {
+	for j := range position {
+		r1 := unifrnd(-1, 1, rng)
+		position[j] = center[j] + r1*searchRange
+	}
+
+	// Apply boundary constraints
+	maxVec(position, lowerBound)
+	minVec(position, upperBound)
+}
+
+// generateScreenedElites generates the elite positions of DESMA like
+// generateEliteMayflies, but evaluates only those the surrogate selects.
+// It returns the better of the best elite and the global best.
+func generateScreenedElites(s *State, searchRange float64) *Mayfly {
+	config := s.Config
+
+	bestElite := newMayfly(config.ProblemSize)
+	copy(bestElite.Position, s.GlobalBest.Position)
+	bestElite.Cost = s.GlobalBest.Cost
+	copy(bestElite.Best.Position, s.GlobalBest.Position)
+	bestElite.Best.Cost = s.GlobalBest.Cost
+
+	positions := make([][]float64, config.EliteCount)
+	for i := range positions {
+		positions[i] = make([]float64, config.ProblemSize)
+		elitePosition(positions[i], s.GlobalBest.Position, searchRange, config.LowerBound, config.UpperBound, s.Rand)
+	}
+
+	for _, i := range s.surrogate.screen(s, positions, nil) {
+		if cost := s.Evaluate(positions[i]); cost < bestElite.Cost {
+			copy(bestElite.Position, positions[i])
+			bestElite.Cost = cost
+			copy(bestElite.Best.Position, positions[i])
+			bestElite.Best.Cost = cost
+		}
+	}
+
+	return bestElite
+}
+
+// desmaStage implements the DESMA dynamic elite strategy as a pipeline stage.
+type desmaStage struct {
+	searchRange        float64
+	lastGlobalBestCost float64
+}
+
+func (d *desmaStage) Name() string {
+	return "desma"
+}
+
+// Initialize sets the initial elite search range.
+func (d *desmaStage) Initialize(s *State) error {
+	d.reset(s)
+	return nil
+}
+
+// Restart resets the elite search range for a new run.
+func (d *desmaStage) Restart(s *State) {
+	d.reset(s)
+}
+
+// reset sets the initial search range and improvement reference.
+func (d *desmaStage) reset(s *State) {
+	if s.Config.SearchRange == 0 {
+		// Auto-calculate initial search range as 10% of the search space
+		d.searchRange = 0.1 * (s.Config.UpperBound - s.Config.LowerBound)
+	} else {
+		d.searchRange = s.Config.SearchRange
+	}
+
+	d.lastGlobalBestCost = s.GlobalBest.Cost
+}
+
+// PostSelect adapts the search range and replaces the worst male with the
+// best elite if it is better.
+func (d *desmaStage) PostSelect(s *State) {
+	config := s.Config
+
+	// Dynamically adjust search range based on improvement
+	if s.GlobalBest.Cost < d.lastGlobalBestCost {
+		// Improving: enlarge search range
+		d.searchRange *= config.EnlargeFactor
+	} else {
+		// Not improving: reduce search range
+		d.searchRange *= config.ReductionFactor
+	}
+
+	// Generate elite mayflies around global best
+	var eliteMayfly *Mayfly
+	if s.surrogate != nil {
+		eliteMayfly = generateScreenedElites(s, d.searchRange)
+	} else {
+		eliteMayfly, _ = generateEliteMayflies(
+			s.GlobalBest,
+			d.searchRange,
+			config.EliteCount,
+			config.ProblemSize,
+			config.LowerBound,
+			config.UpperBound,
+			s.Evaluate,
+			s.Rand,
+		)
+	}
+
+	// Replace worst male if elite is better
+	worst := len(s.Males) - 1
+	if eliteMayfly.Cost < s.Males[worst].Cost {
+		s.Males[worst] = eliteMayfly
+		sortMayflies(s.Males) // Re-sort after replacement
+
+		// Update global best if elite is the new best
+		s.UpdateGlobalBest(eliteMayfly.Position, eliteMayfly.Cost)
+	}
+
+	d.lastGlobalBestCost = s.GlobalBest.Cost
+}
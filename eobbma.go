@@ -0,0 +1,115 @@
+// Package mayfly - EOBBMA (Elite Opposition-Based Bare Bones Mayfly Algorithm)
+//
+// Implements the EOBBMA variant as a set of pipeline stages.
+//
+// Reference:
+// Elite Opposition-Based Bare Bones Mayfly Algorithm (2024).
+// Arabian Journal for Science and Engineering.
+//
+// EOBBMA replaces the velocity-based movement with:
+// - Bare Bones Gaussian sampling towards personal, global and male best positions
+// - Lévy flights for occasional long jumps of the females
+// - Elite opposition-based learning on the best males
+package mayfly
+
+// eobbmaMover implements the Bare Bones movement of EOBBMA.
+type eobbmaMover struct{}
+
+// MoveFemales updates females with Gaussian sampling around their paired
+// male or with a Lévy flight.
+func (m *eobbmaMover) MoveFemales(s *State) {
+	config := s.Config
+
+	for i := 0; i < config.NPopF; i++ {
+		female := s.Females[i]
+
+		// Decide whether to use Lévy flight or Gaussian update
+		if s.Rand.Float64() < 0.5 {
+			// Use Gaussian update toward best male
+			newPos := gaussianUpdate(female.Position, s.Males[i].Position,
+				config.LowerBound, config.UpperBound, s.Rand)
+			copy(female.Position, newPos)
+		} else {
+			// Use Lévy flight for exploration
+			levyStep := levyFlightVec(config.ProblemSize, config.LevyAlpha, config.LevyBeta, s.Rand)
+			for j := 0; j < config.ProblemSize; j++ {
+				female.Position[j] += levyStep[j] * (config.UpperBound - config.LowerBound) * 0.01
+			}
+
+			maxVec(female.Position, config.LowerBound)
+			minVec(female.Position, config.UpperBound)
+		}
+
+		female.Cost = s.Evaluate(female.Position)
+	}
+}
+
+// MoveMales updates males with Gaussian sampling around their personal or
+// the global best. In niching mode the niche leader takes the place of the
+// global best.
+func (m *eobbmaMover) MoveMales(s *State) {
+	config := s.Config
+
+	for i := 0; i < config.NPop; i++ {
+		male := s.Males[i]
+
+		// Decide whether to use Gaussian toward personal best or global best
+		target := s.socialBest(male.Position).Position
+		if s.Rand.Float64() < 0.5 {
+			target = male.Best.Position
+		}
+
+		newPos := gaussianUpdate(male.Position, target, config.LowerBound, config.UpperBound, s.Rand)
+		copy(male.Position, newPos)
+
+		male.Cost = s.Evaluate(male.Position)
+		updatePersonalBest(s, male)
+	}
+}
+
+// eobbmaStage applies elite opposition-based learning to the best males.
+type eobbmaStage struct{}
+
+func (e *eobbmaStage) Name() string {
+	return "eobbma"
+}
+
+// Enhance replaces elite males by their opposition points when these are better.
+func (e *eobbmaStage) Enhance(s *State) {
+	config := s.Config
+
+	// Apply opposition to top elite solutions with probability OppositionRate
+	numEliteOpposition := config.EliteOppositionCount
+	if numEliteOpposition > len(s.Males) {
+		numEliteOpposition = len(s.Males)
+	}
+
+	for i := 0; i < numEliteOpposition; i++ {
+		if s.Rand.Float64() >= config.OppositionRate {
+			continue
+		}
+
+		male := s.Males[i]
+
+		// Generate and evaluate opposition point
+		oppPos := oppositionPoint(male.Position, config.LowerBound, config.UpperBound)
+		oppCost := s.Evaluate(oppPos)
+
+		// If opposition is better, replace the elite
+		if oppCost < male.Cost {
+			copy(male.Position, oppPos)
+			male.Cost = oppCost
+
+			// Update personal best
+			if oppCost < male.Best.Cost {
+				copy(male.Best.Position, oppPos)
+				male.Best.Cost = oppCost
+			}
+
+			s.UpdateGlobalBest(oppPos, oppCost)
+		}
+	}
+
+	// Re-sort after opposition learning
+	sortMayflies(s.Males)
+}
@@ -246,3 +246,27 @@ func TestGaussianUpdateDeterministic(t *testing.T) {
 		}
 	}
 }
+
+// TestEOBBMANiching tests that males follow their niche leader in niching mode.
+func TestEOBBMANiching(t *testing.T) {
+	problem := cecNichingProblem(t, "F4")
+
+	config := NewEOBBMAConfig()
+	config.ObjectiveFunc = problem.Func
+	config.ProblemSize = problem.ProblemSize
+	config.LowerBound = problem.LowerBound
+	config.UpperBound = problem.UpperBound
+	config.MaxIterations = 200
+	config.Rand = rand.New(rand.NewSource(2))
+	config.NichingMethod = NichingClearing
+
+	result, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	// Following the global best collapses the males into one optimum
+	if found := problem.FoundOptima(result.Optima, 1e-2); found < 3 {
+		t.Errorf("Found %d of %d Himmelblau optima", found, problem.GlobalOptima)
+	}
+}
@@ -239,3 +239,107 @@ func ExpandedSchafferF6(x []float64) float64 {
 
 	return sum
 }
+
+// FiveUnevenPeakTrap is the negated CEC 2013 niching function F1 (1-D, bounds [0, 30]).
+// Global minima are at x = 0 and x = 30 with f = -200.
+func FiveUnevenPeakTrap(x []float64) float64 {
+	v := x[0]
+
+	switch {
+	case v < 2.5:
+		return -80 * (2.5 - v)
+	case v < 5:
+		return -64 * (v - 2.5)
+	case v < 7.5:
+		return -64 * (7.5 - v)
+	case v < 12.5:
+		return -28 * (v - 7.5)
+	case v < 17.5:
+		return -28 * (17.5 - v)
+	case v < 22.5:
+		return -32 * (v - 17.5)
+	case v < 27.5:
+		return -32 * (27.5 - v)
+	default:
+		return -80 * (v - 27.5)
+	}
+}
+
+// EqualMaxima is the negated CEC 2013 niching function F2 (1-D, bounds [0, 1]).
+// Global minima are at x = 0.1, 0.3, 0.5, 0.7, 0.9 with f = -1.
+func EqualMaxima(x []float64) float64 {
+	return -math.Pow(math.Sin(5*math.Pi*x[0]), 6)
+}
+
+// UnevenDecreasingMaxima is the negated CEC 2013 niching function F3 (1-D, bounds [0, 1]).
+// Global minimum is at x ≈ 0.08 with f = -1.
+func UnevenDecreasingMaxima(x []float64) float64 {
+	v := x[0]
+	envelope := math.Exp(-2 * math.Ln2 * math.Pow((v-0.08)/0.854, 2))
+
+	return -envelope * math.Pow(math.Sin(5*math.Pi*(math.Pow(v, 0.75)-0.05)), 6)
+}
+
+// Himmelblau has four global minima with f = 0, e.g. at f(3, 2) (2-D, bounds [-6, 6]).
+func Himmelblau(x []float64) float64 {
+	a := x[0]*x[0] + x[1] - 11
+	b := x[0] + x[1]*x[1] - 7
+
+	return a*a + b*b
+}
+
+// SixHumpCamelBack has two global minima with f ≈ -1.0316 at
+// f(0.0898, -0.7126) and f(-0.0898, 0.7126) (2-D, bounds [-1.9, 1.9] x [-1.1, 1.1]).
+func SixHumpCamelBack(x []float64) float64 {
+	a, b := x[0], x[1]
+
+	return (4-2.1*a*a+a*a*a*a/3)*a*a + a*b + (4*b*b-4)*b*b
+}
+
+// Shubert has D·3^D global minima; in 2-D there are 18 with f ≈ -186.7309
+// (bounds [-10, 10]).
+func Shubert(x []float64) float64 {
+	prod := 1.0
+
+	for _, val := range x {
+		sum := 0.0
+		for j := 1.0; j <= 5; j++ {
+			sum += j * math.Cos((j+1)*val+j)
+		}
+
+		prod *= sum
+	}
+
+	return prod
+}
+
+// Vincent has 6^D global minima with f = -1 (bounds [0.25, 10]).
+func Vincent(x []float64) float64 {
+	sum := 0.0
+	for _, val := range x {
+		sum += math.Sin(10 * math.Log(val))
+	}
+
+	return -sum / float64(len(x))
+}
+
+// ModifiedRastrigin is the negated CEC 2013 niching function F8 with
+// k = (3, 4) and k = 1 in further dimensions. In 2-D it has 12 global minima
+// with f = 2 (bounds [0, 1]).
+func ModifiedRastrigin(x []float64) float64 {
+	sum := 0.0
+
+	for i, val := range x {
+		k := 1.0
+		switch i {
+		case 0:
+			k = 3
+		case 1:
+			k = 4
+		}
+
+		sum += 10 + 9*math.Cos(2*math.Pi*k*val)
+	}
+
+	return sum
+}
@@ -0,0 +1,56 @@
+// Package mayfly - Gradient Information
+//
+// Uses an optional user-supplied gradient (Config.Gradient) for objectives
+// with cheap analytic or adjoint derivatives:
+//   - the nuptial dance of the best males follows the descent direction
+//     instead of a random direction (standard and MPMA movement)
+//   - the "lbfgsb" local search uses it instead of finite differences
+//
+// Gradient calls are counted in Result.GradientEvals, not in
+// Result.FuncEvalCount. Without a gradient the algorithm is unchanged.
+package mayfly
+
+import "math"
+
+// nuptialDance updates the velocity of a best male with gravity g, step
+// dance and the random vector e in [-1, 1]. With Config.Gradient the dance follows the
+// descent direction: every component of the negative gradient, scaled to a
+// largest component of 1, is weighted by |e_j| instead of e_j. A gradient
+// of the wrong length is ignored, and the male dances randomly.
+func nuptialDance(s *State, male *Mayfly, g, dance float64, e []float64) {
+	var direction []float64
+	if s.Config.Gradient != nil {
+		if grad := s.Gradient(male.Position); len(grad) == len(male.Velocity) {
+			direction = descentDirection(grad)
+		}
+	}
+
+	for j := range male.Velocity {
+		step := e[j]
+		if direction != nil {
+			step = math.Abs(e[j]) * direction[j]
+		}
+
+		male.Velocity[j] = g*male.Velocity[j] + dance*step
+	}
+}
+
+// descentDirection returns -grad scaled to a largest component of 1, or nil
+// if the gradient vanishes.
+func descentDirection(grad []float64) []float64 {
+	largest := 0.0
+	for _, v := range grad {
+		largest = math.Max(largest, math.Abs(v))
+	}
+
+	if largest == 0 {
+		return nil
+	}
+
+	direction := make([]float64, len(grad))
+	for j, v := range grad {
+		direction[j] = -v / largest
+	}
+
+	return direction
+}
@@ -0,0 +1,183 @@
+package mayfly
+
+import (
+	"math"
+	"testing"
+)
+
+// =============================================================================
+// Tests for gradient.go - Gradient information
+// =============================================================================
+
+func sphereGradient(x []float64) []float64 {
+	g := make([]float64, len(x))
+	for j, v := range x {
+		g[j] = 2 * v
+	}
+
+	return g
+}
+
+func rosenbrockGradient(x []float64) []float64 {
+	g := make([]float64, len(x))
+	for i := 0; i < len(x)-1; i++ {
+		g[i] += -400*x[i]*(x[i+1]-x[i]*x[i]) - 2*(1-x[i])
+		g[i+1] += 200 * (x[i+1] - x[i]*x[i])
+	}
+
+	return g
+}
+
+func newGradientTestConfig(fn ObjectiveFunction, gradient GradientFunction, seed int64) *Config {
+	config := newTestConfig(fn, 10, 10, 100, seed)
+	config.Gradient = gradient
+
+	return config
+}
+
+func TestDescentDirection(t *testing.T) {
+	direction := descentDirection([]float64{2, -4, 0})
+	expected := []float64{-0.5, 1, 0}
+
+	for j := range expected {
+		if direction[j] != expected[j] {
+			t.Fatalf("Expected %v, got %v", expected, direction)
+		}
+	}
+
+	if descentDirection([]float64{0, 0}) != nil {
+		t.Error("A vanishing gradient should give no direction")
+	}
+}
+
+func TestStateGradient(t *testing.T) {
+	var returned []float64
+
+	s := &State{Config: &Config{Gradient: func(x []float64) []float64 {
+		returned = []float64{math.NaN(), math.Inf(-1), x[0]}
+		return returned
+	}}}
+
+	g := s.Gradient([]float64{3})
+	if g[0] != 0 || g[1] != 0 || g[2] != 3 {
+		t.Errorf("Expected non-finite components set to 0, got %v", g)
+	}
+
+	// The caller may keep and reuse the slice its gradient function returned
+	if !math.IsNaN(returned[0]) || !math.IsInf(returned[1], -1) {
+		t.Errorf("The returned gradient was modified: %v", returned)
+	}
+
+	if s.GradEvals != 1 {
+		t.Errorf("Expected 1 gradient evaluation, got %d", s.GradEvals)
+	}
+}
+
+func TestGradientInformedDance(t *testing.T) {
+	tests := []struct {
+		name     string
+		f        ObjectiveFunction
+		gradient GradientFunction
+	}{
+		{"Sphere", Sphere, sphereGradient},
+		{"Rosenbrock", Rosenbrock, rosenbrockGradient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			without, with := 0.0, 0.0
+
+			for seed := int64(1); seed <= 5; seed++ {
+				result, err := Optimize(newGradientTestConfig(tt.f, nil, seed))
+				if err != nil {
+					t.Fatalf("Optimize failed: %v", err)
+				}
+
+				if result.GradientEvals != 0 {
+					t.Errorf("Expected no gradient evaluations, got %d", result.GradientEvals)
+				}
+
+				without += result.GlobalBest.Cost
+
+				result, err = Optimize(newGradientTestConfig(tt.f, tt.gradient, seed))
+				if err != nil {
+					t.Fatalf("Optimize failed: %v", err)
+				}
+
+				if result.GradientEvals == 0 {
+					t.Error("Expected gradient evaluations")
+				}
+
+				with += result.GlobalBest.Cost
+			}
+
+			if with >= without {
+				t.Errorf("Expected the gradient to help: mean cost %g with, %g without", with/5, without/5)
+			}
+		})
+	}
+}
+
+func TestLBFGSBWithGradient(t *testing.T) {
+	config := newGradientTestConfig(Rosenbrock, nil, 1)
+	config.MaxIterations = 30
+	config.ProblemSize = 5
+	config.LocalSearch = LocalSearchLBFGSB
+
+	numeric, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	config = newGradientTestConfig(Rosenbrock, rosenbrockGradient, 1)
+	config.MaxIterations = 30
+	config.ProblemSize = 5
+	config.LocalSearch = LocalSearchLBFGSB
+
+	analytic, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if analytic.GlobalBest.Cost > 1e-10 {
+		t.Errorf("Expected the analytic gradient to reach 1e-10, got %g", analytic.GlobalBest.Cost)
+	}
+
+	if analytic.LocalSearchEvals >= numeric.LocalSearchEvals {
+		t.Errorf("Expected fewer evaluations with the analytic gradient: %d vs %d",
+			analytic.LocalSearchEvals, numeric.LocalSearchEvals)
+	}
+}
+
+func TestGradientWrongLength(t *testing.T) {
+	for name, gradient := range map[string]GradientFunction{
+		"short": func(x []float64) []float64 { return sphereGradient(x[1:]) },
+		"long":  func(x []float64) []float64 { return sphereGradient(append(append([]float64(nil), x...), 1)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, method := range []string{"", LocalSearchLBFGSB} {
+				config := newGradientTestConfig(Sphere, nil, 1)
+				config.LocalSearch = method
+
+				reference, err := Optimize(config)
+				if err != nil {
+					t.Fatalf("Optimize failed: %v", err)
+				}
+
+				// The gradient is ignored: random dance and finite differences
+				config = newGradientTestConfig(Sphere, gradient, 1)
+				config.LocalSearch = method
+
+				result, err := Optimize(config)
+				if err != nil {
+					t.Fatalf("Optimize failed: %v", err)
+				}
+
+				if result.GlobalBest.Cost != reference.GlobalBest.Cost {
+					t.Errorf("Local search %q: expected cost %g without a usable gradient, got %g",
+						method, reference.GlobalBest.Cost, result.GlobalBest.Cost)
+				}
+			}
+		})
+	}
+}
@@ -198,3 +198,106 @@ func calculateAdaptiveCauchyRate(males []*Mayfly, baseCauchyRate float64) float6
 
 	return adaptiveRate
 }
+
+// HybridMutation is the GSASMA MutationOperator. It mixes Cauchy and
+// Gaussian mutation with a Cauchy probability that decreases over the run
+// (0.7 early, 0.5 in the middle, CauchyMutationRate late).
+type HybridMutation struct{}
+
+// Mutate implements MutationOperator.
+func (HybridMutation) Mutate(s *State, x []float64) []float64 {
+	config := s.Config
+
+	// Calculate adaptive Cauchy probability based on iteration progress
+	iterRatio := s.Progress()
+
+	var cauchyProb float64
+
+	if iterRatio < 0.33 {
+		cauchyProb = 0.7 // Early: high exploration
+	} else if iterRatio < 0.66 {
+		cauchyProb = 0.5 // Middle: balanced
+	} else {
+		cauchyProb = config.CauchyMutationRate // Late: configured rate (default 0.3)
+	}
+
+	return HybridMutate(x, config.Mu, config.LowerBound, config.UpperBound, cauchyProb, s.Rand)
+}
+
+// gsasmaStage applies Golden Sine with Simulated Annealing to the elite
+// males and opposition-based learning to the global best.
+type gsasmaStage struct {
+	scheduler *AnnealingScheduler
+}
+
+func (g *gsasmaStage) Name() string {
+	return "gsasma"
+}
+
+// Initialize creates the annealing scheduler.
+func (g *gsasmaStage) Initialize(s *State) error {
+	g.scheduler = NewAnnealingScheduler(
+		s.Config.InitialTemperature,
+		s.Config.CoolingRate,
+		s.Config.CoolingSchedule,
+	)
+
+	return nil
+}
+
+// Restart reheats the annealing scheduler for a new run.
+func (g *gsasmaStage) Restart(s *State) {
+	g.scheduler.Reset()
+}
+
+// Enhance applies GSA to the elite males (top 20%).
+func (g *gsasmaStage) Enhance(s *State) {
+	config := s.Config
+
+	updatedGlobalBest, updatedGlobalBestCost, _ := applyGSASMAToEliteMales(
+		s.Males,
+		0.2, // Elite ratio: top 20%
+		s.GlobalBest.Position,
+		s.GlobalBest.Cost,
+		config.GoldenFactor,
+		s.Iteration,
+		config.MaxIterations,
+		config.LowerBound,
+		config.UpperBound,
+		g.scheduler,
+		s.Evaluate,
+		s.Rand,
+	)
+
+	// Update global best if GSA found better solution
+	s.UpdateGlobalBest(updatedGlobalBest, updatedGlobalBestCost)
+
+	// Re-sort after Golden Sine updates
+	sortMayflies(s.Males)
+}
+
+// PostSelect applies opposition-based learning to the global best.
+func (g *gsasmaStage) PostSelect(s *State) {
+	// Apply OBL every 10 iterations to avoid excessive function evaluations
+	if !s.Config.ApplyOBLToGlobalBest || s.Iteration%10 != 0 {
+		return
+	}
+
+	updatedGlobalBest, updatedGlobalBestCost, _, improved := applyOBLToGlobalBest(
+		s.GlobalBest.Position,
+		s.GlobalBest.Cost,
+		s.Config.LowerBound,
+		s.Config.UpperBound,
+		s.Evaluate,
+		s.Rand,
+	)
+
+	if improved {
+		s.UpdateGlobalBest(updatedGlobalBest, updatedGlobalBestCost)
+	}
+}
+
+// EndIteration advances the temperature schedule.
+func (g *gsasmaStage) EndIteration(s *State) {
+	g.scheduler.Update()
+}
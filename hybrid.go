@@ -0,0 +1,232 @@
+// Package mayfly - Hybrid Variants
+//
+// Implements officially supported combinations of variant enhancements.
+//
+// Variant enhancements fall into two groups:
+//   - Movement variants (EOBBMA, MPMA, AOBLMOA) replace the female and male
+//     movement. At most one of them can be active.
+//   - Stage variants (DESMA, OLCE-MA, GSASMA) add pipeline stages on top of
+//     the movement and can be freely combined with each other and with one
+//     movement variant.
+//
+// Within an iteration the enhancements are applied in a fixed order
+// (see NewPipeline):
+//  1. Movement (standard, EOBBMA, MPMA or AOBLMOA)
+//  2. Post-sort enhancement: OLCE-MA orthogonal learning, EOBBMA elite
+//     opposition, GSASMA golden sine
+//  3. Mating and mutation: GSASMA hybrid mutation replaces Gaussian mutation,
+//     OLCE-MA chaos perturbs every offspring
+//  4. Post-selection: DESMA elites, GSASMA opposition on the global best,
+//     AOBLMOA archive update
+//  5. Iteration end: GSASMA cooling
+//
+// Every objective evaluation performed by any stage is counted in
+// Result.FuncEvalCount.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// movementVariants lists the variant flags that replace the movement operator.
+var movementVariants = []struct {
+	name    string
+	enabled func(*Config) bool
+}{
+	{"EOBBMA", func(c *Config) bool { return c.UseEOBBMA }},
+	{"MPMA", func(c *Config) bool { return c.UseMPMA }},
+	{"AOBLMOA", func(c *Config) bool { return c.UseAOBLMOA }},
+}
+
+// validateVariantCombination checks that the enabled variant flags form a
+// supported combination.
+func validateVariantCombination(config *Config) error {
+	enabled := make([]string, 0, len(movementVariants))
+
+	for _, v := range movementVariants {
+		if v.enabled(config) {
+			enabled = append(enabled, v.name)
+		}
+	}
+
+	if len(enabled) > 1 {
+		return fmt.Errorf("variants %s all replace the movement operator (only one can be active at a time)",
+			strings.Join(enabled, ", "))
+	}
+
+	return nil
+}
+
+// HybridVariant combines the enhancements of several algorithm variants.
+type HybridVariant struct {
+	components []AlgorithmVariant
+}
+
+// NewHybridVariant creates a hybrid of the named variants (e.g. "desma", "olce").
+// Returns an error if a name is unknown or the combination is not supported.
+func NewHybridVariant(names ...string) (*HybridVariant, error) {
+	if len(names) < 2 {
+		return nil, fmt.Errorf("a hybrid variant needs at least two components, got %d", len(names))
+	}
+
+	components := make([]AlgorithmVariant, 0, len(names))
+	seen := make(map[string]bool)
+
+	for _, name := range names {
+		variant := NewVariant(name)
+		if variant == nil {
+			return nil, fmt.Errorf("unknown variant: %s", name)
+		}
+
+		if _, ok := variant.(*HybridVariant); ok {
+			return nil, fmt.Errorf("variant %s is already a hybrid", name)
+		}
+
+		if _, ok := variant.(VariantOptimizer); ok {
+			return nil, fmt.Errorf("variant %s supplies its own optimizer and cannot be combined", name)
+		}
+
+		if seen[variant.Name()] {
+			return nil, fmt.Errorf("variant %s listed more than once", variant.Name())
+		}
+
+		seen[variant.Name()] = true
+
+		components = append(components, variant)
+	}
+
+	hybrid := &HybridVariant{components: components}
+	if err := validateVariantCombination(hybrid.GetConfig()); err != nil {
+		return nil, err
+	}
+
+	return hybrid, nil
+}
+
+// Components returns the variants combined by this hybrid.
+func (v *HybridVariant) Components() []AlgorithmVariant {
+	return v.components
+}
+
+func (v *HybridVariant) Name() string {
+	names := make([]string, len(v.components))
+	for i, c := range v.components {
+		names[i] = c.Name()
+	}
+
+	return strings.Join(names, "+")
+}
+
+func (v *HybridVariant) FullName() string {
+	names := make([]string, len(v.components))
+	for i, c := range v.components {
+		names[i] = c.FullName()
+	}
+
+	return "Hybrid of " + strings.Join(names, " and ")
+}
+
+func (v *HybridVariant) Description() string {
+	return fmt.Sprintf("Combines the %s enhancements in a single pipeline.", v.Name())
+}
+
+// GetConfig merges the default configurations of all components.
+func (v *HybridVariant) GetConfig() *Config {
+	config := NewDefaultConfig()
+	for _, c := range v.components {
+		mergeVariantConfig(config, c.GetConfig())
+	}
+
+	return config
+}
+
+func (v *HybridVariant) ApplicableTo(characteristics ProblemCharacteristics) float64 {
+	score := 0.0
+	for _, c := range v.components {
+		score = math.Max(score, c.ApplicableTo(characteristics))
+	}
+
+	// Complementary enhancements pay off on hard landscapes, otherwise the
+	// extra overhead is not worth it.
+	if characteristics.Modality == HighlyMultimodal && !characteristics.ExpensiveEvaluations {
+		score += 0.05
+	} else {
+		score -= 0.05
+	}
+
+	if characteristics.ExpensiveEvaluations {
+		score -= 0.1
+	}
+
+	return math.Max(0, min(score, 1.0))
+}
+
+func (v *HybridVariant) EstimatedOverhead() float64 {
+	overhead := 1.0
+	for _, c := range v.components {
+		overhead += c.EstimatedOverhead() - 1.0
+	}
+
+	return overhead
+}
+
+func (v *HybridVariant) RecommendedFor() []string {
+	recommended := []string{"Problems where a single enhancement stagnates"}
+	for _, c := range v.components {
+		recommended = append(recommended, c.RecommendedFor()...)
+	}
+
+	return recommended
+}
+
+// mergeVariantConfig enables the variants of src in dst and copies their parameters.
+func mergeVariantConfig(dst, src *Config) {
+	if src.UseDESMA {
+		dst.UseDESMA = true
+		dst.EliteCount = src.EliteCount
+		dst.SearchRange = src.SearchRange
+		dst.EnlargeFactor = src.EnlargeFactor
+		dst.ReductionFactor = src.ReductionFactor
+	}
+
+	if src.UseOLCE {
+		dst.UseOLCE = true
+		dst.OrthogonalFactor = src.OrthogonalFactor
+		dst.ChaosFactor = src.ChaosFactor
+	}
+
+	if src.UseEOBBMA {
+		dst.UseEOBBMA = true
+		dst.LevyAlpha = src.LevyAlpha
+		dst.LevyBeta = src.LevyBeta
+		dst.OppositionRate = src.OppositionRate
+		dst.EliteOppositionCount = src.EliteOppositionCount
+	}
+
+	if src.UseMPMA {
+		dst.UseMPMA = true
+		dst.MedianWeight = src.MedianWeight
+		dst.GravityType = src.GravityType
+		dst.UseWeightedMedian = src.UseWeightedMedian
+	}
+
+	if src.UseGSASMA {
+		dst.UseGSASMA = true
+		dst.InitialTemperature = src.InitialTemperature
+		dst.CoolingRate = src.CoolingRate
+		dst.CauchyMutationRate = src.CauchyMutationRate
+		dst.GoldenFactor = src.GoldenFactor
+		dst.CoolingSchedule = src.CoolingSchedule
+		dst.ApplyOBLToGlobalBest = src.ApplyOBLToGlobalBest
+	}
+
+	if src.UseAOBLMOA {
+		dst.UseAOBLMOA = true
+		dst.AquilaWeight = src.AquilaWeight
+		dst.OppositionProbability = src.OppositionProbability
+		dst.ArchiveSize = src.ArchiveSize
+		dst.StrategySwitch = src.StrategySwitch
+	}
+}
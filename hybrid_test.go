@@ -0,0 +1,182 @@
+package mayfly
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for hybrid.go - Combined variant enhancements
+// =============================================================================
+
+func TestNewHybridVariant(t *testing.T) {
+	hybrid, err := NewHybridVariant("desma", "olce", "gsasma")
+	if err != nil {
+		t.Fatalf("NewHybridVariant failed: %v", err)
+	}
+
+	if hybrid.Name() != "DESMA+OLCE-MA+GSASMA" {
+		t.Errorf("Unexpected name %s", hybrid.Name())
+	}
+
+	config := hybrid.GetConfig()
+	if !config.UseDESMA || !config.UseOLCE || !config.UseGSASMA {
+		t.Error("Hybrid config should enable all component variants")
+	}
+
+	if config.OrthogonalFactor != 0.3 || config.InitialTemperature != 100.0 {
+		t.Error("Hybrid config should carry component parameters")
+	}
+
+	if hybrid.EstimatedOverhead() <= (&GSASMAVariant{}).EstimatedOverhead() {
+		t.Errorf("Hybrid overhead %.2f should exceed its components", hybrid.EstimatedOverhead())
+	}
+}
+
+func TestNewHybridVariantErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		parts []string
+	}{
+		{"Single component", []string{"desma"}},
+		{"Unknown component", []string{"desma", "unknown"}},
+		{"Duplicate component", []string{"olce", "olce-ma"}},
+		{"Two movement variants", []string{"mpma", "eobbma"}},
+		{"Nested hybrid", []string{"desma-olce", "gsasma"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHybridVariant(tt.parts...); err == nil {
+				t.Errorf("Expected error for %v", tt.parts)
+			}
+		})
+	}
+}
+
+func TestNewVariantHybridNames(t *testing.T) {
+	if v := NewVariant("desma-olce-gsasma"); v == nil || v.Name() != "DESMA+OLCE-MA+GSASMA" {
+		t.Errorf("Registered hybrid not found: %v", v)
+	}
+
+	if v := NewVariant("EOBBMA+DESMA"); v == nil || v.Name() != "EOBBMA+DESMA" {
+		t.Errorf("Ad-hoc hybrid not created: %v", v)
+	}
+
+	if v := NewVariant("mpma+aoblmoa"); v != nil {
+		t.Errorf("Unsupported hybrid should return nil, got %s", v.Name())
+	}
+}
+
+func TestValidateVariantCombination(t *testing.T) {
+	config := NewDESMAConfig()
+	config.UseOLCE = true
+	config.UseGSASMA = true
+	config.UseMPMA = true
+
+	if err := validateVariantCombination(config); err != nil {
+		t.Errorf("Stage variants with one movement variant should be valid: %v", err)
+	}
+
+	config.UseAOBLMOA = true
+	if err := validateVariantCombination(config); err == nil {
+		t.Error("Expected error for two movement variants")
+	}
+}
+
+func TestRegisteredHybridsOptimize(t *testing.T) {
+	for _, name := range []string{"desma-olce", "desma-gsasma", "olce-gsasma", "desma-olce-gsasma", "mpma-desma"} {
+		t.Run(name, func(t *testing.T) {
+			config := NewVariant(name).GetConfig()
+			config.ObjectiveFunc = Sphere
+			config.ProblemSize = 5
+			config.LowerBound = -10
+			config.UpperBound = 10
+			config.MaxIterations = 50
+			config.Rand = rand.New(rand.NewSource(1))
+
+			if err := ValidateConfig(config); err != nil {
+				t.Fatalf("ValidateConfig rejected registered hybrid: %v", err)
+			}
+
+			result, err := Optimize(config)
+			if err != nil {
+				t.Fatalf("Optimize failed: %v", err)
+			}
+
+			if result.GlobalBest.Cost > 1.0 {
+				t.Errorf("Hybrid %s did not converge on Sphere: %v", name, result.GlobalBest.Cost)
+			}
+		})
+	}
+}
+
+func TestHybridEvaluationAccounting(t *testing.T) {
+	for _, name := range []string{"desma-olce", "olce", "gsasma", "desma-gsasma"} {
+		t.Run(name, func(t *testing.T) {
+			variant := NewVariant(name)
+			if variant == nil {
+				t.Fatalf("NewVariant(%q) returned nil", name)
+			}
+
+			config := variant.GetConfig()
+			config.ProblemSize = 4
+			config.LowerBound = -5
+			config.UpperBound = 5
+			config.MaxIterations = 20
+			config.Rand = rand.New(rand.NewSource(3))
+
+			calls := 0
+			config.ObjectiveFunc = func(x []float64) float64 {
+				calls++
+				return Sphere(x)
+			}
+
+			result, err := Optimize(config)
+			if err != nil {
+				t.Fatalf("Optimize failed: %v", err)
+			}
+
+			if result.FuncEvalCount != calls {
+				t.Errorf("FuncEvalCount = %d, objective called %d times", result.FuncEvalCount, calls)
+			}
+		})
+	}
+}
+
+func TestAOBLMOAEvaluationAccounting(t *testing.T) {
+	config := NewAOBLMOAConfig()
+	config.ProblemSize = 4
+	config.LowerBound = -5
+	config.UpperBound = 5
+	config.MaxIterations = 20
+	config.Rand = rand.New(rand.NewSource(3))
+
+	calls := 0
+	config.ObjectiveFunc = func(x []float64) float64 {
+		calls++
+		return Sphere(x)
+	}
+
+	result, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if result.FuncEvalCount != calls {
+		t.Errorf("FuncEvalCount = %d, objective called %d times", result.FuncEvalCount, calls)
+	}
+}
+
+func TestComparisonRunnerWithHybrid(t *testing.T) {
+	runner := NewComparisonRunner().
+		WithVariantNames("desma", "desma-olce").
+		WithRuns(2).
+		WithIterations(20)
+
+	result := runner.Compare("Sphere", Sphere, 5, -10, 10)
+
+	if len(result.AlgorithmNames) != 2 || result.AlgorithmNames[1] != "DESMA+OLCE-MA" {
+		t.Errorf("Unexpected algorithm names %v", result.AlgorithmNames)
+	}
+}
@@ -0,0 +1,270 @@
+// Package mayfly - Hypervolume
+//
+// Implements the hypervolume indicator for any number of objectives
+// (minimization). The hypervolume of a set of points is the volume of the
+// objective space dominated by the points and bounded by a reference point.
+//
+//   - Hypervolume computes the exact value with the WFG algorithm (While,
+//     Bradstreet & Barone, 2012); two objectives use a sweep in O(n log n)
+//   - HypervolumeMonteCarlo estimates the value by uniform sampling, for
+//     objective counts where the exact computation becomes too expensive
+//   - HypervolumeContributions returns the exclusive contribution of every
+//     point: the volume lost when the point is removed
+//   - TruncateByHypervolume reduces a set of solutions by repeatedly removing
+//     the least contributor of the worst front (SMS-EMOA, Beume et al., 2007)
+//
+// Points that do not strictly dominate the reference point contribute nothing.
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Hypervolume returns the exact hypervolume of points with respect to
+// reference. All points must have the same number of objectives as reference.
+func Hypervolume(points [][]float64, reference []float64) float64 {
+	return wfg(nondominatedPoints(clipToReference(points, reference)), reference)
+}
+
+// HypervolumeMonteCarlo estimates the hypervolume of points with the given
+// number of uniform samples in the box between the ideal point and
+// reference. The standard error decreases with 1/sqrt(samples). If rng is
+// nil, the global random source is used.
+func HypervolumeMonteCarlo(points [][]float64, reference []float64, samples int, rng *rand.Rand) float64 {
+	points = nondominatedPoints(clipToReference(points, reference))
+	if len(points) == 0 || samples <= 0 {
+		return 0
+	}
+
+	// Sampling box from the ideal point to the reference point
+	ideal := append([]float64(nil), points[0]...)
+	for _, p := range points[1:] {
+		for j, v := range p {
+			ideal[j] = math.Min(ideal[j], v)
+		}
+	}
+
+	box := 1.0
+	for j := range reference {
+		box *= reference[j] - ideal[j]
+	}
+
+	sample := make([]float64, len(reference))
+	hits := 0
+
+	for k := 0; k < samples; k++ {
+		for j := range sample {
+			sample[j] = unifrnd(ideal[j], reference[j], rng)
+		}
+
+		for _, p := range points {
+			if weaklyDominates(p, sample) {
+				hits++
+				break
+			}
+		}
+	}
+
+	return box * float64(hits) / float64(samples)
+}
+
+// HypervolumeContributions returns the exclusive hypervolume contribution
+// of every point, in the order of points. Dominated points, duplicates and
+// points outside the reference point contribute 0.
+func HypervolumeContributions(points [][]float64, reference []float64) []float64 {
+	contributions := make([]float64, len(points))
+
+	for i, p := range points {
+		if !strictlyInside(p, reference) {
+			continue
+		}
+
+		// Volume dominated by p minus the part also dominated by the others
+		limited := make([][]float64, 0, len(points)-1)
+		for k, q := range points {
+			if k != i && strictlyInside(q, reference) {
+				limited = append(limited, worsePoint(p, q))
+			}
+		}
+
+		contributions[i] = math.Max(0, boxVolume(p, reference)-wfg(nondominatedPoints(limited), reference))
+	}
+
+	return contributions
+}
+
+// TruncateByHypervolume returns size solutions of solutions. Solutions are
+// removed one at a time from the worst non-dominated front, choosing the
+// one with the smallest exclusive hypervolume contribution within that
+// front. The Rank of the solutions is updated as a side effect.
+func TruncateByHypervolume(solutions []*ParetoSolution, reference []float64, size int) []*ParetoSolution {
+	remaining := append([]*ParetoSolution(nil), solutions...)
+
+	for len(remaining) > size {
+		fronts := fastNonDominatedSort(remaining)
+		worst := fronts[len(fronts)-1]
+
+		remove := worst[0]
+
+		if len(worst) > 1 {
+			contributions := HypervolumeContributions(objectiveVectors(remaining, worst), reference)
+			least := 0
+			for k, c := range contributions {
+				if c < contributions[least] {
+					least = k
+				}
+			}
+
+			remove = worst[least]
+		}
+
+		remaining = append(remaining[:remove], remaining[remove+1:]...)
+	}
+
+	return remaining
+}
+
+// objectiveVectors returns the objective vectors of the solutions at indices.
+func objectiveVectors(solutions []*ParetoSolution, indices []int) [][]float64 {
+	points := make([][]float64, len(indices))
+	for k, i := range indices {
+		points[k] = solutions[i].ObjectiveValues
+	}
+
+	return points
+}
+
+// wfg returns the hypervolume of a set of mutually non-dominated points
+// inside the reference point.
+func wfg(points [][]float64, reference []float64) float64 {
+	switch {
+	case len(points) == 0:
+		return 0
+	case len(points) == 1:
+		return boxVolume(points[0], reference)
+	case len(reference) == 1:
+		return reference[0] - points[0][0]
+	case len(reference) == 2:
+		return sweep2D(points, reference)
+	}
+
+	// Processing the points in order of the last objective keeps the limit
+	// sets small.
+	sorted := append([][]float64(nil), points...)
+	last := len(reference) - 1
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i][last] > sorted[j][last]
+	})
+
+	volume := 0.0
+
+	for k, p := range sorted {
+		limited := make([][]float64, 0, len(sorted)-k-1)
+		for _, q := range sorted[k+1:] {
+			limited = append(limited, worsePoint(p, q))
+		}
+
+		volume += boxVolume(p, reference) - wfg(nondominatedPoints(limited), reference)
+	}
+
+	return volume
+}
+
+// sweep2D returns the hypervolume of mutually non-dominated points in two objectives.
+func sweep2D(points [][]float64, reference []float64) float64 {
+	sorted := append([][]float64(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i][0] < sorted[j][0]
+	})
+
+	volume := 0.0
+	previous := reference[1]
+
+	for _, p := range sorted {
+		if p[1] < previous {
+			volume += (reference[0] - p[0]) * (previous - p[1])
+			previous = p[1]
+		}
+	}
+
+	return volume
+}
+
+// clipToReference returns the points that strictly dominate reference.
+func clipToReference(points [][]float64, reference []float64) [][]float64 {
+	inside := make([][]float64, 0, len(points))
+	for _, p := range points {
+		if strictlyInside(p, reference) {
+			inside = append(inside, p)
+		}
+	}
+
+	return inside
+}
+
+// nondominatedPoints returns the points not weakly dominated by another
+// point. Of several equal points only the first is kept.
+func nondominatedPoints(points [][]float64) [][]float64 {
+	result := make([][]float64, 0, len(points))
+
+	for i, p := range points {
+		dominated := false
+
+		for k, q := range points {
+			if k != i && (dominates(q, p) || (k < i && equalObjectives(q, p))) {
+				dominated = true
+				break
+			}
+		}
+
+		if !dominated {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+// strictlyInside reports whether p is better than reference in every objective.
+func strictlyInside(p, reference []float64) bool {
+	for j := range reference {
+		if !(p[j] < reference[j]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// weaklyDominates reports whether a is no worse than b in every objective.
+func weaklyDominates(a, b []float64) bool {
+	for j := range a {
+		if a[j] > b[j] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// worsePoint returns the componentwise maximum of a and b.
+func worsePoint(a, b []float64) []float64 {
+	w := make([]float64, len(a))
+	for j := range a {
+		w[j] = math.Max(a[j], b[j])
+	}
+
+	return w
+}
+
+// boxVolume returns the volume of the box between p and reference.
+func boxVolume(p, reference []float64) float64 {
+	volume := 1.0
+	for j := range reference {
+		volume *= reference[j] - p[j]
+	}
+
+	return volume
+}
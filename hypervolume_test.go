@@ -0,0 +1,172 @@
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for hypervolume.go - Hypervolume indicator
+// =============================================================================
+
+// inclusionExclusionHypervolume computes the hypervolume by the
+// inclusion-exclusion principle over all subsets of points.
+func inclusionExclusionHypervolume(points [][]float64, reference []float64) float64 {
+	points = clipToReference(points, reference)
+	volume := 0.0
+
+	for mask := 1; mask < 1<<len(points); mask++ {
+		var corner []float64
+
+		sign := -1.0
+		for i, p := range points {
+			if mask&(1<<i) == 0 {
+				continue
+			}
+
+			sign = -sign
+			if corner == nil {
+				corner = p
+			} else {
+				corner = worsePoint(corner, p)
+			}
+		}
+
+		volume += sign * boxVolume(corner, reference)
+	}
+
+	return volume
+}
+
+func randomFront(rng *rand.Rand, n, m int) [][]float64 {
+	points := make([][]float64, n)
+	for i := range points {
+		points[i] = make([]float64, m)
+		for j := range points[i] {
+			points[i][j] = rng.Float64()
+		}
+	}
+
+	return points
+}
+
+func TestHypervolumeKnownValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		points    [][]float64
+		reference []float64
+		expected  float64
+	}{
+		{"Empty", nil, []float64{1, 1, 1}, 0},
+		{"2D staircase", [][]float64{{1, 3}, {2, 2}, {3, 1}}, []float64{5, 5}, 13},
+		{"3D box", [][]float64{{0, 0, 0}}, []float64{1, 2, 3}, 6},
+		{"3D three points", [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}, []float64{2, 2, 2}, 7},
+		{"3D dominated point", [][]float64{{1, 1, 1}, {0, 0, 0}}, []float64{2, 2, 2}, 8},
+		{"3D duplicates", [][]float64{{1, 1, 1}, {1, 1, 1}}, []float64{2, 2, 2}, 1},
+		{"4D box", [][]float64{{0, 0, 0, 0}}, []float64{1, 1, 2, 2}, 4},
+		{"Outside reference", [][]float64{{3, 0, 0}, {0, 2, 0}}, []float64{2, 2, 2}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Hypervolume(tt.points, tt.reference); math.Abs(got-tt.expected) > 1e-12 {
+				t.Errorf("Hypervolume = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHypervolumeMatchesInclusionExclusion(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, m := range []int{3, 4, 5} {
+		for trial := 0; trial < 20; trial++ {
+			points := randomFront(rng, 8, m)
+			reference := []float64{1.1, 1.1, 1.1, 1.1, 1.1}[:m]
+
+			exact := Hypervolume(points, reference)
+			expected := inclusionExclusionHypervolume(points, reference)
+
+			if math.Abs(exact-expected) > 1e-9 {
+				t.Fatalf("M=%d: Hypervolume = %v, inclusion-exclusion = %v", m, exact, expected)
+			}
+		}
+	}
+}
+
+func TestHypervolumeMonteCarlo(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	points := randomFront(rng, 20, 3)
+	reference := []float64{1, 1, 1}
+
+	exact := Hypervolume(points, reference)
+	estimate := HypervolumeMonteCarlo(points, reference, 200000, rng)
+
+	if math.Abs(estimate-exact) > 0.01 {
+		t.Errorf("Monte Carlo estimate %v too far from exact %v", estimate, exact)
+	}
+
+	if got := HypervolumeMonteCarlo(nil, reference, 100, rng); got != 0 {
+		t.Errorf("Empty set should have hypervolume 0, got %v", got)
+	}
+}
+
+func TestHypervolumeContributions(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	points := randomFront(rng, 10, 3)
+	points = append(points, []float64{2, 0, 0}) // Outside the reference point
+	reference := []float64{1, 1, 1}
+
+	total := Hypervolume(points, reference)
+	contributions := HypervolumeContributions(points, reference)
+
+	for i := range points {
+		others := append(append([][]float64(nil), points[:i]...), points[i+1:]...)
+		expected := total - Hypervolume(others, reference)
+
+		if math.Abs(contributions[i]-expected) > 1e-9 {
+			t.Errorf("Contribution %d = %v, expected %v", i, contributions[i], expected)
+		}
+	}
+
+	if contributions[len(points)-1] != 0 {
+		t.Errorf("Point outside the reference should contribute 0, got %v", contributions[len(points)-1])
+	}
+
+	// Duplicates do not contribute exclusively
+	duplicates := HypervolumeContributions([][]float64{{0.5, 0.5}, {0.5, 0.5}}, []float64{1, 1})
+	if duplicates[0] != 0 || duplicates[1] != 0 {
+		t.Errorf("Duplicates should contribute 0, got %v", duplicates)
+	}
+}
+
+func TestTruncateByHypervolume(t *testing.T) {
+	var solutions []*ParetoSolution
+	for _, objectives := range [][]float64{{0, 1}, {0.1, 0.9}, {0.5, 0.5}, {0.55, 0.46}, {1, 0}, {0.6, 0.6}} {
+		solutions = append(solutions, &ParetoSolution{ObjectiveValues: objectives})
+	}
+
+	reference := []float64{2, 2}
+	kept := TruncateByHypervolume(solutions, reference, 4)
+
+	if len(kept) != 4 {
+		t.Fatalf("Expected 4 solutions, got %d", len(kept))
+	}
+
+	// The dominated solution goes first, then the least contributor
+	for _, s := range kept {
+		if s.ObjectiveValues[0] == 0.6 || s.ObjectiveValues[0] == 0.55 {
+			t.Errorf("Solution %v should have been removed", s.ObjectiveValues)
+		}
+	}
+
+	// Extremes have large contributions and survive
+	if kept[0].ObjectiveValues[0] != 0 || kept[3].ObjectiveValues[0] != 1 {
+		t.Errorf("Extremes should survive, got %v", objectiveVectors(kept, []int{0, 1, 2, 3}))
+	}
+
+	if len(solutions) != 6 {
+		t.Error("Input slice should not be modified")
+	}
+}
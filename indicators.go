@@ -0,0 +1,361 @@
+// Package mayfly - Multi-objective quality indicators
+//
+// Indicators for scoring an obtained Pareto front, optionally against a
+// reference front (usually the true Pareto front). All objectives are
+// minimized.
+//
+// Convergence and diversity:
+//   - CalculateHypervolume: dominated volume up to a reference point (higher is better)
+//   - CalculateIGD, CalculateIGDPlus: distance from the reference front to the obtained front
+//   - CalculateR2: mean best Tchebycheff utility over a set of weight vectors
+//
+// Convergence:
+//   - CalculateGD: distance from the obtained front to the reference front
+//   - CalculateAdditiveEpsilon, CalculateMultiplicativeEpsilon: smallest
+//     translation/factor by which the obtained front covers the reference front
+//
+// Diversity:
+//   - CalculateSpread: Deb's Δ, generalized to any number of objectives
+//   - CalculateSpacing: Schott's spacing
+//
+// Indicators compare objective values directly, so objectives of very
+// different scales should be normalized first with NormalizeFront.
+package mayfly
+
+import (
+	"math"
+)
+
+// CalculateHypervolume calculates the hypervolume indicator for a Pareto front.
+// The hypervolume is the volume of the objective space dominated by the front.
+// Higher values indicate better convergence and diversity.
+//
+// Parameters:
+//   - solutions: Solutions in the Pareto front
+//   - referencePoint: Worst acceptable point (usually slightly worse than nadir point)
+//
+// Any number of objectives is supported; see Hypervolume.
+func CalculateHypervolume(solutions []*ParetoSolution, referencePoint []float64) float64 {
+	return Hypervolume(frontPoints(solutions), referencePoint)
+}
+
+// CalculateIGD calculates the Inverted Generational Distance (IGD) metric.
+// IGD measures both convergence and diversity by computing the average
+// distance from each point in the true Pareto front to the nearest
+// point in the obtained front.
+//
+// Lower values indicate better performance (closer to true Pareto front).
+//
+// Parameters:
+//   - obtainedFront: The Pareto front obtained by the algorithm
+//   - trueFront: The true/reference Pareto front (if known)
+//
+// Returns:
+//   - IGD value (lower is better)
+func CalculateIGD(obtainedFront, trueFront []*ParetoSolution) float64 {
+	return meanNearestDistance(trueFront, obtainedFront, euclideanDistance)
+}
+
+// CalculateGD calculates the Generational Distance (GD): the average distance
+// from each point in the obtained front to the nearest point in the true
+// front. GD only measures convergence.
+//
+// Returns:
+//   - GD value (lower is better), +Inf if either front is empty
+func CalculateGD(obtainedFront, trueFront []*ParetoSolution) float64 {
+	return meanNearestDistance(obtainedFront, trueFront, euclideanDistance)
+}
+
+// CalculateIGDPlus calculates IGD+ (Ishibuchi et al., 2015). It is IGD with
+// a distance that only counts the objectives in which the obtained point is
+// worse than the true point, which makes it weakly Pareto compliant.
+//
+// Returns:
+//   - IGD+ value (lower is better), +Inf if either front is empty
+func CalculateIGDPlus(obtainedFront, trueFront []*ParetoSolution) float64 {
+	return meanNearestDistance(trueFront, obtainedFront, func(truePoint, obtainedPoint []float64) float64 {
+		sum := 0.0
+
+		for i := range truePoint {
+			if d := obtainedPoint[i] - truePoint[i]; d > 0 {
+				sum += d * d
+			}
+		}
+
+		return math.Sqrt(sum)
+	})
+}
+
+// CalculateAdditiveEpsilon calculates the additive epsilon indicator: the
+// smallest ε such that every point of the true front is weakly dominated by
+// some obtained point translated by -ε.
+//
+// Returns:
+//   - ε (lower is better, ≤ 0 if the obtained front covers the true front),
+//     +Inf if either front is empty
+func CalculateAdditiveEpsilon(obtainedFront, trueFront []*ParetoSolution) float64 {
+	return epsilonIndicator(obtainedFront, trueFront, func(a, r float64) float64 {
+		return a - r
+	})
+}
+
+// CalculateMultiplicativeEpsilon calculates the multiplicative epsilon
+// indicator: the smallest ε such that every point of the true front is weakly
+// dominated by some obtained point divided by ε. All objective values must be
+// positive.
+//
+// Returns:
+//   - ε (lower is better, ≤ 1 if the obtained front covers the true front),
+//     +Inf if either front is empty
+func CalculateMultiplicativeEpsilon(obtainedFront, trueFront []*ParetoSolution) float64 {
+	return epsilonIndicator(obtainedFront, trueFront, func(a, r float64) float64 {
+		return a / r
+	})
+}
+
+// CalculateSpread calculates the spread Δ (Deb et al., 2002) in its
+// generalized form for any number of objectives (Zhou et al., 2006):
+//
+//	Δ = (Σ d(e_m) + Σ |d_i - d̄|) / (Σ d(e_m) + N·d̄)
+//
+// where d(e_m) is the distance from the extreme point of the true front in
+// objective m to the obtained front and d_i is the distance from obtained
+// point i to its nearest neighbour. Δ is 0 for a uniformly spread front that
+// reaches the extremes.
+//
+// Returns:
+//   - Δ (lower is better), +Inf if either front is empty
+func CalculateSpread(obtainedFront, trueFront []*ParetoSolution) float64 {
+	if len(obtainedFront) == 0 || len(trueFront) == 0 {
+		return math.Inf(1)
+	}
+
+	// Distance of the true extremes to the obtained front
+	extremes := 0.0
+
+	for m := range trueFront[0].ObjectiveValues {
+		extreme := trueFront[0]
+		for _, sol := range trueFront[1:] {
+			if sol.ObjectiveValues[m] < extreme.ObjectiveValues[m] {
+				extreme = sol
+			}
+		}
+
+		extremes += nearestDistance(extreme.ObjectiveValues, obtainedFront, -1, euclideanDistance)
+	}
+
+	distances, mean := nearestNeighbourDistances(obtainedFront, euclideanDistance)
+
+	deviation := 0.0
+	for _, d := range distances {
+		deviation += math.Abs(d - mean)
+	}
+
+	denominator := extremes + float64(len(obtainedFront))*mean
+	if denominator == 0 {
+		return 0
+	}
+
+	return (extremes + deviation) / denominator
+}
+
+// CalculateSpacing calculates Schott's spacing: the standard deviation of the
+// Manhattan distances from every obtained point to its nearest neighbour.
+// It is 0 for equally spaced points.
+//
+// Returns:
+//   - Spacing (lower is better), 0 for fewer than two solutions
+func CalculateSpacing(front []*ParetoSolution) float64 {
+	if len(front) < 2 {
+		return 0
+	}
+
+	distances, mean := nearestNeighbourDistances(front, func(a, b []float64) float64 {
+		sum := 0.0
+		for i := range a {
+			sum += math.Abs(a[i] - b[i])
+		}
+
+		return sum
+	})
+
+	variance := 0.0
+	for _, d := range distances {
+		variance += (d - mean) * (d - mean)
+	}
+
+	return math.Sqrt(variance / float64(len(distances)-1))
+}
+
+// CalculateR2 calculates the R2 indicator (Hansen & Jaszkiewicz, 1998) with
+// the Tchebycheff utility: the average over all weight vectors of the best
+// value of max_j w_j·|f_j - z_j| in the front, where z is the ideal point.
+//
+// Parameters:
+//   - front: The obtained Pareto front
+//   - weights: Weight vectors, one entry per objective each
+//   - ideal: Utopian reference point, at least as good as every point
+//
+// Returns:
+//   - R2 value (lower is better), +Inf if front or weights are empty
+func CalculateR2(front []*ParetoSolution, weights [][]float64, ideal []float64) float64 {
+	if len(front) == 0 || len(weights) == 0 {
+		return math.Inf(1)
+	}
+
+	total := 0.0
+
+	for _, w := range weights {
+		best := math.Inf(1)
+
+		for _, sol := range front {
+			utility := 0.0
+			for j, f := range sol.ObjectiveValues {
+				utility = math.Max(utility, w[j]*math.Abs(f-ideal[j]))
+			}
+
+			best = math.Min(best, utility)
+		}
+
+		total += best
+	}
+
+	return total / float64(len(weights))
+}
+
+// IdealPoint returns the componentwise minimum of the objective values of
+// the front, or nil for an empty front.
+func IdealPoint(front []*ParetoSolution) []float64 {
+	return frontBound(front, math.Min)
+}
+
+// NadirPoint returns the componentwise maximum of the objective values of
+// the front, or nil for an empty front. For a non-dominated front this is
+// the nadir point.
+func NadirPoint(front []*ParetoSolution) []float64 {
+	return frontBound(front, math.Max)
+}
+
+// NormalizeFront returns copies of the solutions with their objective values
+// mapped linearly from [ideal, nadir] to [0, 1]. Objectives with
+// ideal == nadir are mapped to 0. Pass the bounds of the true front to
+// normalize an obtained front and its reference front consistently. The
+// input solutions are not modified.
+func NormalizeFront(front []*ParetoSolution, ideal, nadir []float64) []*ParetoSolution {
+	normalized := make([]*ParetoSolution, len(front))
+
+	for i, sol := range front {
+		copied := *sol
+		copied.ObjectiveValues = make([]float64, len(sol.ObjectiveValues))
+
+		for j, f := range sol.ObjectiveValues {
+			if span := nadir[j] - ideal[j]; span > 0 {
+				copied.ObjectiveValues[j] = (f - ideal[j]) / span
+			}
+		}
+
+		normalized[i] = &copied
+	}
+
+	return normalized
+}
+
+// frontPoints returns the objective vectors of the solutions.
+func frontPoints(solutions []*ParetoSolution) [][]float64 {
+	points := make([][]float64, len(solutions))
+	for i, sol := range solutions {
+		points[i] = sol.ObjectiveValues
+	}
+
+	return points
+}
+
+// frontBound folds the objective values of the front with bound.
+func frontBound(front []*ParetoSolution, bound func(a, b float64) float64) []float64 {
+	if len(front) == 0 {
+		return nil
+	}
+
+	point := append([]float64(nil), front[0].ObjectiveValues...)
+	for _, sol := range front[1:] {
+		for j, f := range sol.ObjectiveValues {
+			point[j] = bound(point[j], f)
+		}
+	}
+
+	return point
+}
+
+// meanNearestDistance returns the average distance from each point of from
+// to its nearest point in to, or +Inf if either set is empty.
+func meanNearestDistance(from, to []*ParetoSolution, distance func(a, b []float64) float64) float64 {
+	if len(from) == 0 || len(to) == 0 {
+		return math.Inf(1)
+	}
+
+	total := 0.0
+	for _, sol := range from {
+		total += nearestDistance(sol.ObjectiveValues, to, -1, distance)
+	}
+
+	return total / float64(len(from))
+}
+
+// nearestDistance returns the distance from point to the nearest solution
+// of front, skipping the solution at index skip.
+func nearestDistance(point []float64, front []*ParetoSolution, skip int, distance func(a, b []float64) float64) float64 {
+	nearest := math.Inf(1)
+
+	for i, sol := range front {
+		if i != skip {
+			nearest = math.Min(nearest, distance(point, sol.ObjectiveValues))
+		}
+	}
+
+	return nearest
+}
+
+// nearestNeighbourDistances returns the distance from every solution of the
+// front to its nearest neighbour, and their mean. A single solution has
+// distance 0.
+func nearestNeighbourDistances(front []*ParetoSolution, distance func(a, b []float64) float64) ([]float64, float64) {
+	distances := make([]float64, len(front))
+	if len(front) < 2 {
+		return distances, 0
+	}
+
+	mean := 0.0
+	for i, sol := range front {
+		distances[i] = nearestDistance(sol.ObjectiveValues, front, i, distance)
+		mean += distances[i]
+	}
+
+	return distances, mean / float64(len(front))
+}
+
+// epsilonIndicator returns max over true points r of min over obtained
+// points a of max_j diff(a_j, r_j).
+func epsilonIndicator(obtainedFront, trueFront []*ParetoSolution, diff func(a, r float64) float64) float64 {
+	if len(obtainedFront) == 0 || len(trueFront) == 0 {
+		return math.Inf(1)
+	}
+
+	epsilon := math.Inf(-1)
+
+	for _, r := range trueFront {
+		best := math.Inf(1)
+
+		for _, a := range obtainedFront {
+			worst := math.Inf(-1)
+			for j := range r.ObjectiveValues {
+				worst = math.Max(worst, diff(a.ObjectiveValues[j], r.ObjectiveValues[j]))
+			}
+
+			best = math.Min(best, worst)
+		}
+
+		epsilon = math.Max(epsilon, best)
+	}
+
+	return epsilon
+}
@@ -0,0 +1,120 @@
+package mayfly
+
+import (
+	"math"
+	"testing"
+)
+
+// =============================================================================
+// Tests for indicators.go - Multi-objective quality indicators
+// =============================================================================
+
+func paretoFront(points ...[]float64) []*ParetoSolution {
+	front := make([]*ParetoSolution, len(points))
+	for i, p := range points {
+		front[i] = &ParetoSolution{ObjectiveValues: p}
+	}
+
+	return front
+}
+
+func TestQualityIndicators(t *testing.T) {
+	trueFront := paretoFront([]float64{0, 1}, []float64{0.5, 0.5}, []float64{1, 0})
+	shifted := paretoFront([]float64{0, 1.2}, []float64{0.6, 0.6}, []float64{1, 0.2})
+	uneven := paretoFront([]float64{0, 1}, []float64{0.1, 0.9}, []float64{1, 0})
+	ideal := paretoFront([]float64{0, 0})
+
+	tests := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"GD", CalculateGD(shifted, trueFront), (0.4 + math.Sqrt(0.02)) / 3},
+		{"GD of true front", CalculateGD(trueFront, trueFront), 0},
+		{"IGD of ideal point", CalculateIGD(ideal, trueFront), (2 + math.Sqrt(0.5)) / 3},
+		{"IGD+ of ideal point", CalculateIGDPlus(ideal, trueFront), 0},
+		{"IGD+", CalculateIGDPlus(shifted, trueFront), (0.4 + math.Sqrt(0.02)) / 3},
+		{"Additive epsilon", CalculateAdditiveEpsilon(shifted, trueFront), 0.2},
+		{"Additive epsilon of ideal point", CalculateAdditiveEpsilon(ideal, trueFront), 0},
+		{"Spread of true front", CalculateSpread(trueFront, trueFront), 0},
+		{"Spread of single point", CalculateSpread(paretoFront([]float64{0.5, 0.5}), trueFront), 1},
+		{"Spacing of true front", CalculateSpacing(trueFront), 0},
+		{"Spacing", CalculateSpacing(uneven), math.Sqrt((2*math.Pow(0.2-2.2/3, 2) + math.Pow(1.8-2.2/3, 2)) / 2)},
+		{"R2", CalculateR2(trueFront, [][]float64{{1, 0}, {0, 1}, {0.5, 0.5}}, []float64{0, 0}), 0.25 / 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if math.Abs(tt.got-tt.expected) > 1e-9 {
+				t.Errorf("Got %v, expected %v", tt.got, tt.expected)
+			}
+		})
+	}
+
+	if spread := CalculateSpread(uneven, trueFront); spread < 0.5 {
+		t.Errorf("Uneven front should have a large spread, got %v", spread)
+	}
+}
+
+func TestMultiplicativeEpsilon(t *testing.T) {
+	trueFront := paretoFront([]float64{1, 2}, []float64{2, 1})
+	doubled := paretoFront([]float64{2, 4}, []float64{4, 2})
+
+	if eps := CalculateMultiplicativeEpsilon(doubled, trueFront); math.Abs(eps-2) > 1e-12 {
+		t.Errorf("Expected epsilon 2, got %v", eps)
+	}
+
+	if eps := CalculateMultiplicativeEpsilon(trueFront, trueFront); math.Abs(eps-1) > 1e-12 {
+		t.Errorf("Expected epsilon 1, got %v", eps)
+	}
+}
+
+func TestIndicatorsEmptyFronts(t *testing.T) {
+	front := paretoFront([]float64{0, 1})
+
+	for name, value := range map[string]float64{
+		"GD":                     CalculateGD(nil, front),
+		"IGD+":                   CalculateIGDPlus(front, nil),
+		"Additive epsilon":       CalculateAdditiveEpsilon(nil, front),
+		"Multiplicative epsilon": CalculateMultiplicativeEpsilon(front, nil),
+		"Spread":                 CalculateSpread(nil, front),
+		"R2 without weights":     CalculateR2(front, nil, []float64{0, 0}),
+		"R2 without solutions":   CalculateR2(nil, [][]float64{{1, 0}}, []float64{0, 0}),
+	} {
+		if !math.IsInf(value, 1) {
+			t.Errorf("%s: expected +Inf, got %v", name, value)
+		}
+	}
+
+	if spacing := CalculateSpacing(front); spacing != 0 {
+		t.Errorf("Spacing of a single solution should be 0, got %v", spacing)
+	}
+}
+
+func TestNormalizeFront(t *testing.T) {
+	front := paretoFront([]float64{1, 10, 5}, []float64{3, 30, 5}, []float64{2, 20, 5})
+
+	ideal, nadir := IdealPoint(front), NadirPoint(front)
+	if ideal[0] != 1 || ideal[1] != 10 || nadir[0] != 3 || nadir[1] != 30 {
+		t.Fatalf("Unexpected bounds %v, %v", ideal, nadir)
+	}
+
+	normalized := NormalizeFront(front, ideal, nadir)
+
+	expected := [][]float64{{0, 0, 0}, {1, 1, 0}, {0.5, 0.5, 0}}
+	for i, sol := range normalized {
+		for j, f := range sol.ObjectiveValues {
+			if math.Abs(f-expected[i][j]) > 1e-12 {
+				t.Errorf("Normalized[%d][%d] = %v, expected %v", i, j, f, expected[i][j])
+			}
+		}
+	}
+
+	if front[1].ObjectiveValues[0] != 3 {
+		t.Error("NormalizeFront should not modify its input")
+	}
+
+	if IdealPoint(nil) != nil || NadirPoint(nil) != nil {
+		t.Error("Bounds of an empty front should be nil")
+	}
+}
@@ -0,0 +1,426 @@
+// Package mayfly - Island Model
+//
+// Implements a multi-population mode in which K independent swarms (islands)
+// evolve concurrently and periodically exchange their best mayflies.
+//
+// Every island runs its own pipeline, so islands can use different variants
+// (heterogeneous islands). Islands advance in epochs of MigrationInterval
+// iterations, each island in its own goroutine. Between epochs the islands
+// are synchronized and migration takes place:
+//   - Topology "ring": island i receives migrants from island i-1
+//   - Topology "full": island i receives the best migrants of all other islands
+//   - Topology "random": island i receives migrants from one random other island
+//
+// Each island sends its best MigrationRate fraction of males (at least one).
+// With ReplacementPolicy "worst" the migrants replace the worst males of the
+// receiving island (best-replace-worst); with "random" they replace random
+// males other than the island's best.
+//
+// The objective function is called concurrently from several goroutines and
+// must be safe for concurrent use.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// Island topologies accepted by IslandModel.Topology.
+const (
+	TopologyRing   = "ring"
+	TopologyFull   = "full"
+	TopologyRandom = "random"
+)
+
+// Replacement policies accepted by IslandModel.ReplacementPolicy.
+const (
+	ReplaceWorst  = "worst"
+	ReplaceRandom = "random"
+)
+
+// Migration schedule for an unset MigrationInterval or MigrationRate: every
+// ten iterations each island sends a tenth of its males.
+const (
+	defaultMigrationInterval = 10
+	defaultMigrationRate     = 0.1
+)
+
+// Island is one sub-population of an IslandModel.
+type Island struct {
+	Name     string    // Label used in IslandResult (defaults to "island-<i>")
+	Config   *Config   // Configuration of the island's swarm
+	Pipeline *Pipeline // Optional custom pipeline (nil = NewPipeline(Config))
+}
+
+// IslandModel configures a multi-population optimization.
+type IslandModel struct {
+	Islands           []*Island
+	Topology          string     // "ring" (default), "full" or "random"
+	MigrationInterval int        // Iterations between migrations (default 10)
+	MigrationRate     float64    // Fraction of each island's males that migrate (default 0.1)
+	ReplacementPolicy string     // "worst" (default) or "random"
+	Rand              *rand.Rand // Used for random topology and replacement (nil = time-seeded)
+}
+
+// IslandResult holds the combined result of an island model run.
+// The embedded Result aggregates all islands: GlobalBest is the best of all
+// islands, BestSolution the best cost over all islands per iteration,
+// FuncEvalCount the total number of evaluations and Optima (niching mode)
+// the distinct optima of all islands.
+type IslandResult struct {
+	Result
+	Islands     []*Result // Per-island results
+	IslandNames []string  // Names of the islands, in the same order
+	BestIsland  int       // Index of the island that found GlobalBest
+	Migrations  int       // Number of migration events
+}
+
+// NewIslandModel creates one island per variant name (e.g. "ma", "desma",
+// "olce"). Each island uses the variant's default configuration with the
+// problem definition (ObjectiveFunc, ProblemSize, bounds, MaxIterations) and
+// population sizes (NPop, NPopF, NC, NM) of base. A name may be repeated to
+// create several islands of the same variant.
+//
+// If base.Rand is set, every island and the migration process get their own
+// generator seeded from it, which makes the run reproducible.
+func NewIslandModel(base *Config, variants ...string) (*IslandModel, error) {
+	if base == nil {
+		return nil, fmt.Errorf("base config cannot be nil")
+	}
+
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("at least one island variant is required")
+	}
+
+	model := &IslandModel{Islands: make([]*Island, 0, len(variants))}
+
+	for i, name := range variants {
+		variant := NewVariant(name)
+		if variant == nil {
+			return nil, fmt.Errorf("unknown variant: %s", name)
+		}
+
+		config := variant.GetConfig()
+		config.ObjectiveFunc = base.ObjectiveFunc
+		config.Gradient = base.Gradient
+		config.ProblemSize = base.ProblemSize
+		config.LowerBound = base.LowerBound
+		config.UpperBound = base.UpperBound
+		config.MaxIterations = base.MaxIterations
+		config.NPop = base.NPop
+		config.NPopF = base.NPopF
+		config.NC = base.NC
+		config.NM = base.NM
+
+		if base.Rand != nil {
+			config.Rand = rand.New(rand.NewSource(base.Rand.Int63()))
+		}
+
+		island := &Island{Name: fmt.Sprintf("%s-%d", variant.Name(), i), Config: config}
+
+		switch v := variant.(type) {
+		case PipelineVariant:
+			island.Pipeline = v.Pipeline(config)
+		case VariantOptimizer:
+			return nil, fmt.Errorf("variant %s supplies its own optimizer and cannot run on an island", variant.Name())
+		}
+
+		model.Islands = append(model.Islands, island)
+	}
+
+	if base.Rand != nil {
+		model.Rand = rand.New(rand.NewSource(base.Rand.Int63()))
+	}
+
+	return model, nil
+}
+
+// validate checks the model and fills in defaults.
+func (m *IslandModel) validate() error {
+	if len(m.Islands) == 0 {
+		return fmt.Errorf("island model needs at least one island")
+	}
+
+	switch m.Topology {
+	case "", TopologyRing, TopologyFull, TopologyRandom:
+	default:
+		return fmt.Errorf("unknown topology '%s' (expected ring, full or random)", m.Topology)
+	}
+
+	switch m.ReplacementPolicy {
+	case "", ReplaceWorst, ReplaceRandom:
+	default:
+		return fmt.Errorf("unknown replacement policy '%s' (expected worst or random)", m.ReplacementPolicy)
+	}
+
+	if m.MigrationInterval < 0 {
+		return fmt.Errorf("MigrationInterval must be non-negative, got %d", m.MigrationInterval)
+	}
+
+	if m.MigrationRate < 0 || m.MigrationRate > 1 {
+		return fmt.Errorf("MigrationRate must be in [0, 1], got %v", m.MigrationRate)
+	}
+
+	rngs := make(map[*rand.Rand]bool)
+
+	for i, island := range m.Islands {
+		if island == nil || island.Config == nil {
+			return fmt.Errorf("island %d has no config", i)
+		}
+
+		if err := validateOptimizeConfig(island.Config); err != nil {
+			return fmt.Errorf("island %d: %w", i, err)
+		}
+
+		if island.Config.MaxIterations != m.Islands[0].Config.MaxIterations {
+			return fmt.Errorf("island %d: all islands must use the same MaxIterations (%d != %d)",
+				i, island.Config.MaxIterations, m.Islands[0].Config.MaxIterations)
+		}
+
+		if rng := island.Config.Rand; rng != nil {
+			if rngs[rng] || rng == m.Rand {
+				return fmt.Errorf("island %d shares its random number generator (islands run concurrently)", i)
+			}
+
+			rngs[rng] = true
+		}
+
+		if p := island.Pipeline; p != nil && (p.Mover == nil || p.Crossover == nil || p.Mutation == nil) {
+			return fmt.Errorf("island %d: pipeline requires a Mover, Crossover and Mutation operator", i)
+		}
+	}
+
+	return nil
+}
+
+// OptimizeIslands runs the island model and returns the combined result.
+func OptimizeIslands(model *IslandModel) (*IslandResult, error) {
+	if model == nil {
+		return nil, fmt.Errorf("island model cannot be nil")
+	}
+
+	if err := model.validate(); err != nil {
+		return nil, err
+	}
+
+	interval := model.MigrationInterval
+	if interval == 0 {
+		interval = defaultMigrationInterval
+	}
+
+	rng := model.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewSource(rand.Int63()))
+	}
+
+	runs := make([]*pipelineRun, len(model.Islands))
+	names := make([]string, len(model.Islands))
+
+	for i, island := range model.Islands {
+		pipeline := island.Pipeline
+		if pipeline == nil {
+			pipeline = NewPipeline(island.Config)
+		}
+
+		run, err := newPipelineRun(island.Config, pipeline)
+		if err != nil {
+			return nil, fmt.Errorf("island %d: %w", i, err)
+		}
+
+		runs[i] = run
+
+		names[i] = island.Name
+		if names[i] == "" {
+			names[i] = fmt.Sprintf("island-%d", i)
+		}
+	}
+
+	maxIter := model.Islands[0].Config.MaxIterations
+	migrations := 0
+
+	for start := 0; start < maxIter; start += interval {
+		end := start + interval
+		if end > maxIter {
+			end = maxIter
+		}
+
+		var wg sync.WaitGroup
+
+		for _, run := range runs {
+			wg.Add(1)
+
+			go func(run *pipelineRun) {
+				defer wg.Done()
+
+				for it := start; it < end; it++ {
+					run.iterate(it)
+				}
+			}(run)
+		}
+
+		wg.Wait()
+
+		if end < maxIter && len(runs) > 1 {
+			model.migrate(runs, rng)
+			migrations++
+		}
+	}
+
+	return combineIslandResults(runs, names, migrations), nil
+}
+
+// migrantCount returns the number of males an island of the given size exchanges.
+func (m *IslandModel) migrantCount(size int) int {
+	rate := orDefault(m.MigrationRate, defaultMigrationRate)
+	n := int(math.Round(rate * float64(size)))
+
+	if n < 1 {
+		n = 1
+	}
+
+	// Always keep the island's own best male
+	if n > size-1 {
+		n = size - 1
+	}
+
+	return n
+}
+
+// sources returns the islands that send migrants to island i.
+func (m *IslandModel) sources(i, k int, rng *rand.Rand) []int {
+	switch m.Topology {
+	case TopologyFull:
+		sources := make([]int, 0, k-1)
+		for j := 0; j < k; j++ {
+			if j != i {
+				sources = append(sources, j)
+			}
+		}
+
+		return sources
+
+	case TopologyRandom:
+		j := rng.Intn(k - 1)
+		if j >= i {
+			j++
+		}
+
+		return []int{j}
+
+	default:
+		return []int{(i - 1 + k) % k}
+	}
+}
+
+// migrate exchanges the best males between islands. Emigrants are taken
+// from a snapshot before any island is modified, so the result does not
+// depend on the island order.
+func (m *IslandModel) migrate(runs []*pipelineRun, rng *rand.Rand) {
+	emigrants := make([][]*Mayfly, len(runs))
+
+	for i, run := range runs {
+		sorted := append([]*Mayfly(nil), run.state.Males...)
+		sortMayflies(sorted)
+
+		n := m.migrantCount(len(sorted))
+		emigrants[i] = make([]*Mayfly, n)
+
+		for k := 0; k < n; k++ {
+			emigrants[i][k] = sorted[k].clone()
+		}
+	}
+
+	for i, run := range runs {
+		var candidates []*Mayfly
+		for _, j := range m.sources(i, len(runs), rng) {
+			candidates = append(candidates, emigrants[j]...)
+		}
+
+		sortMayflies(candidates)
+
+		n := m.migrantCount(len(run.state.Males))
+		if n > len(candidates) {
+			n = len(candidates)
+		}
+
+		m.receive(run.state, candidates[:n], rng)
+	}
+}
+
+// receive places migrants into the male population of s according to the
+// replacement policy and updates the island's global best.
+func (m *IslandModel) receive(s *State, migrants []*Mayfly, rng *rand.Rand) {
+	if len(migrants) == 0 {
+		return
+	}
+
+	sortMayflies(s.Males)
+
+	var slots []int
+
+	if m.ReplacementPolicy == ReplaceRandom {
+		// Any male except the island's best
+		slots = rng.Perm(len(s.Males) - 1)[:len(migrants)]
+		for k := range slots {
+			slots[k]++
+		}
+	} else {
+		for k := range migrants {
+			slots = append(slots, len(s.Males)-1-k)
+		}
+	}
+
+	for k, migrant := range migrants {
+		s.Males[slots[k]] = migrant.clone()
+		s.UpdateGlobalBest(migrant.Position, migrant.Cost)
+	}
+}
+
+// combineIslandResults aggregates the per-island results.
+func combineIslandResults(runs []*pipelineRun, names []string, migrations int) *IslandResult {
+	result := &IslandResult{
+		Islands:     make([]*Result, len(runs)),
+		IslandNames: names,
+		Migrations:  migrations,
+	}
+
+	maxIter := runs[0].config.MaxIterations
+	result.BestSolution = make([]float64, maxIter)
+	result.IterationCount = maxIter
+	result.GlobalBest.Cost = math.Inf(1)
+
+	for it := range result.BestSolution {
+		result.BestSolution[it] = math.Inf(1)
+	}
+
+	for i, run := range runs {
+		r := run.result()
+		result.Islands[i] = r
+		result.FuncEvalCount += r.FuncEvalCount
+
+		if r.GlobalBest.Cost < result.GlobalBest.Cost {
+			result.GlobalBest = Best{
+				Position: append([]float64(nil), r.GlobalBest.Position...),
+				Cost:     r.GlobalBest.Cost,
+			}
+			result.BestIsland = i
+			result.Seed = r.Seed
+		}
+
+		for it, cost := range r.BestSolution {
+			result.BestSolution[it] = math.Min(result.BestSolution[it], cost)
+		}
+
+		result.Optima = append(result.Optima, r.Optima...)
+	}
+
+	// Islands may have found the same optima
+	if result.Optima != nil {
+		first := runs[0].config
+		result.Optima = mergeOptima(result.Optima, nicheRadius(first), 0)
+	}
+
+	return result
+}
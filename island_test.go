@@ -0,0 +1,240 @@
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+)
+
+// =============================================================================
+// Tests for island.go - Island model with migration
+// =============================================================================
+
+func newIslandTestBase(seed int64) *Config {
+	base := NewDefaultConfig()
+	base.ObjectiveFunc = Rastrigin
+	base.ProblemSize = 5
+	base.LowerBound = -5.12
+	base.UpperBound = 5.12
+	base.MaxIterations = 60
+	base.Rand = rand.New(rand.NewSource(seed))
+
+	return base
+}
+
+func TestOptimizeIslandsHeterogeneous(t *testing.T) {
+	base := newIslandTestBase(1)
+
+	var calls int64
+	base.ObjectiveFunc = func(x []float64) float64 {
+		atomic.AddInt64(&calls, 1)
+		return Rastrigin(x)
+	}
+
+	model, err := NewIslandModel(base, "ma", "desma", "olce", "eobbma")
+	if err != nil {
+		t.Fatalf("NewIslandModel failed: %v", err)
+	}
+
+	model.MigrationInterval = 15
+
+	result, err := OptimizeIslands(model)
+	if err != nil {
+		t.Fatalf("OptimizeIslands failed: %v", err)
+	}
+
+	if len(result.Islands) != 4 || result.IslandNames[1] != "DESMA-1" {
+		t.Errorf("Unexpected islands %v", result.IslandNames)
+	}
+
+	if result.Migrations != 3 {
+		t.Errorf("Expected 3 migrations for 60 iterations every 15, got %d", result.Migrations)
+	}
+
+	if int64(result.FuncEvalCount) != calls {
+		t.Errorf("FuncEvalCount = %d, objective called %d times", result.FuncEvalCount, calls)
+	}
+
+	for i, island := range result.Islands {
+		if island.GlobalBest.Cost < result.GlobalBest.Cost {
+			t.Errorf("Island %d best %v beats combined best %v", i, island.GlobalBest.Cost, result.GlobalBest.Cost)
+		}
+	}
+
+	if result.Islands[result.BestIsland].GlobalBest.Cost != result.GlobalBest.Cost {
+		t.Error("BestIsland does not hold the combined best")
+	}
+
+	for it := 1; it < len(result.BestSolution); it++ {
+		if result.BestSolution[it] > result.BestSolution[it-1] {
+			t.Fatalf("Combined best got worse at iteration %d", it)
+		}
+	}
+}
+
+func TestOptimizeIslandsReproducible(t *testing.T) {
+	run := func() float64 {
+		model, err := NewIslandModel(newIslandTestBase(7), "ma", "desma", "ma")
+		if err != nil {
+			t.Fatalf("NewIslandModel failed: %v", err)
+		}
+
+		model.Topology = TopologyRandom
+		model.ReplacementPolicy = ReplaceRandom
+
+		result, err := OptimizeIslands(model)
+		if err != nil {
+			t.Fatalf("OptimizeIslands failed: %v", err)
+		}
+
+		return result.GlobalBest.Cost
+	}
+
+	if a, b := run(), run(); a != b {
+		t.Errorf("Seeded island runs differ: %v vs %v", a, b)
+	}
+}
+
+func TestIslandTopologies(t *testing.T) {
+	for _, topology := range []string{TopologyRing, TopologyFull, TopologyRandom} {
+		t.Run(topology, func(t *testing.T) {
+			model, err := NewIslandModel(newIslandTestBase(2), "ma", "ma", "ma")
+			if err != nil {
+				t.Fatalf("NewIslandModel failed: %v", err)
+			}
+
+			model.Topology = topology
+			model.MigrationInterval = 5
+
+			result, err := OptimizeIslands(model)
+			if err != nil {
+				t.Fatalf("OptimizeIslands failed: %v", err)
+			}
+
+			if math.IsInf(result.GlobalBest.Cost, 0) || result.Migrations != 11 {
+				t.Errorf("Unexpected result: best %v, %d migrations", result.GlobalBest.Cost, result.Migrations)
+			}
+		})
+	}
+}
+
+func TestIslandSources(t *testing.T) {
+	model := &IslandModel{}
+	if src := model.sources(0, 4, nil); len(src) != 1 || src[0] != 3 {
+		t.Errorf("Ring source of island 0 should be 3, got %v", src)
+	}
+
+	model.Topology = TopologyFull
+	if src := model.sources(1, 4, nil); len(src) != 3 {
+		t.Errorf("Full topology should have 3 sources, got %v", src)
+	}
+
+	model.Topology = TopologyRandom
+	rng := rand.New(rand.NewSource(1))
+
+	for trial := 0; trial < 50; trial++ {
+		if src := model.sources(2, 4, rng); len(src) != 1 || src[0] == 2 {
+			t.Fatalf("Random source must be another island, got %v", src)
+		}
+	}
+}
+
+func TestIslandReceiveBestReplacesWorst(t *testing.T) {
+	s := newSelectionTestState(10, 1)
+	s.GlobalBest = Best{Position: make([]float64, 1), Cost: 0}
+
+	migrant := newMayfly(1)
+	migrant.Cost = -1
+	migrant.Position[0] = 42
+
+	model := &IslandModel{}
+	model.receive(s, []*Mayfly{migrant}, nil)
+
+	if s.Males[len(s.Males)-1].Cost != -1 {
+		t.Errorf("Migrant should replace the worst male, last cost %v", s.Males[len(s.Males)-1].Cost)
+	}
+
+	if s.GlobalBest.Cost != -1 || s.GlobalBest.Position[0] != 42 {
+		t.Errorf("Island global best not updated: %v", s.GlobalBest)
+	}
+
+	if model.migrantCount(20) != 2 || model.migrantCount(1) != 0 {
+		t.Errorf("Unexpected migrant counts %d/%d", model.migrantCount(20), model.migrantCount(1))
+	}
+}
+
+func TestIslandModelValidation(t *testing.T) {
+	newModel := func() *IslandModel {
+		model, err := NewIslandModel(newIslandTestBase(3), "ma", "desma")
+		if err != nil {
+			t.Fatalf("NewIslandModel failed: %v", err)
+		}
+
+		return model
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*IslandModel)
+	}{
+		{"Unknown topology", func(m *IslandModel) { m.Topology = "star" }},
+		{"Unknown policy", func(m *IslandModel) { m.ReplacementPolicy = "oldest" }},
+		{"Rate above one", func(m *IslandModel) { m.MigrationRate = 2 }},
+		{"Shared generator", func(m *IslandModel) { m.Islands[1].Config.Rand = m.Islands[0].Config.Rand }},
+		{"Different budgets", func(m *IslandModel) { m.Islands[1].Config.MaxIterations = 10 }},
+		{"Invalid island", func(m *IslandModel) { m.Islands[0].Config.ProblemSize = 0 }},
+		{"No islands", func(m *IslandModel) { m.Islands = nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newModel()
+			tt.modify(model)
+
+			if _, err := OptimizeIslands(model); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+
+	if _, err := NewIslandModel(newIslandTestBase(3), "ma", "unknown"); err == nil {
+		t.Error("Expected error for unknown variant")
+	}
+}
+
+func TestIslandsMergeOptima(t *testing.T) {
+	base := newIslandTestBase(4)
+	base.ObjectiveFunc = EqualMaxima
+	base.ProblemSize = 1
+	base.LowerBound = 0
+	base.UpperBound = 1
+
+	model, err := NewIslandModel(base, "ma", "ma")
+	if err != nil {
+		t.Fatalf("NewIslandModel failed: %v", err)
+	}
+
+	for _, island := range model.Islands {
+		island.Config.NichingMethod = NichingSpeciation
+	}
+
+	result, err := OptimizeIslands(model)
+	if err != nil {
+		t.Fatalf("OptimizeIslands failed: %v", err)
+	}
+
+	radius := nicheRadius(model.Islands[0].Config)
+
+	for i := range result.Optima {
+		for j := 0; j < i; j++ {
+			if math.Abs(result.Optima[i].Position[0]-result.Optima[j].Position[0]) < radius {
+				t.Fatalf("Islands' optima %d and %d were not merged", j, i)
+			}
+		}
+	}
+
+	if len(result.Optima) == 0 || result.Optima[0].Cost != result.GlobalBest.Cost {
+		t.Error("Merged optima should start with the global best")
+	}
+}
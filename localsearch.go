@@ -0,0 +1,521 @@
+// Package mayfly - Local Search Polishing
+//
+// Implements an optional hybrid phase that refines the global best with a
+// bounded local optimizer. Mayflies find the right basin quickly but
+// converge slowly to high precision; a local method started at the global
+// best closes the remaining gap with few evaluations.
+//
+// Methods (Config.LocalSearch):
+//   - "nelder-mead": downhill simplex (Nelder & Mead, 1965); points outside
+//     the bounds are projected onto them
+//   - "hooke-jeeves": pattern search with exploratory coordinate moves and
+//     step halving (Hooke & Jeeves, 1961)
+//   - "lbfgsb": limited-memory BFGS with box constraints in the spirit of
+//     L-BFGS-B (Byrd et al., 1995): variables held at a bound by the
+//     gradient are fixed, and a projected backtracking line search keeps the
+//     iterates feasible; gradients come from Config.Gradient or are
+//     estimated by central differences
+//
+// The local search runs after the last iteration and, with
+// LocalSearchInterval > 0, after every LocalSearchInterval iterations. Each
+// call may use up to LocalSearchEvals evaluations. They count towards
+// Result.FuncEvalCount, and Result.LocalSearchEvals reports their share. An
+// improvement becomes the new global best, which the males are attracted to.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Local search method names accepted by Config.LocalSearch.
+const (
+	LocalSearchNelderMead  = "nelder-mead"
+	LocalSearchHookeJeeves = "hooke-jeeves"
+	LocalSearchLBFGSB      = "lbfgsb"
+)
+
+// defaultLocalSearchEvals is the evaluation budget of a local search per
+// dimension when Config.LocalSearchEvals is zero.
+const defaultLocalSearchEvals = 100
+
+// localSearchTolerance is the step length, relative to the search range,
+// below which a local search has converged.
+const localSearchTolerance = 1e-12
+
+// localSearchImprovement is the relative decrease of the cost that counts
+// as an improvement in pattern search.
+const localSearchImprovement = 1e-12
+
+// finiteDifferenceStep is the step of the finite-difference gradient,
+// relative to the search range.
+const finiteDifferenceStep = 1e-7
+
+// lbfgsMemory is the number of correction pairs kept by L-BFGS-B.
+const lbfgsMemory = 5
+
+// validateLocalSearchConfig checks the local search method and its parameters.
+func validateLocalSearchConfig(config *Config) error {
+	switch config.LocalSearch {
+	case "", LocalSearchNelderMead, LocalSearchHookeJeeves, LocalSearchLBFGSB:
+	default:
+		return fmt.Errorf("unknown local search '%s' (expected nelder-mead, hooke-jeeves or lbfgsb)", config.LocalSearch)
+	}
+
+	if config.LocalSearchInterval < 0 {
+		return fmt.Errorf("LocalSearchInterval must be non-negative, got %d", config.LocalSearchInterval)
+	}
+
+	if config.LocalSearchEvals < 0 {
+		return fmt.Errorf("LocalSearchEvals must be non-negative, got %d", config.LocalSearchEvals)
+	}
+
+	if config.LocalSearch != "" && (config.LocalSearchStep <= 0 || config.LocalSearchStep > 1) {
+		return fmt.Errorf("LocalSearchStep must be in (0, 1], got %v", config.LocalSearchStep)
+	}
+
+	return nil
+}
+
+// localSearchController polishes the global best of a run.
+type localSearchController struct {
+	method   string
+	interval int
+	maxEvals int
+	step     float64 // Initial step in position units
+	evals    int     // Evaluations used so far
+}
+
+// newLocalSearchController creates the local search component for config.
+func newLocalSearchController(config *Config) *localSearchController {
+	maxEvals := config.LocalSearchEvals
+	if maxEvals == 0 {
+		maxEvals = defaultLocalSearchEvals * config.ProblemSize
+	}
+
+	return &localSearchController{
+		method:   config.LocalSearch,
+		interval: config.LocalSearchInterval,
+		maxEvals: maxEvals,
+		step:     config.LocalSearchStep * (config.UpperBound - config.LowerBound),
+	}
+}
+
+// due reports whether the local search runs after iteration it.
+func (c *localSearchController) due(it, maxIterations int) bool {
+	return it == maxIterations-1 || c.interval > 0 && (it+1)%c.interval == 0
+}
+
+// polish runs the local search from the global best and keeps the best
+// point it finds.
+func (c *localSearchController) polish(s *State) {
+	o := &localObjective{
+		f:        func(x []float64) float64 { return sanitizeCost(s.Evaluate(x)) },
+		lower:    s.Config.LowerBound,
+		upper:    s.Config.UpperBound,
+		maxEvals: c.maxEvals,
+		best:     Best{Position: append([]float64(nil), s.GlobalBest.Position...), Cost: s.GlobalBest.Cost},
+	}
+
+	start := append([]float64(nil), s.GlobalBest.Position...)
+
+	switch c.method {
+	case LocalSearchNelderMead:
+		nelderMead(o, start, s.GlobalBest.Cost, c.step)
+	case LocalSearchHookeJeeves:
+		hookeJeeves(o, start, s.GlobalBest.Cost, c.step)
+	case LocalSearchLBFGSB:
+		var gradient GradientFunction
+		if s.Config.Gradient != nil {
+			gradient = s.Gradient
+		}
+
+		lbfgsb(o, start, s.GlobalBest.Cost, c.step, gradient)
+	}
+
+	c.evals += o.evals
+	s.UpdateGlobalBest(o.best.Position, o.best.Cost)
+}
+
+// localObjective counts the evaluations of one local search, enforces its
+// budget and remembers the best point evaluated.
+type localObjective struct {
+	f        func([]float64) float64
+	lower    float64
+	upper    float64
+	evals    int
+	maxEvals int
+	best     Best
+}
+
+// exhausted reports whether the evaluation budget is used up.
+func (o *localObjective) exhausted() bool {
+	return o.evals >= o.maxEvals
+}
+
+// eval evaluates x, or returns +Inf without evaluating once the budget is
+// exhausted.
+func (o *localObjective) eval(x []float64) float64 {
+	if o.exhausted() {
+		return math.Inf(1)
+	}
+
+	o.evals++
+	cost := o.f(x)
+
+	if cost < o.best.Cost {
+		o.best.Cost = cost
+		copy(o.best.Position, x)
+	}
+
+	return cost
+}
+
+// along returns the projection of from + t (to - from) onto the bounds.
+func (o *localObjective) along(from, to []float64, t float64) []float64 {
+	x := make([]float64, len(from))
+	for j := range x {
+		x[j] = from[j] + t*(to[j]-from[j])
+	}
+
+	maxVec(x, o.lower)
+	minVec(x, o.upper)
+
+	return x
+}
+
+// =============================================================================
+// Nelder-Mead
+// =============================================================================
+
+// nelderMead runs the downhill simplex method from x0 with an initial
+// simplex of edge length step.
+func nelderMead(o *localObjective, x0 []float64, f0, step float64) {
+	n := len(x0)
+	simplex := make([][]float64, n+1)
+	costs := make([]float64, n+1)
+
+	simplex[0], costs[0] = x0, f0
+	for i := 1; i <= n; i++ {
+		x := append([]float64(nil), x0...)
+		if x[i-1]+step <= o.upper {
+			x[i-1] += step
+		} else {
+			x[i-1] -= step
+		}
+
+		simplex[i], costs[i] = x, o.eval(x)
+	}
+
+	tolerance := localSearchTolerance * (o.upper - o.lower)
+	centroid := make([]float64, n)
+
+	for !o.exhausted() {
+		sortSimplex(simplex, costs)
+
+		diameter := 0.0
+		for _, x := range simplex[1:] {
+			diameter = math.Max(diameter, euclideanDistance(x, simplex[0]))
+		}
+
+		if diameter < tolerance {
+			return
+		}
+
+		for j := range centroid {
+			centroid[j] = 0
+			for _, x := range simplex[:n] {
+				centroid[j] += x[j] / float64(n)
+			}
+		}
+
+		worst := simplex[n]
+		reflected := o.along(centroid, worst, -1)
+		fr := o.eval(reflected)
+
+		switch {
+		case fr < costs[0]:
+			expanded := o.along(centroid, worst, -2)
+			if fe := o.eval(expanded); fe < fr {
+				simplex[n], costs[n] = expanded, fe
+			} else {
+				simplex[n], costs[n] = reflected, fr
+			}
+		case fr < costs[n-1]:
+			simplex[n], costs[n] = reflected, fr
+		default:
+			t := 0.5
+			if fr < costs[n] {
+				t = -0.5 // Outside contraction
+			}
+
+			contracted := o.along(centroid, worst, t)
+			if fc := o.eval(contracted); fc < math.Min(fr, costs[n]) {
+				simplex[n], costs[n] = contracted, fc
+				continue
+			}
+
+			// Shrink towards the best point
+			for i := 1; i <= n; i++ {
+				simplex[i] = o.along(simplex[0], simplex[i], 0.5)
+				costs[i] = o.eval(simplex[i])
+			}
+		}
+	}
+}
+
+// sortSimplex orders the simplex vertices by cost.
+func sortSimplex(simplex [][]float64, costs []float64) {
+	order := make([]int, len(costs))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool { return costs[order[a]] < costs[order[b]] })
+
+	sortedSimplex := make([][]float64, len(simplex))
+	sortedCosts := make([]float64, len(costs))
+
+	for i, k := range order {
+		sortedSimplex[i], sortedCosts[i] = simplex[k], costs[k]
+	}
+
+	copy(simplex, sortedSimplex)
+	copy(costs, sortedCosts)
+}
+
+// =============================================================================
+// Hooke-Jeeves
+// =============================================================================
+
+// hookeJeeves runs pattern search from x0, starting with coordinate steps
+// of length step.
+func hookeJeeves(o *localObjective, x0 []float64, f0, step float64) {
+	tolerance := localSearchTolerance * (o.upper - o.lower)
+	base, fBase := x0, f0
+
+	for step >= tolerance && !o.exhausted() {
+		x, fx := o.explore(base, fBase, step)
+		if !improves(fx, fBase) {
+			step /= 2
+			continue
+		}
+
+		// Pattern moves as long as they pay off
+		for improves(fx, fBase) && !o.exhausted() {
+			pattern := o.along(base, x, 2)
+			base, fBase = x, fx
+			x, fx = o.explore(pattern, o.eval(pattern), step)
+		}
+	}
+}
+
+// improves reports whether f is better than ref by more than rounding
+// noise, which would otherwise keep pattern moves going on a plateau.
+func improves(f, ref float64) bool {
+	return f < ref-localSearchImprovement*math.Abs(ref)
+}
+
+// explore tries a step in both directions of every coordinate of x and
+// keeps each improvement.
+func (o *localObjective) explore(x []float64, fx, step float64) ([]float64, float64) {
+	x = append([]float64(nil), x...)
+
+	for j := range x {
+		old := x[j]
+		improved := false
+
+		for _, delta := range []float64{step, -step} {
+			x[j] = math.Max(o.lower, math.Min(o.upper, old+delta))
+			if x[j] == old {
+				continue
+			}
+
+			if f := o.eval(x); f < fx {
+				fx, improved = f, true
+				break
+			}
+		}
+
+		if !improved {
+			x[j] = old
+		}
+	}
+
+	return x, fx
+}
+
+// =============================================================================
+// L-BFGS-B
+// =============================================================================
+
+// lbfgsb runs limited-memory BFGS with box constraints from x0. The first
+// step moves at most step along any coordinate. Without a gradient function
+// the gradient is estimated by finite differences, and so is a gradient of
+// the wrong length.
+func lbfgsb(o *localObjective, x0 []float64, f0, step float64, gradient GradientFunction) {
+	tolerance := localSearchTolerance * (o.upper - o.lower)
+	x, fx := x0, f0
+
+	grad := func(x []float64, fx float64) []float64 {
+		if gradient != nil {
+			if g := gradient(x); len(g) == len(x) {
+				return g
+			}
+		}
+
+		return o.finiteDifference(x, fx)
+	}
+
+	g := grad(x, fx)
+
+	var ss, ys [][]float64 // Correction pairs, oldest first
+
+	for !o.exhausted() {
+		d := lbfgsDirection(g, ss, ys)
+
+		// Fix the variables that the gradient pushes out of the box
+		for j := range d {
+			if x[j] <= o.lower && d[j] < 0 || x[j] >= o.upper && d[j] > 0 {
+				d[j] = 0
+			}
+		}
+
+		if dot(d, g) >= 0 {
+			ss, ys = nil, nil
+
+			for j := range d {
+				d[j] = -g[j]
+				if x[j] <= o.lower && d[j] < 0 || x[j] >= o.upper && d[j] > 0 {
+					d[j] = 0
+				}
+			}
+		}
+
+		alpha := 1.0
+		if len(ss) == 0 {
+			largest := 0.0
+			for _, v := range d {
+				largest = math.Max(largest, math.Abs(v))
+			}
+
+			if largest > step {
+				alpha = step / largest
+			}
+		}
+
+		// Projected backtracking line search with the Armijo condition
+		var next []float64
+
+		fNext := math.Inf(1)
+		for k := 0; k < 50 && !o.exhausted(); k++ {
+			candidate := make([]float64, len(x))
+			for j := range x {
+				candidate[j] = x[j] + alpha*d[j]
+			}
+
+			maxVec(candidate, o.lower)
+			minVec(candidate, o.upper)
+
+			decrease := 0.0
+			for j := range x {
+				decrease += g[j] * (candidate[j] - x[j])
+			}
+
+			if f := o.eval(candidate); f <= fx+1e-4*decrease {
+				next, fNext = candidate, f
+				break
+			}
+
+			alpha /= 2
+		}
+
+		if next == nil || euclideanDistance(next, x) < tolerance {
+			return
+		}
+
+		gNext := grad(next, fNext)
+
+		s := make([]float64, len(x))
+		y := make([]float64, len(x))
+
+		for j := range x {
+			s[j] = next[j] - x[j]
+			y[j] = gNext[j] - g[j]
+		}
+
+		if dot(s, y) > 1e-10*dot(y, y) {
+			ss, ys = append(ss, s), append(ys, y)
+			if len(ss) > lbfgsMemory {
+				ss, ys = ss[1:], ys[1:]
+			}
+		}
+
+		x, fx, g = next, fNext, gNext
+	}
+}
+
+// lbfgsDirection returns the quasi-Newton direction -H g of the two-loop
+// recursion (Nocedal, 1980).
+func lbfgsDirection(g []float64, ss, ys [][]float64) []float64 {
+	q := make([]float64, len(g))
+	for j, v := range g {
+		q[j] = -v
+	}
+
+	alphas := make([]float64, len(ss))
+	for i := len(ss) - 1; i >= 0; i-- {
+		alphas[i] = dot(ss[i], q) / dot(ys[i], ss[i])
+		for j := range q {
+			q[j] -= alphas[i] * ys[i][j]
+		}
+	}
+
+	if k := len(ss) - 1; k >= 0 {
+		gamma := dot(ss[k], ys[k]) / dot(ys[k], ys[k])
+		for j := range q {
+			q[j] *= gamma
+		}
+	}
+
+	for i := range ss {
+		beta := dot(ys[i], q) / dot(ys[i], ss[i])
+		for j := range q {
+			q[j] += ss[i][j] * (alphas[i] - beta)
+		}
+	}
+
+	return q
+}
+
+// finiteDifference estimates the gradient at x with cost fx by central
+// differences, one-sided at the bounds.
+func (o *localObjective) finiteDifference(x []float64, fx float64) []float64 {
+	h := finiteDifferenceStep * (o.upper - o.lower)
+	g := make([]float64, len(x))
+	p := append([]float64(nil), x...)
+
+	for j, v := range x {
+		lo, hi := math.Max(v-h, o.lower), math.Min(v+h, o.upper)
+		fLo, fHi := fx, fx
+
+		if hi > v {
+			p[j] = hi
+			fHi = o.eval(p)
+		}
+
+		if lo < v {
+			p[j] = lo
+			fLo = o.eval(p)
+		}
+
+		p[j] = v
+
+		if hi > lo {
+			g[j] = (fHi - fLo) / (hi - lo)
+		}
+	}
+
+	return g
+}
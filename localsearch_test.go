@@ -0,0 +1,168 @@
+package mayfly
+
+import (
+	"math"
+	"testing"
+)
+
+// =============================================================================
+// Tests for localsearch.go - Local search polishing
+// =============================================================================
+
+func newLocalSearchTestConfig(fn ObjectiveFunction, method string) *Config {
+	config := newTestConfig(fn, 5, 10, 30, 1)
+	config.LocalSearch = method
+
+	return config
+}
+
+// runLocalSearch runs method on f from x0 within [lower, upper].
+func runLocalSearch(method string, f ObjectiveFunction, x0 []float64, lower, upper float64, maxEvals int) *localObjective {
+	o := &localObjective{
+		f:        f,
+		lower:    lower,
+		upper:    upper,
+		maxEvals: maxEvals,
+		best:     Best{Position: append([]float64(nil), x0...), Cost: f(x0)},
+	}
+
+	start := append([]float64(nil), x0...)
+	step := 0.01 * (upper - lower)
+
+	switch method {
+	case LocalSearchNelderMead:
+		nelderMead(o, start, o.best.Cost, step)
+	case LocalSearchHookeJeeves:
+		hookeJeeves(o, start, o.best.Cost, step)
+	case LocalSearchLBFGSB:
+		lbfgsb(o, start, o.best.Cost, step, nil)
+	}
+
+	return o
+}
+
+var localSearchMethods = []string{LocalSearchNelderMead, LocalSearchHookeJeeves, LocalSearchLBFGSB}
+
+func TestValidateLocalSearchConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"unknown method", func(c *Config) { c.LocalSearch = "powell" }},
+		{"negative interval", func(c *Config) { c.LocalSearchInterval = -1 }},
+		{"negative evals", func(c *Config) { c.LocalSearchEvals = -1 }},
+		{"step above 1", func(c *Config) { c.LocalSearchStep = 2 }},
+		{"zero step", func(c *Config) { c.LocalSearchStep = 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newLocalSearchTestConfig(Sphere, LocalSearchNelderMead)
+			tt.modify(config)
+
+			if _, err := Optimize(config); err == nil {
+				t.Error("Expected a validation error")
+			}
+		})
+	}
+}
+
+func TestLocalSearchMethods(t *testing.T) {
+	for _, method := range localSearchMethods {
+		t.Run(method, func(t *testing.T) {
+			o := runLocalSearch(method, Rosenbrock, []float64{-1.2, 1}, -5, 5, 5000)
+			if o.best.Cost > 1e-6 {
+				t.Errorf("Rosenbrock: expected cost below 1e-6, got %g after %d evaluations", o.best.Cost, o.evals)
+			}
+
+			if o.evals > 5000 {
+				t.Errorf("Budget exceeded: %d evaluations", o.evals)
+			}
+
+			// The optimum of a shifted sphere lies on the upper bound
+			shifted := func(x []float64) float64 {
+				sum := 0.0
+				for _, v := range x {
+					sum += (v - 15) * (v - 15)
+				}
+
+				return sum
+			}
+
+			o = runLocalSearch(method, shifted, []float64{1, -2, 3}, -10, 10, 2000)
+			for _, v := range o.best.Position {
+				if v < -10 || v > 10 {
+					t.Fatalf("Position %v outside the bounds", o.best.Position)
+				}
+			}
+
+			if math.Abs(o.best.Cost-75) > 1e-6 {
+				t.Errorf("Shifted sphere: expected cost 75 at the bound, got %g", o.best.Cost)
+			}
+		})
+	}
+}
+
+func TestLocalSearchBudget(t *testing.T) {
+	for _, method := range localSearchMethods {
+		o := runLocalSearch(method, Ackley, []float64{1.3, -0.7, 2.1, 0.4}, -32, 32, 37)
+		if o.evals != 37 {
+			t.Errorf("%s: expected the budget of 37 evaluations to be used, got %d", method, o.evals)
+		}
+	}
+}
+
+func TestLocalSearchPolishing(t *testing.T) {
+	baseline, err := Optimize(newLocalSearchTestConfig(Sphere, ""))
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if baseline.LocalSearchEvals != 0 {
+		t.Errorf("Expected no local search evaluations, got %d", baseline.LocalSearchEvals)
+	}
+
+	for _, method := range localSearchMethods {
+		t.Run(method, func(t *testing.T) {
+			result, err := Optimize(newLocalSearchTestConfig(Sphere, method))
+			if err != nil {
+				t.Fatalf("Optimize failed: %v", err)
+			}
+
+			if result.GlobalBest.Cost > 1e-6*baseline.GlobalBest.Cost {
+				t.Errorf("Expected polishing to improve %g by six orders of magnitude, got %g",
+					baseline.GlobalBest.Cost, result.GlobalBest.Cost)
+			}
+
+			if result.LocalSearchEvals == 0 || result.LocalSearchEvals > defaultLocalSearchEvals*5 {
+				t.Errorf("Unexpected local search evaluations: %d", result.LocalSearchEvals)
+			}
+
+			if result.FuncEvalCount != baseline.FuncEvalCount+result.LocalSearchEvals {
+				t.Errorf("Local search evaluations not counted: %d != %d + %d",
+					result.FuncEvalCount, baseline.FuncEvalCount, result.LocalSearchEvals)
+			}
+
+			last := result.BestSolution[len(result.BestSolution)-1]
+			if last != result.GlobalBest.Cost {
+				t.Errorf("History ends at %g, expected the polished cost %g", last, result.GlobalBest.Cost)
+			}
+		})
+	}
+}
+
+func TestLocalSearchInterval(t *testing.T) {
+	config := newLocalSearchTestConfig(Rastrigin, LocalSearchNelderMead)
+	config.LocalSearchInterval = 10
+	config.LocalSearchEvals = 20
+
+	result, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	// Iterations 10, 20 and 30
+	if result.LocalSearchEvals != 60 {
+		t.Errorf("Expected 3 local searches of 20 evaluations, got %d evaluations", result.LocalSearchEvals)
+	}
+}
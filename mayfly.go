@@ -18,843 +18,309 @@ package mayfly
 import (
 	"fmt"
 	"math"
-	"math/rand"
-	"time"
 )
 
 // Optimize runs the Mayfly Optimization Algorithm with the given configuration.
+// The variant flags of config select the stages of the underlying Pipeline.
 func Optimize(config *Config) (*Result, error) {
+	if err := validateOptimizeConfig(config); err != nil {
+		return nil, err
+	}
+
+	return runPipeline(config, NewPipeline(config))
+}
+
+// validateOptimizeConfig checks the parameters required to run the main loop.
+func validateOptimizeConfig(config *Config) error {
 	// Validate required parameters
 	if config == nil {
-		return nil, fmt.Errorf("config cannot be nil")
+		return fmt.Errorf("config cannot be nil")
 	}
 
 	if config.ObjectiveFunc == nil {
-		return nil, fmt.Errorf("ObjectiveFunc is required")
+		return fmt.Errorf("ObjectiveFunc is required")
 	}
 
+	if config.ConstraintFunc != nil {
+		return fmt.Errorf("ConstraintFunc is only supported by OptimizeMulti")
+	}
+
+	return validateSearchConfig(config)
+}
+
+// validateSearchConfig checks all parameters of the main loop except the
+// objective function.
+func validateSearchConfig(config *Config) error {
 	if config.ProblemSize <= 0 {
-		return nil, fmt.Errorf("ProblemSize must be positive, got %d", config.ProblemSize)
+		return fmt.Errorf("ProblemSize must be positive, got %d", config.ProblemSize)
 	}
 
 	// Validate bounds are finite and properly ordered
 	if math.IsNaN(config.LowerBound) || math.IsInf(config.LowerBound, 0) {
-		return nil, fmt.Errorf("LowerBound must be finite, got %v", config.LowerBound)
+		return fmt.Errorf("LowerBound must be finite, got %v", config.LowerBound)
 	}
 
 	if math.IsNaN(config.UpperBound) || math.IsInf(config.UpperBound, 0) {
-		return nil, fmt.Errorf("UpperBound must be finite, got %v", config.UpperBound)
+		return fmt.Errorf("UpperBound must be finite, got %v", config.UpperBound)
 	}
 
 	if config.LowerBound >= config.UpperBound {
-		return nil, fmt.Errorf("LowerBound (%v) must be less than UpperBound (%v)",
+		return fmt.Errorf("LowerBound (%v) must be less than UpperBound (%v)",
 			config.LowerBound, config.UpperBound)
 	}
 
 	if config.MaxIterations <= 0 {
-		return nil, fmt.Errorf("MaxIterations must be positive, got %d", config.MaxIterations)
+		return fmt.Errorf("MaxIterations must be positive, got %d", config.MaxIterations)
 	}
 
 	// Validate population sizes
 	if config.NPop <= 0 {
-		return nil, fmt.Errorf("NPop (male population) must be positive, got %d", config.NPop)
+		return fmt.Errorf("NPop (male population) must be positive, got %d", config.NPop)
 	}
 
 	if config.NPopF <= 0 {
-		return nil, fmt.Errorf("NPopF (female population) must be positive, got %d", config.NPopF)
+		return fmt.Errorf("NPopF (female population) must be positive, got %d", config.NPopF)
+	}
+
+	if err := validateCrossoverConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateMutationConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateSelectionConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateRestartConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateNichingConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateMultiObjectiveConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateSurrogateConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateLocalSearchConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateCMAESConfig(config); err != nil {
+		return err
+	}
+
+	if err := validateAdaptationConfig(config); err != nil {
+		return err
 	}
 
 	// Validate variant-specific parameters
 	if config.UseDESMA {
 		if config.SearchRange < 0 {
-			return nil, fmt.Errorf("DESMA SearchRange must be non-negative, got %v", config.SearchRange)
+			return fmt.Errorf("DESMA SearchRange must be non-negative, got %v", config.SearchRange)
 		}
 		if config.EliteCount < 0 {
-			return nil, fmt.Errorf("DESMA EliteCount must be non-negative, got %d", config.EliteCount)
+			return fmt.Errorf("DESMA EliteCount must be non-negative, got %d", config.EliteCount)
 		}
 		if config.EnlargeFactor <= 0 {
-			return nil, fmt.Errorf("DESMA EnlargeFactor must be positive, got %v", config.EnlargeFactor)
+			return fmt.Errorf("DESMA EnlargeFactor must be positive, got %v", config.EnlargeFactor)
 		}
 		if config.ReductionFactor <= 0 {
-			return nil, fmt.Errorf("DESMA ReductionFactor must be positive, got %v", config.ReductionFactor)
+			return fmt.Errorf("DESMA ReductionFactor must be positive, got %v", config.ReductionFactor)
 		}
 	}
 
 	if config.UseEOBBMA {
 		if config.LevyAlpha <= 0 || config.LevyAlpha > 2 {
-			return nil, fmt.Errorf("EOBBMA LevyAlpha must be in (0, 2], got %v", config.LevyAlpha)
+			return fmt.Errorf("EOBBMA LevyAlpha must be in (0, 2], got %v", config.LevyAlpha)
 		}
 		if config.LevyBeta <= 0 {
-			return nil, fmt.Errorf("EOBBMA LevyBeta must be positive, got %v", config.LevyBeta)
+			return fmt.Errorf("EOBBMA LevyBeta must be positive, got %v", config.LevyBeta)
 		}
 		if config.OppositionRate < 0 || config.OppositionRate > 1 {
-			return nil, fmt.Errorf("EOBBMA OppositionRate must be in [0, 1], got %v", config.OppositionRate)
+			return fmt.Errorf("EOBBMA OppositionRate must be in [0, 1], got %v", config.OppositionRate)
 		}
 	}
 
 	if config.UseOLCE {
 		if config.OrthogonalFactor < 0 || config.OrthogonalFactor > 1 {
-			return nil, fmt.Errorf("OLCE OrthogonalFactor must be in [0, 1], got %v", config.OrthogonalFactor)
+			return fmt.Errorf("OLCE OrthogonalFactor must be in [0, 1], got %v", config.OrthogonalFactor)
 		}
 		if config.ChaosFactor < 0 {
-			return nil, fmt.Errorf("OLCE ChaosFactor must be non-negative, got %v", config.ChaosFactor)
+			return fmt.Errorf("OLCE ChaosFactor must be non-negative, got %v", config.ChaosFactor)
 		}
 	}
 
 	if config.UseGSASMA {
 		if config.InitialTemperature <= 0 {
-			return nil, fmt.Errorf("GSASMA InitialTemperature must be positive, got %v", config.InitialTemperature)
+			return fmt.Errorf("GSASMA InitialTemperature must be positive, got %v", config.InitialTemperature)
 		}
 		if config.CoolingRate <= 0 || config.CoolingRate >= 1 {
-			return nil, fmt.Errorf("GSASMA CoolingRate must be in (0, 1), got %v", config.CoolingRate)
+			return fmt.Errorf("GSASMA CoolingRate must be in (0, 1), got %v", config.CoolingRate)
 		}
 		if config.CauchyMutationRate < 0 || config.CauchyMutationRate > 1 {
-			return nil, fmt.Errorf("GSASMA CauchyMutationRate must be in [0, 1], got %v", config.CauchyMutationRate)
+			return fmt.Errorf("GSASMA CauchyMutationRate must be in [0, 1], got %v", config.CauchyMutationRate)
 		}
 	}
 
 	if config.UseMPMA {
 		if config.MedianWeight < 0 || config.MedianWeight > 1 {
-			return nil, fmt.Errorf("MPMA MedianWeight must be in [0, 1], got %v", config.MedianWeight)
+			return fmt.Errorf("MPMA MedianWeight must be in [0, 1], got %v", config.MedianWeight)
 		}
 	}
 
 	if config.UseAOBLMOA {
 		if config.AquilaWeight < 0 || config.AquilaWeight > 1 {
-			return nil, fmt.Errorf("AOBLMOA AquilaWeight must be in [0, 1], got %v", config.AquilaWeight)
+			return fmt.Errorf("AOBLMOA AquilaWeight must be in [0, 1], got %v", config.AquilaWeight)
 		}
 		if config.OppositionProbability < 0 || config.OppositionProbability > 1 {
-			return nil, fmt.Errorf("AOBLMOA OppositionProbability must be in [0, 1], got %v", config.OppositionProbability)
+			return fmt.Errorf("AOBLMOA OppositionProbability must be in [0, 1], got %v", config.OppositionProbability)
 		}
 	}
 
-	// Initialize parameters
-	if config.NM == 0 {
-		config.NM = int(math.Round(0.05 * float64(config.NPop)))
-	}
-
-	if config.VelMax == 0 {
-		config.VelMax = 0.1 * (config.UpperBound - config.LowerBound)
-		config.VelMin = -config.VelMax
-	}
+	return validateVariantCombination(config)
+}
 
-	// Initialize random number generator if not provided
-	rng := config.Rand
-	seed := int64(0)
-	if rng == nil {
-		seed = time.Now().UnixNano()
-		rng = rand.New(rand.NewSource(seed))
-	} else {
-		// Try to extract seed from the random source if possible
-		// This is a best-effort attempt for reproducibility tracking
-		seed = time.Now().UnixNano() // Fallback if we can't determine
+// mayflyOnlyOption returns the name of the first enabled option that only
+// the single-objective Mayfly main loop implements, or "" if there is none.
+func mayflyOnlyOption(config *Config) string {
+	switch {
+	case config.UseDESMA:
+		return "UseDESMA"
+	case config.UseOLCE:
+		return "UseOLCE"
+	case config.UseGSASMA:
+		return "UseGSASMA"
+	case config.UseEOBBMA:
+		return "UseEOBBMA"
+	case config.UseMPMA:
+		return "UseMPMA"
+	case config.UseAOBLMOA:
+		return "UseAOBLMOA"
+	case config.UseSurrogate:
+		return "UseSurrogate"
+	case config.LocalSearch != "":
+		return "LocalSearch"
+	case config.Gradient != nil:
+		return "Gradient"
+	case config.AdaptiveParameters:
+		return "AdaptiveParameters"
 	}
 
-	// Initialize populations
-	males := make([]*Mayfly, config.NPop)
-	females := make([]*Mayfly, config.NPopF)
+	return ""
+}
 
-	globalBest := Best{
-		Position: make([]float64, config.ProblemSize),
-		Cost:     math.Inf(1),
-	}
+// standardMover implements the velocity-based movement of the original
+// Mayfly Algorithm.
+type standardMover struct{}
 
-	funcCount := 0
+// MoveFemales attracts each female towards her paired male or lets her fly randomly.
+func (m *standardMover) MoveFemales(s *State) {
+	config := s.Config
 
-	// Initialize male population
-	for i := 0; i < config.NPop; i++ {
-		males[i] = newMayfly(config.ProblemSize)
-		males[i].Position = unifrndVec(config.LowerBound, config.UpperBound, config.ProblemSize, rng)
-		males[i].Cost = evaluateWithSanitization(config.ObjectiveFunc, males[i].Position,
-			config.LowerBound, config.UpperBound, rng)
-		funcCount++
-
-		// Update personal best
-		copy(males[i].Best.Position, males[i].Position)
-		males[i].Best.Cost = males[i].Cost
-
-		// Update global best
-		if males[i].Best.Cost < globalBest.Cost {
-			globalBest.Cost = males[i].Best.Cost
-			globalBest.Position = make([]float64, config.ProblemSize)
-			copy(globalBest.Position, males[i].Best.Position)
-		}
-	}
-
-	// Initialize female population
 	for i := 0; i < config.NPopF; i++ {
-		females[i] = newMayfly(config.ProblemSize)
-		females[i].Position = unifrndVec(config.LowerBound, config.UpperBound, config.ProblemSize, rng)
-		females[i].Cost = evaluateWithSanitization(config.ObjectiveFunc, females[i].Position,
-			config.LowerBound, config.UpperBound, rng)
-		funcCount++
-	}
-
-	bestSolution := make([]float64, config.MaxIterations)
-	g := config.G
-	dance := config.Dance
-	fl := config.FL
-
-	// Initialize DESMA parameters if enabled
-	var searchRange float64
+		female := s.Females[i]
+		e := unifrndVec(-1, 1, config.ProblemSize, s.Rand)
 
-	var lastGlobalBestCost float64
-
-	if config.UseDESMA {
-		if config.SearchRange == 0 {
-			// Auto-calculate initial search range as 10% of the search space
-			searchRange = 0.1 * (config.UpperBound - config.LowerBound)
-		} else {
-			searchRange = config.SearchRange
-		}
-
-		lastGlobalBestCost = globalBest.Cost
-	}
-
-	// Initialize OLCE-MA parameters if enabled
-	var chaosMap *LogisticMap
-
-	if config.UseOLCE {
-		// Initialize chaotic map with random seed
-		seed := rng.Float64()
-		chaosMap = NewLogisticMap(seed)
-	}
-
-	// Initialize GSASMA parameters if enabled
-	var annealingScheduler *AnnealingScheduler
-	if config.UseGSASMA {
-		annealingScheduler = NewAnnealingScheduler(
-			config.InitialTemperature,
-			config.CoolingRate,
-			config.CoolingSchedule,
-		)
-	}
-
-	// Initialize AOBLMOA parameters if enabled
-	var paretoArchive *ParetoArchive
-
-	if config.UseAOBLMOA {
-		initializeAOBLMOA(config)
-		paretoArchive = NewParetoArchive(config.ArchiveSize)
-	}
-
-	// Main loop
-	for it := 0; it < config.MaxIterations; it++ {
-		// AOBLMOA: Use hybrid Mayfly-Aquila updates with opposition-based learning
-		if config.UseAOBLMOA {
-			// Apply AOBLMOA to populations
-			applyAOBLMOAToPopulation(males, females, globalBest, it, config.MaxIterations, config)
-
-			// Count function evaluations (approximation)
-			// Aquila strategies: 1 eval per mayfly
-			// Opposition learning: OppositionProbability * population size * 2 (original + opposition)
-			aoblmoaEvals := config.NPop + config.NPopF
-			oppositionEvals := int(config.OppositionProbability * float64(config.NPop+config.NPopF) * 2)
-			funcCount += aoblmoaEvals + oppositionEvals
-
-			// Update global best from updated populations
-			for i := 0; i < config.NPop; i++ {
-				if males[i].Cost < globalBest.Cost {
-					globalBest.Cost = males[i].Cost
-					copy(globalBest.Position, males[i].Position)
-				}
-			}
-		} else if config.UseEOBBMA {
-			// Update females with Gaussian sampling around best males
-			for i := 0; i < config.NPopF; i++ {
-				// Decide whether to use Lévy flight or Gaussian update
-				if rng.Float64() < 0.5 {
-					// Use Gaussian update toward best male
-					newPos := gaussianUpdate(females[i].Position, males[i].Position,
-						config.LowerBound, config.UpperBound, rng)
-					copy(females[i].Position, newPos)
-				} else {
-					// Use Lévy flight for exploration
-					levyStep := levyFlightVec(config.ProblemSize, config.LevyAlpha, config.LevyBeta, rng)
-					for j := 0; j < config.ProblemSize; j++ {
-						females[i].Position[j] += levyStep[j] * (config.UpperBound - config.LowerBound) * 0.01
-					}
-
-					maxVec(females[i].Position, config.LowerBound)
-					minVec(females[i].Position, config.UpperBound)
-				}
-
-				females[i].Cost = config.ObjectiveFunc(females[i].Position)
-				funcCount++
-			}
-
-			// Update males with Gaussian sampling around personal and global best
-			for i := 0; i < config.NPop; i++ {
-				// Decide whether to use Gaussian toward personal best or global best
-				if rng.Float64() < 0.5 {
-					// Gaussian toward personal best
-					newPos := gaussianUpdate(males[i].Position, males[i].Best.Position,
-						config.LowerBound, config.UpperBound, rng)
-					copy(males[i].Position, newPos)
-				} else {
-					// Gaussian toward global best
-					newPos := gaussianUpdate(males[i].Position, globalBest.Position,
-						config.LowerBound, config.UpperBound, rng)
-					copy(males[i].Position, newPos)
-				}
-
-				males[i].Cost = config.ObjectiveFunc(males[i].Position)
-				funcCount++
-
-				// Update personal best
-				if males[i].Cost < males[i].Best.Cost {
-					copy(males[i].Best.Position, males[i].Position)
-					males[i].Best.Cost = males[i].Cost
-
-					// Update global best
-					if males[i].Best.Cost < globalBest.Cost {
-						globalBest.Cost = males[i].Best.Cost
-						copy(globalBest.Position, males[i].Best.Position)
-					}
-				}
+		if female.Cost > s.Males[i].Cost {
+			// Attracted to male
+			c := s.coefficients(female, ruleAttraction)
+			for j := 0; j < config.ProblemSize; j++ {
+				rmf := s.Males[i].Position[j] - female.Position[j]
+				female.Velocity[j] = s.G*female.Velocity[j] +
+					c.A3*math.Exp(-c.Beta*rmf*rmf)*(s.Males[i].Position[j]-female.Position[j])
 			}
 		} else {
-			// Standard velocity-based updates
-			// Update females
-			for i := 0; i < config.NPopF; i++ {
-				e := unifrndVec(-1, 1, config.ProblemSize, rng)
-
-				if females[i].Cost > males[i].Cost {
-					// Attracted to male
-					for j := 0; j < config.ProblemSize; j++ {
-						rmf := males[i].Position[j] - females[i].Position[j]
-						females[i].Velocity[j] = g*females[i].Velocity[j] +
-							config.A3*math.Exp(-config.Beta*rmf*rmf)*(males[i].Position[j]-females[i].Position[j])
-					}
-				} else {
-					// Random flight
-					for j := 0; j < config.ProblemSize; j++ {
-						females[i].Velocity[j] = g*females[i].Velocity[j] + fl*e[j]
-					}
-				}
-
-				// Apply velocity limits
-				maxVec(females[i].Velocity, config.VelMin)
-				minVec(females[i].Velocity, config.VelMax)
-
-				// Update position
-				for j := 0; j < config.ProblemSize; j++ {
-					females[i].Position[j] += females[i].Velocity[j]
-				}
-
-				// Apply position limits
-				maxVec(females[i].Position, config.LowerBound)
-				minVec(females[i].Position, config.UpperBound)
-
-				// Evaluate
-				females[i].Cost = config.ObjectiveFunc(females[i].Position)
-				funcCount++
-			}
-
-			// MPMA: Calculate median position if enabled
-			var medianPos []float64
-
-			var mpmaG float64 // MPMA-specific gravity coefficient
-
-			if config.UseMPMA {
-				if config.UseWeightedMedian {
-					// Create fitness weights (better fitness = higher weight)
-					weights := make([]float64, len(males))
-					maxCost := males[len(males)-1].Cost // Worst cost (sorted)
-					minCost := males[0].Cost            // Best cost
-
-					for i := range males {
-						// Normalize and invert (better solutions get higher weight)
-						if maxCost > minCost {
-							weights[i] = 1.0 - (males[i].Cost-minCost)/(maxCost-minCost)
-						} else {
-							weights[i] = 1.0 // All equal
-						}
-					}
-
-					medianPos = calculateWeightedMedianPosition(males, weights)
-				} else {
-					medianPos = calculateMedianPosition(males)
-				}
-				// Calculate non-linear gravity coefficient
-				mpmaG = calculateGravityCoefficient(config.GravityType, it, config.MaxIterations)
-			}
-
-			// Update males
-			for i := 0; i < config.NPop; i++ {
-				e := unifrndVec(-1, 1, config.ProblemSize, rng)
-
-				if males[i].Cost > globalBest.Cost {
-					// Update velocity with personal and global best
-					if config.UseMPMA {
-						// MPMA: Include median position in velocity update
-						for j := 0; j < config.ProblemSize; j++ {
-							rpbest := males[i].Best.Position[j] - males[i].Position[j]
-							rgbest := globalBest.Position[j] - males[i].Position[j]
-							rmedian := medianPos[j] - males[i].Position[j]
-
-							// Modified velocity update with median position and non-linear gravity
-							males[i].Velocity[j] = mpmaG*males[i].Velocity[j] +
-								config.A1*math.Exp(-config.Beta*rpbest*rpbest)*(males[i].Best.Position[j]-males[i].Position[j]) +
-								config.A2*math.Exp(-config.Beta*rgbest*rgbest)*(globalBest.Position[j]-males[i].Position[j]) +
-								config.MedianWeight*math.Exp(-config.Beta*rmedian*rmedian)*(medianPos[j]-males[i].Position[j])
-						}
-					} else {
-						// Standard velocity update
-						for j := 0; j < config.ProblemSize; j++ {
-							rpbest := males[i].Best.Position[j] - males[i].Position[j]
-							rgbest := globalBest.Position[j] - males[i].Position[j]
-							males[i].Velocity[j] = g*males[i].Velocity[j] +
-								config.A1*math.Exp(-config.Beta*rpbest*rpbest)*(males[i].Best.Position[j]-males[i].Position[j]) +
-								config.A2*math.Exp(-config.Beta*rgbest*rgbest)*(globalBest.Position[j]-males[i].Position[j])
-						}
-					}
-				} else {
-					// Nuptial dance
-					gVal := g
-					if config.UseMPMA {
-						gVal = mpmaG // Use MPMA gravity for dance too
-					}
-
-					for j := 0; j < config.ProblemSize; j++ {
-						males[i].Velocity[j] = gVal*males[i].Velocity[j] + dance*e[j]
-					}
-				}
-
-				// Apply velocity limits
-				maxVec(males[i].Velocity, config.VelMin)
-				minVec(males[i].Velocity, config.VelMax)
-
-				// Update position
-				for j := 0; j < config.ProblemSize; j++ {
-					males[i].Position[j] += males[i].Velocity[j]
-				}
-
-				// Apply position limits
-				maxVec(males[i].Position, config.LowerBound)
-				minVec(males[i].Position, config.UpperBound)
-
-				// Evaluate
-				males[i].Cost = config.ObjectiveFunc(males[i].Position)
-				funcCount++
-
-				// Update personal best
-				if males[i].Cost < males[i].Best.Cost {
-					copy(males[i].Best.Position, males[i].Position)
-					males[i].Best.Cost = males[i].Cost
-
-					// Update global best
-					if males[i].Best.Cost < globalBest.Cost {
-						globalBest.Cost = males[i].Best.Cost
-						copy(globalBest.Position, males[i].Best.Position)
-					}
-				}
-			}
-		}
-
-		// Sort populations by cost
-		sortMayflies(males)
-		sortMayflies(females)
-
-		// OLCE-MA: Apply orthogonal learning to elite males
-		if config.UseOLCE {
-			// Prepare bounds vectors for orthogonal learning
-			lb := make([]float64, config.ProblemSize)
-			ub := make([]float64, config.ProblemSize)
-
+			// Random flight
+			c := s.coefficients(female, ruleFlight)
 			for j := 0; j < config.ProblemSize; j++ {
-				lb[j] = config.LowerBound
-				ub[j] = config.UpperBound
+				female.Velocity[j] = s.G*female.Velocity[j] + c.FL*e[j]
 			}
-
-			// Apply to top 20% of males
-			ApplyOrthogonalLearningToElite(
-				males,
-				0.2, // Top 20%
-				globalBest.Position,
-				config.OrthogonalFactor,
-				lb, ub,
-				config.ObjectiveFunc,
-				rng,
-			)
-
-			// Count function evaluations from orthogonal learning
-			// Each elite male generates 4 candidates (L4 array)
-			numElite := int(float64(len(males)) * 0.2)
-			if numElite < 1 {
-				numElite = 1
-			}
-
-			funcCount += numElite * 4
-
-			// Update global best if orthogonal learning found better solution
-			for i := 0; i < numElite; i++ {
-				if males[i].Cost < globalBest.Cost {
-					globalBest.Cost = males[i].Cost
-					copy(globalBest.Position, males[i].Position)
-				}
-			}
-
-			// Re-sort after orthogonal learning
-			sortMayflies(males)
-		}
-
-		// EOBBMA: Apply elite opposition-based learning
-		if config.UseEOBBMA {
-			// Apply opposition to top elite solutions with probability OppositionRate
-			numEliteOpposition := config.EliteOppositionCount
-			if numEliteOpposition > len(males) {
-				numEliteOpposition = len(males)
-			}
-
-			for i := 0; i < numEliteOpposition; i++ {
-				if rng.Float64() < config.OppositionRate {
-					// Generate opposition point
-					oppPos := oppositionPoint(males[i].Position, config.LowerBound, config.UpperBound)
-
-					// Evaluate opposition point
-					oppCost := config.ObjectiveFunc(oppPos)
-					funcCount++
-
-					// If opposition is better, replace the elite
-					if oppCost < males[i].Cost {
-						copy(males[i].Position, oppPos)
-						males[i].Cost = oppCost
-
-						// Update personal best
-						if oppCost < males[i].Best.Cost {
-							copy(males[i].Best.Position, oppPos)
-							males[i].Best.Cost = oppCost
-						}
-
-						// Update global best
-						if oppCost < globalBest.Cost {
-							globalBest.Cost = oppCost
-							copy(globalBest.Position, oppPos)
-						}
-					}
-				}
-			}
-
-			// Re-sort after opposition learning
-			sortMayflies(males)
 		}
 
-		// GSASMA: Apply Golden Sine Algorithm with Simulated Annealing to elite males
-		if config.UseGSASMA {
-			// Apply GSA to elite males (top 20%)
-			updatedGlobalBest, updatedGlobalBestCost, gsaFuncEvals := applyGSASMAToEliteMales(
-				males,
-				0.2, // Elite ratio: top 20%
-				globalBest.Position,
-				globalBest.Cost,
-				config.GoldenFactor,
-				it,
-				config.MaxIterations,
-				config.LowerBound,
-				config.UpperBound,
-				annealingScheduler,
-				config.ObjectiveFunc,
-				rng,
-			)
-			funcCount += gsaFuncEvals
-
-			// Update global best if GSA found better solution
-			if updatedGlobalBestCost < globalBest.Cost {
-				globalBest.Cost = updatedGlobalBestCost
-				copy(globalBest.Position, updatedGlobalBest)
-			}
-
-			// Re-sort after Golden Sine updates
-			sortMayflies(males)
-		}
-
-		// Mating - Create offspring
-		offspring := make([]*Mayfly, 0, config.NC)
-
-		for k := 0; k < config.NC/2; k++ {
-			// Select parents (best males and females)
-			p1 := males[k]
-			p2 := females[k]
-
-			// Apply crossover
-			off1Pos, off2Pos := Crossover(p1.Position, p2.Position, config.LowerBound, config.UpperBound, rng)
-
-			// Create offspring 1
-			off1 := newMayfly(config.ProblemSize)
-			copy(off1.Position, off1Pos)
-
-			// OLCE-MA: Apply chaotic exploitation to offspring
-			if config.UseOLCE {
-				for j := 0; j < config.ProblemSize; j++ {
-					chaosValue := chaosMap.Next()
-					perturbation := config.ChaosFactor * (chaosValue - 0.5) * (config.UpperBound - config.LowerBound)
-					off1.Position[j] += perturbation
-
-					// Apply bounds
-					if off1.Position[j] < config.LowerBound {
-						off1.Position[j] = config.LowerBound
-					}
-
-					if off1.Position[j] > config.UpperBound {
-						off1.Position[j] = config.UpperBound
-					}
-				}
-			}
-
-			off1.Cost = config.ObjectiveFunc(off1.Position)
-			funcCount++
-
-			if off1.Cost < globalBest.Cost {
-				globalBest.Cost = off1.Cost
-				copy(globalBest.Position, off1.Position)
-			}
-
-			copy(off1.Best.Position, off1.Position)
-			off1.Best.Cost = off1.Cost
-
-			// Create offspring 2
-			off2 := newMayfly(config.ProblemSize)
-			copy(off2.Position, off2Pos)
-
-			// OLCE-MA: Apply chaotic exploitation to offspring
-			if config.UseOLCE {
-				for j := 0; j < config.ProblemSize; j++ {
-					chaosValue := chaosMap.Next()
-					perturbation := config.ChaosFactor * (chaosValue - 0.5) * (config.UpperBound - config.LowerBound)
-					off2.Position[j] += perturbation
-
-					// Apply bounds
-					if off2.Position[j] < config.LowerBound {
-						off2.Position[j] = config.LowerBound
-					}
-
-					if off2.Position[j] > config.UpperBound {
-						off2.Position[j] = config.UpperBound
-					}
-				}
-			}
-
-			off2.Cost = config.ObjectiveFunc(off2.Position)
-			funcCount++
+		moveMayfly(s, female)
+	}
+}
 
-			if off2.Cost < globalBest.Cost {
-				globalBest.Cost = off2.Cost
-				copy(globalBest.Position, off2.Position)
-			}
+// MoveMales moves each male towards its personal and the global best, or
+// performs the nuptial dance if it is the best male. In niching mode the
+// niche leader takes the place of the global best.
+func (m *standardMover) MoveMales(s *State) {
+	config := s.Config
 
-			copy(off2.Best.Position, off2.Position)
-			off2.Best.Cost = off2.Cost
+	for i := 0; i < config.NPop; i++ {
+		male := s.Males[i]
+		e := unifrndVec(-1, 1, config.ProblemSize, s.Rand)
 
-			offspring = append(offspring, off1, off2)
-		}
+		leader := s.socialBest(male.Position)
 
-		// Mutation
-		// GSASMA: Use hybrid Cauchy-Gaussian mutation
-		if config.UseGSASMA {
-			// Apply hybrid mutation with adaptive Cauchy probability
-			for k := 0; k < config.NM; k++ {
-				// Select parent from offspring
-				i := rng.Intn(len(offspring))
-				p := offspring[i]
-
-				mut := newMayfly(config.ProblemSize)
-
-				// Calculate adaptive Cauchy probability based on iteration progress
-				iterRatio := float64(it) / float64(config.MaxIterations)
-
-				var cauchyProb float64
-
-				if iterRatio < 0.33 {
-					cauchyProb = 0.7 // Early: high exploration
-				} else if iterRatio < 0.66 {
-					cauchyProb = 0.5 // Middle: balanced
-				} else {
-					cauchyProb = config.CauchyMutationRate // Late: configured rate (default 0.3)
-				}
-
-				// Apply hybrid mutation
-				mut.Position = HybridMutate(
-					p.Position,
-					config.Mu,
-					config.LowerBound,
-					config.UpperBound,
-					cauchyProb,
-					rng,
-				)
-
-				// OLCE-MA: Apply chaotic exploitation to mutated offspring if OLCE is also enabled
-				if config.UseOLCE {
-					for j := 0; j < config.ProblemSize; j++ {
-						// Apply chaotic perturbation
-						chaosValue := chaosMap.Next()
-						perturbation := config.ChaosFactor * (chaosValue - 0.5) * (config.UpperBound - config.LowerBound)
-						mut.Position[j] += perturbation
-
-						// Apply bounds
-						if mut.Position[j] < config.LowerBound {
-							mut.Position[j] = config.LowerBound
-						}
-
-						if mut.Position[j] > config.UpperBound {
-							mut.Position[j] = config.UpperBound
-						}
-					}
-				}
-
-				mut.Cost = config.ObjectiveFunc(mut.Position)
-				funcCount++
-
-				if mut.Cost < globalBest.Cost {
-					globalBest.Cost = mut.Cost
-					copy(globalBest.Position, mut.Position)
-				}
-
-				copy(mut.Best.Position, mut.Position)
-				mut.Best.Cost = mut.Cost
-
-				offspring = append(offspring, mut)
+		if male.Cost > leader.Cost {
+			// Update velocity with personal and global best
+			c := s.coefficients(male, ruleFollow)
+			for j := 0; j < config.ProblemSize; j++ {
+				rpbest := male.Best.Position[j] - male.Position[j]
+				rgbest := leader.Position[j] - male.Position[j]
+				male.Velocity[j] = s.G*male.Velocity[j] +
+					c.A1*math.Exp(-c.Beta*rpbest*rpbest)*(male.Best.Position[j]-male.Position[j]) +
+					c.A2*math.Exp(-c.Beta*rgbest*rgbest)*(leader.Position[j]-male.Position[j])
 			}
 		} else {
-			// Standard mutation
-			for k := 0; k < config.NM; k++ {
-				// Select parent from offspring
-				i := rng.Intn(len(offspring))
-				p := offspring[i]
-
-				mut := newMayfly(config.ProblemSize)
-				mut.Position = Mutate(p.Position, config.Mu, config.LowerBound, config.UpperBound, rng)
-
-				// OLCE-MA: Apply chaotic exploitation to mutated offspring
-				if config.UseOLCE {
-					for j := 0; j < config.ProblemSize; j++ {
-						// Apply chaotic perturbation
-						chaosValue := chaosMap.Next()
-						perturbation := config.ChaosFactor * (chaosValue - 0.5) * (config.UpperBound - config.LowerBound)
-						mut.Position[j] += perturbation
-
-						// Apply bounds
-						if mut.Position[j] < config.LowerBound {
-							mut.Position[j] = config.LowerBound
-						}
-
-						if mut.Position[j] > config.UpperBound {
-							mut.Position[j] = config.UpperBound
-						}
-					}
-				}
-
-				mut.Cost = config.ObjectiveFunc(mut.Position)
-				funcCount++
-
-				if mut.Cost < globalBest.Cost {
-					globalBest.Cost = mut.Cost
-					copy(globalBest.Position, mut.Position)
-				}
-
-				copy(mut.Best.Position, mut.Position)
-				mut.Best.Cost = mut.Cost
-
-				offspring = append(offspring, mut)
-			}
+			nuptialDance(s, male, s.G, s.coefficients(male, ruleDance).Dance, e)
 		}
 
-		// Merge offspring into populations
-		split := len(offspring) / 2
-		males = append(males, offspring[:split]...)
-		females = append(females, offspring[split:]...)
-
-		// Sort and keep best
-		sortMayflies(males)
-		sortMayflies(females)
-
-		males = males[:config.NPop]
-		females = females[:config.NPopF]
-
-		// DESMA: Apply dynamic elite strategy
-		if config.UseDESMA {
-			// Dynamically adjust search range based on improvement
-			if globalBest.Cost < lastGlobalBestCost {
-				// Improving: enlarge search range
-				searchRange *= config.EnlargeFactor
-			} else {
-				// Not improving: reduce search range
-				searchRange *= config.ReductionFactor
-			}
-
-			// Generate elite mayflies around global best
-			eliteMayfly, eliteFuncCount := generateEliteMayflies(
-				globalBest,
-				searchRange,
-				config.EliteCount,
-				config.ProblemSize,
-				config.LowerBound,
-				config.UpperBound,
-				config.ObjectiveFunc,
-				rng,
-			)
-			funcCount += eliteFuncCount
-
-			// Replace worst male if elite is better
-			if eliteMayfly.Cost < males[config.NPop-1].Cost {
-				males[config.NPop-1] = eliteMayfly
-				sortMayflies(males) // Re-sort after replacement
-
-				// Update global best if elite is the new best
-				if eliteMayfly.Cost < globalBest.Cost {
-					globalBest.Cost = eliteMayfly.Cost
-					copy(globalBest.Position, eliteMayfly.Position)
-				}
-			}
+		moveMayfly(s, male)
+		updatePersonalBest(s, male)
+	}
+}
 
-			lastGlobalBestCost = globalBest.Cost
-		}
+// moveMayfly clamps the velocity, moves the mayfly and evaluates its new
+// position. In surrogate-assisted mode the evaluation is deferred until the
+// surrogate has screened all moves of the phase.
+func moveMayfly(s *State, mayfly *Mayfly) {
+	config := s.Config
 
-		// GSASMA: Apply Opposition-Based Learning to global best
-		if config.UseGSASMA && config.ApplyOBLToGlobalBest {
-			// Apply OBL every 10 iterations to avoid excessive function evaluations
-			if it%10 == 0 {
-				updatedGlobalBest, updatedGlobalBestCost, oblFuncEvals, improved := applyOBLToGlobalBest(
-					globalBest.Position,
-					globalBest.Cost,
-					config.LowerBound,
-					config.UpperBound,
-					config.ObjectiveFunc,
-					rng,
-				)
-				funcCount += oblFuncEvals
-
-				if improved {
-					globalBest.Cost = updatedGlobalBestCost
-					copy(globalBest.Position, updatedGlobalBest)
-				}
-			}
-		}
+	var previous []float64
+	if s.surrogate != nil {
+		previous = append(previous, mayfly.Position...)
+	}
 
-		// AOBLMOA: Update Pareto archive
-		if config.UseAOBLMOA {
-			updateParetoArchive(paretoArchive, males, females)
-		}
+	// Apply velocity limits
+	maxVec(mayfly.Velocity, config.VelMin)
+	minVec(mayfly.Velocity, config.VelMax)
 
-		bestSolution[it] = globalBest.Cost
+	// Update position
+	for j := 0; j < config.ProblemSize; j++ {
+		mayfly.Position[j] += mayfly.Velocity[j]
+	}
 
-		// GSASMA: Update temperature schedule
-		if config.UseGSASMA {
-			annealingScheduler.Update()
-		}
+	// Apply position limits
+	maxVec(mayfly.Position, config.LowerBound)
+	minVec(mayfly.Position, config.UpperBound)
 
-		// Update parameters
-		g *= config.GDamp
-		dance *= config.DanceDamp
-		fl *= config.FLDamp
+	if s.surrogate != nil {
+		s.surrogate.deferMove(mayfly, previous)
+		return
 	}
 
-	return &Result{
-		GlobalBest:     globalBest,
-		BestSolution:   bestSolution,
-		FuncEvalCount:  funcCount,
-		IterationCount: config.MaxIterations,
-		Seed:           seed,
-	}, nil
+	// Evaluate
+	mayfly.Cost = s.Evaluate(mayfly.Position)
 }
 
+// updatePersonalBest records the current position of a mayfly as its
+// personal best if it improved, and propagates it to the global best.
+func updatePersonalBest(s *State, mayfly *Mayfly) {
+	if mayfly.Cost < mayfly.Best.Cost {
+		copy(mayfly.Best.Position, mayfly.Position)
+		mayfly.Best.Cost = mayfly.Cost
+
+		s.UpdateGlobalBest(mayfly.Best.Position, mayfly.Best.Cost)
+	}
+}
@@ -0,0 +1,853 @@
+// Package mayfly - Multi-objective benchmark problems
+//
+// ZDT (Zitzler, Deb & Thiele, 2000), DTLZ (Deb, Thiele, Laumanns & Zitzler,
+// 2002) and WFG (Huband, Hingston, Barone & While, 2006) test problems, each
+// with a generator for its true Pareto front. All objectives are minimized.
+//
+// Every problem is defined on the unit hypercube [0, 1]^n, so it can be
+// optimized with LowerBound = 0 and UpperBound = 1:
+//   - ZDT4 maps x_2, ..., x_n linearly to [-5, 5]
+//   - ZDT5 is binary; variable x_i is a bit that is set if x_i >= 0.5
+//   - WFG variables are the normalized values z_i / (2i)
+package mayfly
+
+import (
+	"fmt"
+	"math"
+)
+
+// MultiObjectiveProblem is a multi-objective benchmark problem with its
+// standard problem size and a generator for its true Pareto front.
+type MultiObjectiveProblem struct {
+	Name        string
+	Func        MultiObjectiveFunction
+	ProblemSize int
+	Objectives  int
+	LowerBound  float64
+	UpperBound  float64
+
+	// TrueFront returns about points non-dominated objective vectors of the
+	// true Pareto front, e.g. as reference front for CalculateIGD. Only
+	// ObjectiveValues are set. Disconnected fronts return fewer points.
+	TrueFront func(points int) []*ParetoSolution
+
+	// Constraints returns the constraint values of constrained problems
+	// (c_j(x) >= 0 is feasible) and is nil otherwise.
+	Constraints ConstraintFunction
+}
+
+// ZDTProblems returns ZDT1-6 with their standard problem sizes.
+func ZDTProblems() []MultiObjectiveProblem {
+	zdt1Front := func(points int) []*ParetoSolution {
+		return zdtFront(points, 0, func(f1 float64) float64 { return 1 - math.Sqrt(f1) })
+	}
+
+	return []MultiObjectiveProblem{
+		{"ZDT1", ZDT1, 30, 2, 0, 1, zdt1Front, nil},
+		{"ZDT2", ZDT2, 30, 2, 0, 1, func(points int) []*ParetoSolution {
+			return zdtFront(points, 0, func(f1 float64) float64 { return 1 - f1*f1 })
+		}, nil},
+		{"ZDT3", ZDT3, 30, 2, 0, 1, func(points int) []*ParetoSolution {
+			return zdtFront(points, 0, func(f1 float64) float64 {
+				return 1 - math.Sqrt(f1) - f1*math.Sin(10*math.Pi*f1)
+			})
+		}, nil},
+		{"ZDT4", ZDT4, 10, 2, 0, 1, zdt1Front, nil},
+		{"ZDT5", ZDT5, 80, 2, 0, 1, func(int) []*ParetoSolution {
+			// x_1 has 30 bits, and each of the 10 other groups contributes g = 1
+			front := make([][]float64, 31)
+			for i := range front {
+				front[i] = []float64{float64(i + 1), 10 / float64(i+1)}
+			}
+
+			return nondominatedFront(front)
+		}, nil},
+		{"ZDT6", ZDT6, 10, 2, 0, 1, func(points int) []*ParetoSolution {
+			return zdtFront(points, 0.2807753191, func(f1 float64) float64 { return 1 - f1*f1 })
+		}, nil},
+	}
+}
+
+// DTLZProblems returns DTLZ1-7 for the given number of objectives (at least
+// 2). The problem sizes are objectives - 1 + k with the recommended k = 5
+// for DTLZ1, k = 10 for DTLZ2-6 and k = 20 for DTLZ7.
+func DTLZProblems(objectives int) []MultiObjectiveProblem {
+	problem := func(name string, f MultiObjectiveFunction, k int, front func(int) []*ParetoSolution) MultiObjectiveProblem {
+		return MultiObjectiveProblem{name, f, objectives - 1 + k, objectives, 0, 1, front, nil}
+	}
+
+	simplex := func(radius float64, spherical bool) func(int) []*ParetoSolution {
+		return func(points int) []*ParetoSolution {
+			front := DasDennisPoints(objectives, dasDennisDivisions(objectives, points))
+			for _, p := range front {
+				scale := radius
+				if spherical {
+					scale /= norm(p)
+				}
+
+				for j := range p {
+					p[j] *= scale
+				}
+			}
+
+			return nondominatedFront(front)
+		}
+	}
+
+	// The DTLZ5 and DTLZ6 fronts are the same curve on the unit sphere
+	curve := func(points int) []*ParetoSolution {
+		f := DTLZ5(objectives)
+		front := make([][]float64, 0, points)
+
+		for _, t := range linspace(0, 1, points) {
+			x := make([]float64, objectives)
+			for i := range x {
+				x[i] = 0.5
+			}
+
+			x[0] = t
+			front = append(front, f(x))
+		}
+
+		return nondominatedFront(front)
+	}
+
+	disconnected := func(points int) []*ParetoSolution {
+		f := DTLZ7(objectives)
+		front := make([][]float64, 0, points)
+
+		for _, position := range unitGrid(objectives-1, points) {
+			front = append(front, f(append(position, 0)))
+		}
+
+		return nondominatedFront(front)
+	}
+
+	return []MultiObjectiveProblem{
+		problem("DTLZ1", DTLZ1(objectives), 5, simplex(0.5, false)),
+		problem("DTLZ2", DTLZ2(objectives), 10, simplex(1, true)),
+		problem("DTLZ3", DTLZ3(objectives), 10, simplex(1, true)),
+		problem("DTLZ4", DTLZ4(objectives), 10, simplex(1, true)),
+		problem("DTLZ5", DTLZ5(objectives), 10, curve),
+		problem("DTLZ6", DTLZ6(objectives), 10, curve),
+		problem("DTLZ7", DTLZ7(objectives), 20, disconnected),
+	}
+}
+
+// WFGProblems returns WFG1-9 for the given number of objectives (at least
+// 2), with 2·(objectives-1) position and 20 distance parameters.
+func WFGProblems(objectives int) []MultiObjectiveProblem {
+	size := wfgPositionParams(objectives) + 20
+
+	problem := func(i int, f MultiObjectiveFunction, degenerate bool, shape wfgShape) MultiObjectiveProblem {
+		return MultiObjectiveProblem{fmt.Sprintf("WFG%d", i), f, size, objectives, 0, 1, wfgFront(objectives, degenerate, shape), nil}
+	}
+
+	return []MultiObjectiveProblem{
+		problem(1, WFG1(objectives), false, wfg1Shape),
+		problem(2, WFG2(objectives), false, wfg2Shape),
+		problem(3, WFG3(objectives), true, wfgLinear),
+		problem(4, WFG4(objectives), false, wfgConcave),
+		problem(5, WFG5(objectives), false, wfgConcave),
+		problem(6, WFG6(objectives), false, wfgConcave),
+		problem(7, WFG7(objectives), false, wfgConcave),
+		problem(8, WFG8(objectives), false, wfgConcave),
+		problem(9, WFG9(objectives), false, wfgConcave),
+	}
+}
+
+// =============================================================================
+// ZDT
+// =============================================================================
+
+// ZDT1 has a convex front f2 = 1 - sqrt(f1) at x_2 = ... = x_n = 0 (standard n = 30).
+func ZDT1(x []float64) []float64 {
+	g := zdtG(x)
+	return []float64{x[0], g * (1 - math.Sqrt(x[0]/g))}
+}
+
+// ZDT2 has a concave front f2 = 1 - f1² at x_2 = ... = x_n = 0 (standard n = 30).
+func ZDT2(x []float64) []float64 {
+	g := zdtG(x)
+	return []float64{x[0], g * (1 - math.Pow(x[0]/g, 2))}
+}
+
+// ZDT3 has a front of five disconnected parts at x_2 = ... = x_n = 0 (standard n = 30).
+func ZDT3(x []float64) []float64 {
+	g := zdtG(x)
+	return []float64{x[0], g * (1 - math.Sqrt(x[0]/g) - x[0]/g*math.Sin(10*math.Pi*x[0]))}
+}
+
+// ZDT4 is ZDT1 with a multimodal g (21^9 local fronts). x_2, ..., x_n are
+// mapped to [-5, 5]; the front is at x_2 = ... = x_n = 0.5 (standard n = 10).
+func ZDT4(x []float64) []float64 {
+	g := 1 + 10*float64(len(x)-1)
+	for _, v := range x[1:] {
+		y := 10*v - 5
+		g += y*y - 10*math.Cos(4*math.Pi*y)
+	}
+
+	return []float64{x[0], g * (1 - math.Sqrt(x[0]/g))}
+}
+
+// ZDT5 is a deceptive binary problem. The first 30 variables form x_1, every
+// following group of 5 variables one further substring; the standard size is
+// 80. The front is f2 = 10/f1 for f1 = 1, ..., 31 at all bits of the groups set.
+func ZDT5(x []float64) []float64 {
+	f1 := 1 + float64(countBits(x[:30]))
+
+	g := 0.0
+	for i := 30; i+5 <= len(x); i += 5 {
+		if u := countBits(x[i : i+5]); u < 5 {
+			g += 2 + float64(u)
+		} else {
+			g++
+		}
+	}
+
+	return []float64{f1, g / f1}
+}
+
+// ZDT6 has a concave front with a non-uniform density at x_2 = ... = x_n = 0 (standard n = 10).
+func ZDT6(x []float64) []float64 {
+	f1 := 1 - math.Exp(-4*x[0])*math.Pow(math.Sin(6*math.Pi*x[0]), 6)
+
+	sum := 0.0
+	for _, v := range x[1:] {
+		sum += v
+	}
+
+	g := 1 + 9*math.Pow(sum/float64(len(x)-1), 0.25)
+
+	return []float64{f1, g * (1 - math.Pow(f1/g, 2))}
+}
+
+// zdtG is the distance function of ZDT1-3.
+func zdtG(x []float64) float64 {
+	sum := 0.0
+	for _, v := range x[1:] {
+		sum += v
+	}
+
+	return 1 + 9*sum/float64(len(x)-1)
+}
+
+// countBits returns the number of variables that are set as bits.
+func countBits(x []float64) int {
+	bits := 0
+
+	for _, v := range x {
+		if v >= 0.5 {
+			bits++
+		}
+	}
+
+	return bits
+}
+
+// zdtFront samples f1 uniformly in [from, 1].
+func zdtFront(points int, from float64, f2 func(float64) float64) []*ParetoSolution {
+	front := make([][]float64, 0, points)
+	for _, f1 := range linspace(from, 1, points) {
+		front = append(front, []float64{f1, f2(f1)})
+	}
+
+	return nondominatedFront(front)
+}
+
+// =============================================================================
+// DTLZ
+// =============================================================================
+
+// DTLZ1 has a linear front Σ f_m = 0.5 and a multimodal g. The front is at
+// x_M = ... = x_n = 0.5.
+func DTLZ1(objectives int) MultiObjectiveFunction {
+	return func(x []float64) []float64 {
+		g := dtlzMultimodalG(x[objectives-1:])
+		f := make([]float64, objectives)
+
+		for i := range f {
+			f[i] = 0.5 * (1 + g)
+			for j := 0; j < objectives-1-i; j++ {
+				f[i] *= x[j]
+			}
+
+			if i > 0 {
+				f[i] *= 1 - x[objectives-1-i]
+			}
+		}
+
+		return f
+	}
+}
+
+// DTLZ2 has a spherical front ‖f‖ = 1 at x_M = ... = x_n = 0.5.
+func DTLZ2(objectives int) MultiObjectiveFunction {
+	return func(x []float64) []float64 {
+		return dtlzSpherical(dtlzAngles(x[:objectives-1], 1), 1+dtlzSphereG(x[objectives-1:]))
+	}
+}
+
+// DTLZ3 is DTLZ2 with the multimodal g of DTLZ1.
+func DTLZ3(objectives int) MultiObjectiveFunction {
+	return func(x []float64) []float64 {
+		return dtlzSpherical(dtlzAngles(x[:objectives-1], 1), 1+dtlzMultimodalG(x[objectives-1:]))
+	}
+}
+
+// DTLZ4 is DTLZ2 with the position variables raised to the power 100, which
+// biases solutions towards the edges of the front.
+func DTLZ4(objectives int) MultiObjectiveFunction {
+	return func(x []float64) []float64 {
+		return dtlzSpherical(dtlzAngles(x[:objectives-1], 100), 1+dtlzSphereG(x[objectives-1:]))
+	}
+}
+
+// DTLZ5 has a degenerate front, a curve on the unit sphere, at
+// x_M = ... = x_n = 0.5.
+func DTLZ5(objectives int) MultiObjectiveFunction {
+	return func(x []float64) []float64 {
+		g := dtlzSphereG(x[objectives-1:])
+		return dtlzSpherical(dtlzDegenerateAngles(x[:objectives-1], g), 1+g)
+	}
+}
+
+// DTLZ6 is DTLZ5 with the harder g = Σ x_i^0.1; the front is at
+// x_M = ... = x_n = 0.
+func DTLZ6(objectives int) MultiObjectiveFunction {
+	return func(x []float64) []float64 {
+		g := 0.0
+		for _, v := range x[objectives-1:] {
+			g += math.Pow(v, 0.1)
+		}
+
+		return dtlzSpherical(dtlzDegenerateAngles(x[:objectives-1], g), 1+g)
+	}
+}
+
+// DTLZ7 has 2^(M-1) disconnected front regions at x_M = ... = x_n = 0.
+func DTLZ7(objectives int) MultiObjectiveFunction {
+	return func(x []float64) []float64 {
+		distance := x[objectives-1:]
+
+		g := 0.0
+		for _, v := range distance {
+			g += v
+		}
+
+		g = 1 + 9*g/float64(len(distance))
+
+		f := make([]float64, objectives)
+		h := float64(objectives)
+
+		for i := 0; i < objectives-1; i++ {
+			f[i] = x[i]
+			h -= f[i] / (1 + g) * (1 + math.Sin(3*math.Pi*f[i]))
+		}
+
+		f[objectives-1] = (1 + g) * h
+
+		return f
+	}
+}
+
+// dtlzSphereG is the unimodal distance function of DTLZ2, DTLZ4 and DTLZ5.
+func dtlzSphereG(x []float64) float64 {
+	g := 0.0
+	for _, v := range x {
+		g += (v - 0.5) * (v - 0.5)
+	}
+
+	return g
+}
+
+// dtlzMultimodalG is the Rastrigin-like distance function of DTLZ1 and DTLZ3.
+func dtlzMultimodalG(x []float64) float64 {
+	g := float64(len(x))
+	for _, v := range x {
+		g += (v-0.5)*(v-0.5) - math.Cos(20*math.Pi*(v-0.5))
+	}
+
+	return 100 * g
+}
+
+// dtlzAngles maps position variables to angles x^alpha·π/2.
+func dtlzAngles(x []float64, alpha float64) []float64 {
+	theta := make([]float64, len(x))
+	for i, v := range x {
+		theta[i] = math.Pow(v, alpha) * math.Pi / 2
+	}
+
+	return theta
+}
+
+// dtlzDegenerateAngles maps position variables to the angles of DTLZ5 and
+// DTLZ6, which collapse to π/4 except the first when g = 0.
+func dtlzDegenerateAngles(x []float64, g float64) []float64 {
+	theta := make([]float64, len(x))
+	for i, v := range x {
+		if i == 0 {
+			theta[i] = v * math.Pi / 2
+		} else {
+			theta[i] = math.Pi / (4 * (1 + g)) * (1 + 2*g*v)
+		}
+	}
+
+	return theta
+}
+
+// dtlzSpherical returns the point with the given angles on the sphere of
+// the given radius.
+func dtlzSpherical(theta []float64, radius float64) []float64 {
+	m := len(theta) + 1
+	f := make([]float64, m)
+
+	for i := range f {
+		f[i] = radius
+		for j := 0; j < m-1-i; j++ {
+			f[i] *= math.Cos(theta[j])
+		}
+
+		if i > 0 {
+			f[i] *= math.Sin(theta[m-1-i])
+		}
+	}
+
+	return f
+}
+
+// =============================================================================
+// WFG
+// =============================================================================
+
+// wfgShape returns the shape function h_m (m = 1, ..., M) of x ∈ [0, 1]^(M-1).
+type wfgShape func(x []float64, m int) float64
+
+// WFG1 has a mixed convex front with flat bias and a polynomial bias on all
+// variables. The problem size is k + l with k = 2·(objectives-1) position
+// parameters; the front is at x_(k+1) = ... = x_n = 0.35.
+func WFG1(objectives int) MultiObjectiveFunction {
+	return newWFG(objectives, false, wfg1Shape, func(y []float64, k int) []float64 {
+		for i := k; i < len(y); i++ {
+			y[i] = wfgBFlat(wfgSLinear(y[i], 0.35), 0.8, 0.75, 0.85)
+		}
+
+		for i := range y {
+			y[i] = math.Pow(y[i], 0.02)
+		}
+
+		return wfgReduce(y, k, objectives, func(group []float64, offset int) float64 {
+			weights := make([]float64, len(group))
+			for j := range weights {
+				weights[j] = 2 * float64(offset+j+1)
+			}
+
+			return wfgRSum(group, weights)
+		})
+	})
+}
+
+// WFG2 has a disconnected convex front and non-separable distance
+// parameters. The number of distance parameters must be even; the front is
+// at x_(k+1) = ... = x_n = 0.35.
+func WFG2(objectives int) MultiObjectiveFunction {
+	return newWFG(objectives, false, wfg2Shape, wfg2Transform(objectives))
+}
+
+// WFG3 has a degenerate linear front and is otherwise WFG2. The front is at
+// x_(k+1) = ... = x_n = 0.35.
+func WFG3(objectives int) MultiObjectiveFunction {
+	return newWFG(objectives, true, wfgLinear, wfg2Transform(objectives))
+}
+
+// WFG4 has a concave front and a highly multimodal landscape. The front is
+// at x_(k+1) = ... = x_n = 0.35.
+func WFG4(objectives int) MultiObjectiveFunction {
+	return newWFG(objectives, false, wfgConcave, func(y []float64, k int) []float64 {
+		for i := range y {
+			y[i] = wfgSMulti(y[i], 30, 10, 0.35)
+		}
+
+		return wfgReduce(y, k, objectives, wfgMean)
+	})
+}
+
+// WFG5 has a concave front and a deceptive landscape. The front is at
+// x_(k+1) = ... = x_n = 0.35.
+func WFG5(objectives int) MultiObjectiveFunction {
+	return newWFG(objectives, false, wfgConcave, func(y []float64, k int) []float64 {
+		for i := range y {
+			y[i] = wfgSDecept(y[i], 0.35, 0.001, 0.05)
+		}
+
+		return wfgReduce(y, k, objectives, wfgMean)
+	})
+}
+
+// WFG6 has a concave front and non-separable parameters. The front is at
+// x_(k+1) = ... = x_n = 0.35.
+func WFG6(objectives int) MultiObjectiveFunction {
+	return newWFG(objectives, false, wfgConcave, func(y []float64, k int) []float64 {
+		for i := k; i < len(y); i++ {
+			y[i] = wfgSLinear(y[i], 0.35)
+		}
+
+		return wfgReduce(y, k, objectives, func(group []float64, _ int) float64 {
+			return wfgRNonsep(group, len(group))
+		})
+	})
+}
+
+// WFG7 has a concave front and position parameters whose bias depends on
+// the distance parameters. The front is at x_(k+1) = ... = x_n = 0.35.
+func WFG7(objectives int) MultiObjectiveFunction {
+	return newWFG(objectives, false, wfgConcave, func(y []float64, k int) []float64 {
+		original := append([]float64(nil), y...)
+		for i := 0; i < k; i++ {
+			y[i] = wfgBParam(y[i], wfgMean(original[i+1:], 0), 0.98/49.98, 0.02, 50)
+		}
+
+		for i := k; i < len(y); i++ {
+			y[i] = wfgSLinear(y[i], 0.35)
+		}
+
+		return wfgReduce(y, k, objectives, wfgMean)
+	})
+}
+
+// WFG8 has a concave front and distance parameters whose bias depends on all
+// preceding parameters, so the optimal distance parameters vary along the
+// front.
+func WFG8(objectives int) MultiObjectiveFunction {
+	return newWFG(objectives, false, wfgConcave, func(y []float64, k int) []float64 {
+		original := append([]float64(nil), y...)
+		for i := k; i < len(y); i++ {
+			y[i] = wfgSLinear(wfgBParam(y[i], wfgMean(original[:i], 0), 0.98/49.98, 0.02, 50), 0.35)
+		}
+
+		return wfgReduce(y, k, objectives, wfgMean)
+	})
+}
+
+// WFG9 has a concave front, parameter-dependent bias, deceptive and
+// multimodal position and distance parameters and non-separable reduction.
+func WFG9(objectives int) MultiObjectiveFunction {
+	return newWFG(objectives, false, wfgConcave, func(y []float64, k int) []float64 {
+		original := append([]float64(nil), y...)
+		for i := 0; i < len(y)-1; i++ {
+			y[i] = wfgBParam(y[i], wfgMean(original[i+1:], 0), 0.98/49.98, 0.02, 50)
+		}
+
+		for i := range y {
+			if i < k {
+				y[i] = wfgSDecept(y[i], 0.35, 0.001, 0.05)
+			} else {
+				y[i] = wfgSMulti(y[i], 30, 95, 0.35)
+			}
+		}
+
+		return wfgReduce(y, k, objectives, func(group []float64, _ int) float64 {
+			return wfgRNonsep(group, len(group))
+		})
+	})
+}
+
+// wfgPositionParams returns the number of position parameters k.
+func wfgPositionParams(objectives int) int {
+	return 2 * (objectives - 1)
+}
+
+// newWFG builds a WFG problem from its transformation, which maps the
+// normalized variables (a copy that it may modify) to t_1, ..., t_M, and its
+// shape.
+func newWFG(objectives int, degenerate bool, shape wfgShape, transform func(y []float64, k int) []float64) MultiObjectiveFunction {
+	k := wfgPositionParams(objectives)
+
+	return func(x []float64) []float64 {
+		y := make([]float64, len(x))
+		for i, v := range x {
+			y[i] = clamp01(v)
+		}
+
+		return wfgObjectives(transform(y, k), degenerate, shape)
+	}
+}
+
+// wfgObjectives returns f_m = x_M + 2m·h_m for the transformed parameters t.
+func wfgObjectives(t []float64, degenerate bool, shape wfgShape) []float64 {
+	m := len(t)
+	distance := t[m-1]
+
+	x := make([]float64, m-1)
+	for i := range x {
+		a := 1.0
+		if degenerate && i > 0 {
+			a = 0
+		}
+
+		x[i] = math.Max(distance, a)*(t[i]-0.5) + 0.5
+	}
+
+	f := make([]float64, m)
+	for i := range f {
+		f[i] = distance + 2*float64(i+1)*shape(x, i+1)
+	}
+
+	return f
+}
+
+// wfgFront samples the shape at distance 0 on a grid of the position
+// parameters.
+func wfgFront(objectives int, degenerate bool, shape wfgShape) func(int) []*ParetoSolution {
+	return func(points int) []*ParetoSolution {
+		dims := objectives - 1
+		if degenerate {
+			dims = 1
+		}
+
+		front := make([][]float64, 0, points)
+
+		for _, position := range unitGrid(dims, points) {
+			t := make([]float64, objectives)
+			for i := range t[:objectives-1] {
+				t[i] = 0.5
+			}
+
+			copy(t, position)
+			t[objectives-1] = 0
+
+			front = append(front, wfgObjectives(t, degenerate, shape))
+		}
+
+		return nondominatedFront(front)
+	}
+}
+
+// wfg2Transform is the transformation of WFG2 and WFG3.
+func wfg2Transform(objectives int) func(y []float64, k int) []float64 {
+	return func(y []float64, k int) []float64 {
+		for i := k; i < len(y); i++ {
+			y[i] = wfgSLinear(y[i], 0.35)
+		}
+
+		reduced := append([]float64(nil), y[:k]...)
+		for i := k; i+1 < len(y); i += 2 {
+			reduced = append(reduced, wfgRNonsep(y[i:i+2], 2))
+		}
+
+		return wfgReduce(reduced, k, objectives, wfgMean)
+	}
+}
+
+// wfgReduce reduces the k position parameters in objectives-1 equal groups
+// and the distance parameters to one value each. reduce receives the group
+// and the index of its first parameter.
+func wfgReduce(y []float64, k, objectives int, reduce func(group []float64, offset int) float64) []float64 {
+	t := make([]float64, objectives)
+	size := k / (objectives - 1)
+
+	for i := 0; i < objectives-1; i++ {
+		t[i] = reduce(y[i*size:(i+1)*size], i*size)
+	}
+
+	t[objectives-1] = reduce(y[k:], k)
+
+	return t
+}
+
+func wfg1Shape(x []float64, m int) float64 {
+	if m == len(x)+1 {
+		return wfgMixed(x, 5, 1)
+	}
+
+	return wfgConvex(x, m)
+}
+
+func wfg2Shape(x []float64, m int) float64 {
+	if m == len(x)+1 {
+		return wfgDisc(x, 5, 1, 1)
+	}
+
+	return wfgConvex(x, m)
+}
+
+func wfgLinear(x []float64, m int) float64 {
+	n := len(x) + 1 - m
+
+	h := 1.0
+	for _, v := range x[:n] {
+		h *= v
+	}
+
+	if m > 1 {
+		h *= 1 - x[n]
+	}
+
+	return h
+}
+
+func wfgConvex(x []float64, m int) float64 {
+	n := len(x) + 1 - m
+
+	h := 1.0
+	for _, v := range x[:n] {
+		h *= 1 - math.Cos(v*math.Pi/2)
+	}
+
+	if m > 1 {
+		h *= 1 - math.Sin(x[n]*math.Pi/2)
+	}
+
+	return h
+}
+
+func wfgConcave(x []float64, m int) float64 {
+	n := len(x) + 1 - m
+
+	h := 1.0
+	for _, v := range x[:n] {
+		h *= math.Sin(v * math.Pi / 2)
+	}
+
+	if m > 1 {
+		h *= math.Cos(x[n] * math.Pi / 2)
+	}
+
+	return h
+}
+
+func wfgMixed(x []float64, a, alpha float64) float64 {
+	return math.Pow(1-x[0]-math.Cos(2*a*math.Pi*x[0]+math.Pi/2)/(2*a*math.Pi), alpha)
+}
+
+func wfgDisc(x []float64, a, alpha, beta float64) float64 {
+	return 1 - math.Pow(x[0], alpha)*math.Pow(math.Cos(a*math.Pow(x[0], beta)*math.Pi), 2)
+}
+
+// wfgSLinear is the linear shift transformation with optimum at a.
+func wfgSLinear(y, a float64) float64 {
+	return clamp01(math.Abs(y-a) / math.Abs(math.Floor(a-y)+a))
+}
+
+// wfgSDecept is the deceptive shift transformation with global optimum at a.
+func wfgSDecept(y, a, b, c float64) float64 {
+	left := math.Floor(y-a+b) * (1 - c + (a-b)/b) / (a - b)
+	right := math.Floor(a+b-y) * (1 - c + (1-a-b)/b) / (1 - a - b)
+
+	return clamp01(1 + (math.Abs(y-a)-b)*(left+right+1/b))
+}
+
+// wfgSMulti is the multimodal shift transformation with global optimum at c.
+func wfgSMulti(y, a, b, c float64) float64 {
+	d := math.Abs(y-c) / (2 * (math.Floor(c-y) + c))
+	return clamp01((1 + math.Cos((4*a+2)*math.Pi*(0.5-d)) + 4*b*d*d) / (b + 2))
+}
+
+// wfgBFlat is the flat region bias transformation.
+func wfgBFlat(y, a, b, c float64) float64 {
+	return clamp01(a + math.Min(0, math.Floor(y-b))*a*(b-y)/b - math.Min(0, math.Floor(c-y))*(1-a)*(y-c)/(1-c))
+}
+
+// wfgBParam is the parameter-dependent bias transformation.
+func wfgBParam(y, u, a, b, c float64) float64 {
+	return clamp01(math.Pow(y, b+(c-b)*(a-(1-2*u)*math.Abs(math.Floor(0.5-u)+a))))
+}
+
+// wfgRSum is the weighted sum reduction.
+func wfgRSum(y, weights []float64) float64 {
+	sum, total := 0.0, 0.0
+	for i, v := range y {
+		sum += weights[i] * v
+		total += weights[i]
+	}
+
+	return clamp01(sum / total)
+}
+
+// wfgMean is the reduction with equal weights.
+func wfgMean(y []float64, _ int) float64 {
+	sum := 0.0
+	for _, v := range y {
+		sum += v
+	}
+
+	return clamp01(sum / float64(len(y)))
+}
+
+// wfgRNonsep is the non-separable reduction with degree a.
+func wfgRNonsep(y []float64, a int) float64 {
+	n := len(y)
+
+	sum := 0.0
+	for j := 0; j < n; j++ {
+		sum += y[j]
+		for k := 0; k <= a-2; k++ {
+			sum += math.Abs(y[j] - y[(j+k+1)%n])
+		}
+	}
+
+	half := math.Ceil(float64(a) / 2)
+
+	return clamp01(sum / (float64(n) / float64(a) * half * (1 + 2*float64(a) - 2*half)))
+}
+
+// clamp01 clamps v to [0, 1], absorbing rounding errors of the WFG
+// transformations.
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// =============================================================================
+// Front sampling
+// =============================================================================
+
+// nondominatedFront returns the non-dominated objective vectors as solutions.
+func nondominatedFront(points [][]float64) []*ParetoSolution {
+	points = nondominatedPoints(points)
+
+	front := make([]*ParetoSolution, len(points))
+	for i, p := range points {
+		front[i] = &ParetoSolution{ObjectiveValues: p}
+	}
+
+	return front
+}
+
+// linspace returns n evenly spaced values from a to b.
+func linspace(a, b float64, n int) []float64 {
+	if n < 2 {
+		return []float64{a}
+	}
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = a + (b-a)*float64(i)/float64(n-1)
+	}
+
+	return values
+}
+
+// unitGrid returns a regular grid of at most points points (at least 2 per
+// dimension) in [0, 1]^dims.
+func unitGrid(dims, points int) [][]float64 {
+	steps := int(math.Pow(float64(points), 1/float64(dims)) + 1e-9)
+	if steps < 2 {
+		steps = 2
+	}
+
+	axis := linspace(0, 1, steps)
+	grid := [][]float64{{}}
+
+	for d := 0; d < dims; d++ {
+		next := make([][]float64, 0, len(grid)*steps)
+		for _, prefix := range grid {
+			for _, v := range axis {
+				next = append(next, append(append([]float64(nil), prefix...), v))
+			}
+		}
+
+		grid = next
+	}
+
+	return grid
+}
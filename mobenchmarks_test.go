@@ -0,0 +1,194 @@
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for mobenchmarks.go - Multi-objective benchmark problems
+// =============================================================================
+
+// paretoOptimalPosition returns a random position on the true front of the
+// problem: random position variables, optimal distance variables.
+func paretoOptimalPosition(problem MultiObjectiveProblem, rng *rand.Rand) []float64 {
+	x := make([]float64, problem.ProblemSize)
+	for i := range x {
+		x[i] = rng.Float64()
+	}
+
+	position := problem.Objectives - 1
+	k := wfgPositionParams(problem.Objectives)
+	bParamExponent := func(u float64) float64 {
+		a := 0.98 / 49.98
+		return 0.02 + 49.98*(a-(1-2*u)*math.Abs(math.Floor(0.5-u)+a))
+	}
+
+	switch problem.Name {
+	case "ZDT1", "ZDT2", "ZDT3", "ZDT6":
+		fillVec(x[1:], 0)
+	case "ZDT4":
+		fillVec(x[1:], 0.5)
+	case "ZDT5":
+		fillVec(x[30:], 1)
+	case "DTLZ1", "DTLZ2", "DTLZ3", "DTLZ4", "DTLZ5":
+		fillVec(x[position:], 0.5)
+	case "DTLZ6", "DTLZ7":
+		fillVec(x[position:], 0)
+	case "WFG8":
+		for i := k; i < len(x); i++ {
+			x[i] = math.Pow(0.35, 1/bParamExponent(wfgMean(x[:i], 0)))
+		}
+	case "WFG9":
+		x[len(x)-1] = 0.35
+		for i := len(x) - 2; i >= k; i-- {
+			x[i] = math.Pow(0.35, 1/bParamExponent(wfgMean(x[i+1:], 0)))
+		}
+	default:
+		fillVec(x[k:], 0.35)
+	}
+
+	return x
+}
+
+func fillVec(x []float64, v float64) {
+	for i := range x {
+		x[i] = v
+	}
+}
+
+// frontDominates reports whether a point of the front is better than f by
+// more than rounding errors.
+func frontDominates(front []*ParetoSolution, f []float64) bool {
+	for _, s := range front {
+		shifted := make([]float64, len(f))
+		for j := range f {
+			shifted[j] = s.ObjectiveValues[j] + 1e-9
+		}
+
+		if dominates(shifted, f) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func allMultiObjectiveProblems(objectives int) []MultiObjectiveProblem {
+	problems := append(DTLZProblems(objectives), WFGProblems(objectives)...)
+	if objectives == 2 {
+		problems = append(ZDTProblems(), problems...)
+	}
+
+	return problems
+}
+
+func TestMultiObjectiveBenchmarkValues(t *testing.T) {
+	zeros := make([]float64, 80)
+	ones := make([]float64, 80)
+	fillVec(ones, 1)
+
+	tests := []struct {
+		name     string
+		got      []float64
+		expected []float64
+	}{
+		{"ZDT1", ZDT1([]float64{0.25, 0, 0}), []float64{0.25, 0.5}},
+		{"ZDT1 with g = 10", ZDT1([]float64{0, 1, 1}), []float64{0, 10}},
+		{"ZDT2", ZDT2([]float64{0.5, 0, 0}), []float64{0.5, 0.75}},
+		{"ZDT3", ZDT3([]float64{0.25, 0, 0}), []float64{0.25, 0.5 - 0.25*math.Sin(2.5*math.Pi)}},
+		{"ZDT4", ZDT4([]float64{0.25, 0.5, 0.5}), []float64{0.25, 0.5}},
+		{"ZDT5 zeros", ZDT5(zeros), []float64{1, 20}},
+		{"ZDT5 ones", ZDT5(ones), []float64{31, 10.0 / 31}},
+		{"ZDT6", ZDT6([]float64{0, 0, 0}), []float64{1, 0}},
+		{"DTLZ1", DTLZ1(3)([]float64{0.5, 0.5, 0.5, 0.5}), []float64{0.125, 0.125, 0.25}},
+		{"DTLZ2", DTLZ2(3)([]float64{0, 0, 0.5}), []float64{1, 0, 0}},
+		{"DTLZ2 with g = 0.25", DTLZ2(2)([]float64{1, 0}), []float64{1.25 * math.Cos(math.Pi/2), 1.25}},
+		{"DTLZ7", DTLZ7(2)([]float64{0, 0}), []float64{0, 4}},
+		{"WFG4 corner", WFG4(2)([]float64{0.35, 0.35, 0.35, 0.35}), []float64{0, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.expected {
+				if math.Abs(tt.got[i]-tt.expected[i]) > 1e-9 {
+					t.Fatalf("Got %v, expected %v", tt.got, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestMultiObjectiveBenchmarkFronts(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, objectives := range []int{2, 3} {
+		for _, problem := range allMultiObjectiveProblems(objectives) {
+			t.Run(problem.Name, func(t *testing.T) {
+				front := problem.TrueFront(1000)
+				if len(front) < 10 {
+					t.Fatalf("True front has only %d points", len(front))
+				}
+
+				ideal, nadir := IdealPoint(front), NadirPoint(front)
+				normalizedFront := NormalizeFront(front, ideal, nadir)
+
+				// Positions with optimal distance variables lie on the front,
+				// unless a disconnected front dominates them
+				var optimal []*ParetoSolution
+				for i := 0; i < 50; i++ {
+					f := problem.Func(paretoOptimalPosition(problem, rng))
+					if len(f) != objectives {
+						t.Fatalf("Expected %d objectives, got %d", objectives, len(f))
+					}
+
+					if !frontDominates(front, f) {
+						optimal = append(optimal, &ParetoSolution{ObjectiveValues: f})
+					}
+				}
+
+				if len(optimal) < 10 {
+					t.Fatalf("Only %d of 50 Pareto-optimal solutions are non-dominated", len(optimal))
+				}
+
+				if gd := CalculateGD(NormalizeFront(optimal, ideal, nadir), normalizedFront); gd > 0.05 {
+					t.Errorf("Pareto-optimal solutions have GD %.4f to the true front", gd)
+				}
+
+				// Random positions do not beat the front
+				var random []*ParetoSolution
+				for i := 0; i < 200; i++ {
+					x := make([]float64, problem.ProblemSize)
+					for j := range x {
+						x[j] = rng.Float64()
+					}
+
+					random = append(random, &ParetoSolution{ObjectiveValues: problem.Func(x)})
+				}
+
+				normalizedRandom := NormalizeFront(random, ideal, nadir)
+				for _, r := range normalizedRandom {
+					if eps := CalculateAdditiveEpsilon(normalizedFront, []*ParetoSolution{r}); eps > 0.05 {
+						t.Fatalf("Random solution %v lies %.4f beyond the true front", r.ObjectiveValues, eps)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestDTLZScalesObjectives(t *testing.T) {
+	for _, problem := range DTLZProblems(5) {
+		if problem.ProblemSize < 5 || len(problem.Func(make([]float64, problem.ProblemSize))) != 5 {
+			t.Errorf("%s does not have 5 objectives", problem.Name)
+		}
+	}
+
+	front := DTLZProblems(5)[1].TrueFront(500)
+	for _, s := range front {
+		if math.Abs(norm(s.ObjectiveValues)-1) > 1e-12 {
+			t.Fatalf("DTLZ2 front point %v not on the unit sphere", s.ObjectiveValues)
+		}
+	}
+}
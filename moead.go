@@ -0,0 +1,318 @@
+// Package mayfly - Decomposition (MOEA/D)
+//
+// Implements the "moead" mode of OptimizeMulti (Zhang & Li, 2007). The
+// problem is decomposed into one scalar subproblem per weight vector (the
+// Das-Dennis reference points). Male i and female i belong to subproblem i
+// and are compared by its scalarizing function:
+//   - "tchebycheff": max_j w_j·|f_j - z_j| (default)
+//   - "weightedsum": Σ w_j·(f_j - z_j)
+//   - "pbi": d1 + θ·d2, the distance along and the distance from the weight
+//     direction (penalty-based boundary intersection)
+//
+// where z is the best value of every objective found so far. With
+// constraints, the solution with the smaller violation is better, and the
+// scalarizing function decides between equal violations.
+//
+// Movement uses the subproblem instead of Pareto dominance: a female is
+// attracted to her male if he is better for their subproblem, and a male is
+// attracted to the best personal best of his neighborhood (the
+// NeighborhoodSize subproblems with the closest weights) if it beats him.
+// Mating is restricted to the neighborhood with probability
+// NeighborhoodProbability, and every offspring replaces at most
+// MaxReplacements worse solutions of the subproblems it was bred for.
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Scalarizing function names accepted by Config.Scalarization.
+const (
+	ScalarizationTchebycheff = "tchebycheff"
+	ScalarizationWeightedSum = "weightedsum"
+	ScalarizationPBI         = "pbi"
+)
+
+// Neighbourhood settings for an unset NeighborhoodSize or MaxReplacements,
+// as in MOEA/D-DE (Li & Zhang, 2009): 20 neighbours, of which a child
+// replaces at most 2.
+const (
+	defaultNeighborhoodSize = 20
+	defaultMaxReplacements  = 2
+)
+
+// decomposition holds the subproblems of a MOEA/D run.
+type decomposition struct {
+	weights         [][]float64
+	neighbors       [][]int // Closest subproblems, including the subproblem itself
+	ideal           []float64
+	method          string
+	theta           float64
+	probability     float64
+	maxReplacements int
+}
+
+// newDecomposition creates one subproblem per weight vector.
+func newDecomposition(config *Config, weights [][]float64) *decomposition {
+	d := &decomposition{
+		weights:         weights,
+		method:          config.Scalarization,
+		theta:           config.PBITheta,
+		probability:     config.NeighborhoodProbability,
+		maxReplacements: config.MaxReplacements,
+	}
+
+	if d.maxReplacements == 0 {
+		d.maxReplacements = defaultMaxReplacements
+	}
+
+	size := config.NeighborhoodSize
+	if size == 0 {
+		size = defaultNeighborhoodSize
+	}
+
+	if size > len(weights) {
+		size = len(weights)
+	}
+
+	d.neighbors = make([][]int, len(weights))
+	for i, w := range weights {
+		order := make([]int, len(weights))
+		for j := range order {
+			order[j] = j
+		}
+
+		sort.SliceStable(order, func(a, b int) bool {
+			return euclideanDistance(w, weights[order[a]]) < euclideanDistance(w, weights[order[b]])
+		})
+
+		d.neighbors[i] = order[:size]
+	}
+
+	return d
+}
+
+// updateIdeal lowers the ideal point to f where f is better.
+func (d *decomposition) updateIdeal(f []float64) {
+	if d.ideal == nil {
+		d.ideal = append([]float64(nil), f...)
+		return
+	}
+
+	for j, v := range f {
+		d.ideal[j] = math.Min(d.ideal[j], v)
+	}
+}
+
+// value returns the scalarized value of objective vector f for subproblem i.
+func (d *decomposition) value(f []float64, i int) float64 {
+	return scalarize(d.method, f, d.weights[i], d.ideal, d.theta)
+}
+
+// better reports whether solution a is better than b for subproblem i.
+func (d *decomposition) better(a, b *ParetoSolution, i int) bool {
+	if a.ConstraintViolation != b.ConstraintViolation {
+		return a.ConstraintViolation < b.ConstraintViolation
+	}
+
+	return d.value(a.ObjectiveValues, i) < d.value(b.ObjectiveValues, i)
+}
+
+// pool returns the subproblems available for mating and replacement around
+// subproblem i: its neighborhood, or all subproblems.
+func (d *decomposition) pool(i int, rng *rand.Rand) []int {
+	if rng.Float64() < d.probability {
+		return d.neighbors[i]
+	}
+
+	all := make([]int, len(d.weights))
+	for j := range all {
+		all[j] = j
+	}
+
+	return all
+}
+
+// scalarize returns the value of f for weight vector w and ideal point z
+// under the given scalarizing function.
+func scalarize(method string, f, w, z []float64, theta float64) float64 {
+	switch method {
+	case ScalarizationWeightedSum:
+		sum := 0.0
+		for j := range f {
+			sum += w[j] * (f[j] - z[j])
+		}
+
+		return sum
+
+	case ScalarizationPBI:
+		length := norm(w)
+
+		d1 := 0.0
+		for j := range f {
+			d1 += (f[j] - z[j]) * w[j] / length
+		}
+
+		d2 := 0.0
+		for j := range f {
+			d := f[j] - z[j] - d1*w[j]/length
+			d2 += d * d
+		}
+
+		return math.Abs(d1) + theta*math.Sqrt(d2)
+
+	default:
+		// Zero weights would ignore an objective entirely
+		worst := 0.0
+		for j := range f {
+			worst = math.Max(worst, math.Max(w[j], 1e-6)*math.Abs(f[j]-z[j]))
+		}
+
+		return worst
+	}
+}
+
+// iterateDecomposition performs main loop iteration it in MOEA/D mode.
+func (r *multiRun) iterateDecomposition(it int) {
+	s, config := r.state, r.state.Config
+	s.Iteration = it
+
+	r.moveDecomposed()
+	r.breedDecomposed()
+
+	r.updateArchive(s.Males)
+	r.updateArchive(s.Females)
+	r.updateArchive(s.Offspring)
+	r.forget(s.Offspring)
+
+	// Update parameters
+	s.G *= config.GDamp
+	s.Dance *= config.DanceDamp
+	s.FL *= config.FLDamp
+}
+
+// moveDecomposed moves females and males, comparing them by the
+// scalarizing function of their subproblem.
+func (r *multiRun) moveDecomposed() {
+	s, config, d := r.state, r.state.Config, r.decomposition
+
+	for i, female := range s.Females {
+		e := unifrndVec(-1, 1, config.ProblemSize, s.Rand)
+
+		var male *Mayfly
+		if d.better(r.current(s.Males[i]), r.current(female), i) {
+			male = s.Males[i]
+		}
+
+		r.femaleVelocity(female, male, e)
+		r.move(female)
+		d.updateIdeal(r.objectives[female])
+	}
+
+	for i, male := range s.Males {
+		e := unifrndVec(-1, 1, config.ProblemSize, s.Rand)
+
+		leader := s.Males[d.neighbors[i][0]]
+		for _, j := range d.neighbors[i][1:] {
+			if d.better(r.best(s.Males[j]), r.best(leader), i) {
+				leader = s.Males[j]
+			}
+		}
+
+		var target []float64
+		if d.better(r.best(leader), r.current(male), i) {
+			target = leader.Best.Position
+		}
+
+		r.maleVelocity(male, target, e)
+		r.move(male)
+		d.updateIdeal(r.objectives[male])
+
+		if !d.better(r.best(male), r.current(male), i) {
+			copy(male.Best.Position, male.Position)
+			r.bestObjectives[male] = r.objectives[male]
+			r.bestViolations[male] = r.violations[male]
+		}
+	}
+}
+
+// breedDecomposed mates and mutates mayflies of a random subproblem's pool.
+// The first child of a pair competes with the males of the pool, the second
+// with the females; mutants compete with the males.
+func (r *multiRun) breedDecomposed() {
+	s, p, config, d := r.state, r.pipeline, r.state.Config, r.decomposition
+	s.Offspring = make([]*Mayfly, 0, config.NC+config.NM)
+
+	for k := 0; k < config.NC/2; k++ {
+		pool := d.pool(s.Rand.Intn(len(d.weights)), s.Rand)
+		male := s.Males[pool[s.Rand.Intn(len(pool))]]
+		female := s.Females[pool[s.Rand.Intn(len(pool))]]
+
+		pos1, pos2 := p.Crossover.Cross(s, male.Position, female.Position)
+		sigma := inheritedSigma(male, female)
+
+		off1, off2 := r.newOffspring(pos1, sigma), r.newOffspring(pos2, sigma)
+		r.replaceInPool(s.Males, off1, pool)
+		r.replaceInPool(s.Females, off2, pool)
+
+		s.Offspring = append(s.Offspring, off1, off2)
+	}
+
+	for k := 0; k < config.NM; k++ {
+		pool := d.pool(s.Rand.Intn(len(d.weights)), s.Rand)
+		parent := s.Males[pool[s.Rand.Intn(len(pool))]]
+
+		var off *Mayfly
+		if adaptive, ok := p.Mutation.(StepSizeMutationOperator); ok {
+			position, sigma := adaptive.MutateWithStepSize(s, parent.Position, parent.Sigma)
+			off = r.newOffspring(position, sigma)
+		} else {
+			off = r.newOffspring(p.Mutation.Mutate(s, parent.Position), 0)
+		}
+
+		r.replaceInPool(s.Males, off, pool)
+		s.Offspring = append(s.Offspring, off)
+	}
+}
+
+// replaceInPool copies offspring into at most maxReplacements members of
+// population whose subproblems in pool it solves better.
+func (r *multiRun) replaceInPool(population []*Mayfly, off *Mayfly, pool []int) {
+	s, d := r.state, r.decomposition
+	objectives, violation := r.objectives[off], r.violations[off]
+	d.updateIdeal(objectives)
+
+	replaced := 0
+
+	for _, k := range s.Rand.Perm(len(pool)) {
+		if replaced == d.maxReplacements {
+			break
+		}
+
+		j := pool[k]
+		target := population[j]
+
+		if !d.better(r.current(off), r.current(target), j) {
+			continue
+		}
+
+		copy(target.Position, off.Position)
+		for i := range target.Velocity {
+			target.Velocity[i] = 0
+		}
+
+		target.Sigma = off.Sigma
+		r.objectives[target] = objectives
+		r.violations[target] = violation
+
+		if !d.better(r.best(target), r.current(off), j) {
+			copy(target.Best.Position, off.Position)
+			r.bestObjectives[target] = objectives
+			r.bestViolations[target] = violation
+		}
+
+		replaced++
+	}
+}
@@ -0,0 +1,127 @@
+package mayfly
+
+import (
+	"math"
+	"testing"
+)
+
+// =============================================================================
+// Tests for moead.go - Decomposition (MOEA/D)
+// =============================================================================
+
+func TestScalarize(t *testing.T) {
+	f := []float64{3, 1}
+	w := []float64{0.5, 0.5}
+	z := []float64{1, 0}
+
+	tests := []struct {
+		method   string
+		expected float64
+	}{
+		{ScalarizationTchebycheff, 1},
+		{"", 1},
+		{ScalarizationWeightedSum, 1.5},
+		// d1 = 3/sqrt(2) along w, d2 = 1/sqrt(2) from it
+		{ScalarizationPBI, 3/math.Sqrt2 + 5/math.Sqrt2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := scalarize(tt.method, f, w, z, 5); math.Abs(got-tt.expected) > 1e-12 {
+				t.Errorf("Got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecompositionNeighbors(t *testing.T) {
+	config := NewDefaultConfig()
+	config.NeighborhoodSize = 3
+
+	d := newDecomposition(config, DasDennisPoints(2, 10))
+
+	for i, neighbors := range d.neighbors {
+		if len(neighbors) != 3 || neighbors[0] != i {
+			t.Fatalf("Subproblem %d has neighborhood %v", i, neighbors)
+		}
+
+		for _, j := range neighbors {
+			if j < i-2 || j > i+2 {
+				t.Fatalf("Subproblem %d has distant neighbor %d", i, j)
+			}
+		}
+	}
+
+	if d.probability != 0.9 || d.theta != 5 || d.maxReplacements != defaultMaxReplacements {
+		t.Error("Expected the default MOEA/D parameters")
+	}
+
+	// 0 is a valid setting of both: global mating and pure weighted distance
+	config.NeighborhoodProbability = 0
+	config.PBITheta = 0
+
+	if d := newDecomposition(config, DasDennisPoints(2, 10)); d.probability != 0 || d.theta != 0 {
+		t.Errorf("Explicit zeros replaced: probability %v, theta %v", d.probability, d.theta)
+	}
+}
+
+func TestOptimizeMultiMOEAD(t *testing.T) {
+	for _, method := range []string{ScalarizationTchebycheff, ScalarizationPBI} {
+		t.Run(method, func(t *testing.T) {
+			config := newMultiTestConfig(12, 5)
+			config.MultiObjectiveMode = MultiObjectiveMOEAD
+			config.Scalarization = method
+			config.ReferenceDivisions = 12
+
+			calls := 0
+			objective := func(x []float64) []float64 {
+				calls++
+				return DTLZ2(3)(x)
+			}
+
+			result, err := OptimizeMulti(config, objective)
+			if err != nil {
+				t.Fatalf("OptimizeMulti failed: %v", err)
+			}
+
+			if result.FuncEvalCount != calls {
+				t.Errorf("FuncEvalCount = %d, objective called %d times", result.FuncEvalCount, calls)
+			}
+
+			if config.NPop != NewDefaultConfig().NPop {
+				t.Error("MOEA/D mode must not modify the caller's config")
+			}
+
+			trueFront := DTLZProblems(3)[1].TrueFront(1000)
+			if igd := CalculateIGD(result.Front, trueFront); igd > 0.15 {
+				t.Errorf("IGD %.4f to the true DTLZ2 front is too large", igd)
+			}
+		})
+	}
+}
+
+func TestOptimizeMultiMOEADReproducible(t *testing.T) {
+	run := func() []*ParetoSolution {
+		config := newMultiTestConfig(5, 6)
+		config.MultiObjectiveMode = MultiObjectiveMOEAD
+		config.MaxIterations = 30
+
+		result, err := OptimizeMulti(config, ZDT1)
+		if err != nil {
+			t.Fatalf("OptimizeMulti failed: %v", err)
+		}
+
+		return result.Front
+	}
+
+	a, b := run(), run()
+	if len(a) != len(b) {
+		t.Fatalf("Front sizes differ: %d vs %d", len(a), len(b))
+	}
+
+	for i := range a {
+		if !equalObjectives(a[i].ObjectiveValues, b[i].ObjectiveValues) {
+			t.Fatalf("Seeded runs differ at %d", i)
+		}
+	}
+}
@@ -0,0 +1,642 @@
+// Package mayfly - Multi-Objective Mayfly Algorithm
+//
+// Implements OptimizeMulti, which minimizes a MultiObjectiveFunction and
+// returns an approximation of its Pareto front.
+//
+// Males and females are ranked by Pareto rank and crowding distance (the
+// NSGA-II crowded comparison). The ranking is encoded in Mayfly.Cost as
+//
+//	cost = (rank - 1) + 1/(1 + crowding)
+//
+// so that the configured parent selection, crossover and mutation operators
+// work unchanged. Movement follows the original Mayfly Algorithm with Pareto
+// dominance in place of cost comparisons:
+//   - a female is attracted to her male if he dominates her, otherwise she
+//     performs a random flight
+//   - a male dominated by his leader moves towards his personal best and the
+//     leader, all other males perform the nuptial dance; leaders are drawn
+//     from the archive by binary tournament on crowding distance (MOPSO)
+//   - the personal best is replaced if the new position dominates it, or
+//     with probability 0.5 if neither dominates the other
+//
+// Survivors are selected by the crowded comparison. Every evaluated solution
+// is offered to an external archive of at most ArchiveSize non-dominated
+// solutions; when the archive is full, the most crowded solution is removed.
+// The archive is returned as the Pareto front.
+//
+// Config.MultiObjectiveMode selects the selection scheme:
+//   - "nsga2": the crowded comparison described above (default)
+//   - "nsga3": ranking and archive truncation by reference-point niching
+//     instead of crowding distance, for many objectives (see nsga3.go)
+//   - "moead": decomposition into scalar subproblems (see moead.go)
+//
+// NSGA-III and MOEA/D use Das-Dennis reference points with
+// ReferenceDivisions divisions (default: as many points as NPop allows).
+//
+// Config.ConstraintFunc adds constraints. All comparisons then use Deb's
+// constrained domination (see constrainedDominates), so feasible solutions
+// beat infeasible ones and the archive holds the least violating solutions
+// until a feasible one is found.
+//
+// The variant stages (DESMA, OLCE, ...) rely on scalar costs and are not used.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// defaultMultiArchiveSize is used when Config.ArchiveSize is zero.
+const defaultMultiArchiveSize = 100
+
+// Multi-objective mode names accepted by Config.MultiObjectiveMode.
+const (
+	MultiObjectiveNSGA2 = "nsga2"
+	MultiObjectiveNSGA3 = "nsga3"
+	MultiObjectiveMOEAD = "moead"
+)
+
+// MultiResult holds the result of a multi-objective optimization.
+type MultiResult struct {
+	Front          []*ParetoSolution // Non-dominated solutions, sorted by the first objective; infeasible only if no feasible solution was found
+	FuncEvalCount  int
+	IterationCount int
+	Seed           int64 // Random seed used for reproducibility
+}
+
+// OptimizeMulti minimizes all objectives of objective simultaneously.
+// Problem definition, population sizes, movement coefficients and genetic
+// operators are taken from config; config.ObjectiveFunc is not used.
+// Config.ArchiveSize limits the size of the returned front (default 100).
+// Config.ConstraintFunc, if set, constrains the search; constraint values
+// c_j(x) >= 0 are feasible. In MOEA/D mode the number of males and females is the number of
+// reference points instead of NPop and NPopF.
+func OptimizeMulti(config *Config, objective MultiObjectiveFunction) (*MultiResult, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	if objective == nil {
+		return nil, fmt.Errorf("multi-objective function is required")
+	}
+
+	if option := mayflyOnlyOption(config); option != "" {
+		return nil, fmt.Errorf("OptimizeMulti does not support %s", option)
+	}
+
+	if err := validateSearchConfig(config); err != nil {
+		return nil, err
+	}
+
+	if restartsEnabled(config) || nichingEnabled(config) {
+		return nil, fmt.Errorf("OptimizeMulti does not support restarts or niching")
+	}
+
+	if config.ArchiveSize < 0 {
+		return nil, fmt.Errorf("ArchiveSize must be non-negative, got %d", config.ArchiveSize)
+	}
+
+	r := newMultiRun(config, objective)
+
+	for it := 0; it < config.MaxIterations; it++ {
+		if r.decomposition != nil {
+			r.iterateDecomposition(it)
+		} else {
+			r.iterate(it)
+		}
+	}
+
+	return r.result(), nil
+}
+
+// validateMultiObjectiveConfig checks the multi-objective mode and its parameters.
+func validateMultiObjectiveConfig(config *Config) error {
+	switch config.MultiObjectiveMode {
+	case "", MultiObjectiveNSGA2, MultiObjectiveNSGA3, MultiObjectiveMOEAD:
+	default:
+		return fmt.Errorf("unknown multi-objective mode '%s' (expected nsga2, nsga3 or moead)", config.MultiObjectiveMode)
+	}
+
+	switch config.Scalarization {
+	case "", ScalarizationTchebycheff, ScalarizationWeightedSum, ScalarizationPBI:
+	default:
+		return fmt.Errorf("unknown scalarization '%s' (expected tchebycheff, weightedsum or pbi)", config.Scalarization)
+	}
+
+	if config.ReferenceDivisions < 0 {
+		return fmt.Errorf("ReferenceDivisions must be non-negative, got %d", config.ReferenceDivisions)
+	}
+
+	if config.NeighborhoodSize < 0 {
+		return fmt.Errorf("NeighborhoodSize must be non-negative, got %d", config.NeighborhoodSize)
+	}
+
+	if config.NeighborhoodProbability < 0 || config.NeighborhoodProbability > 1 {
+		return fmt.Errorf("NeighborhoodProbability must be in [0, 1], got %v", config.NeighborhoodProbability)
+	}
+
+	if config.MaxReplacements < 0 {
+		return fmt.Errorf("MaxReplacements must be non-negative, got %d", config.MaxReplacements)
+	}
+
+	if config.PBITheta < 0 {
+		return fmt.Errorf("PBITheta must be non-negative, got %v", config.PBITheta)
+	}
+
+	return nil
+}
+
+// multiRun holds the state of one multi-objective run.
+type multiRun struct {
+	state       *State
+	pipeline    *Pipeline
+	objective   MultiObjectiveFunction
+	seed        int64
+	archiveSize int
+	archive     []*ParetoSolution
+
+	mode          string
+	references    [][]float64    // Das-Dennis points (NSGA-III and MOEA/D)
+	decomposition *decomposition // Subproblems (MOEA/D only)
+
+	objectives     map[*Mayfly][]float64 // Objective vector of the current position
+	bestObjectives map[*Mayfly][]float64 // Objective vector of the personal best
+	violations     map[*Mayfly]float64   // Constraint violation of the current position
+	bestViolations map[*Mayfly]float64   // Constraint violation of the personal best
+}
+
+// newMultiRun creates and evaluates the initial populations.
+func newMultiRun(config *Config, objective MultiObjectiveFunction) *multiRun {
+	rng, seed := prepareRun(config)
+
+	r := &multiRun{
+		state: &State{
+			Config: config,
+			Rand:   rng,
+			GlobalBest: Best{
+				Position: make([]float64, config.ProblemSize),
+				Cost:     math.Inf(1),
+			},
+			BestSolution: make([]float64, config.MaxIterations),
+			G:            config.G,
+			Dance:        config.Dance,
+			FL:           config.FL,
+		},
+		pipeline:       NewPipeline(config),
+		objective:      objective,
+		seed:           seed,
+		archiveSize:    config.ArchiveSize,
+		mode:           config.MultiObjectiveMode,
+		objectives:     make(map[*Mayfly][]float64),
+		bestObjectives: make(map[*Mayfly][]float64),
+		violations:     make(map[*Mayfly]float64),
+		bestViolations: make(map[*Mayfly]float64),
+	}
+
+	if r.archiveSize == 0 {
+		r.archiveSize = defaultMultiArchiveSize
+	}
+
+	if r.mode == "" {
+		r.mode = MultiObjectiveNSGA2
+	}
+
+	// The first male reveals the number of objectives
+	s := r.state
+	s.Males = r.newPopulation(1)
+
+	if r.mode != MultiObjectiveNSGA2 {
+		objectives := len(r.objectives[s.Males[0]])
+
+		divisions := config.ReferenceDivisions
+		if divisions == 0 {
+			divisions = dasDennisDivisions(objectives, config.NPop)
+		}
+
+		r.references = DasDennisPoints(objectives, divisions)
+	}
+
+	if r.mode == MultiObjectiveMOEAD {
+		// One male and one female per subproblem, on a private config copy
+		private := *config
+		private.NPop = len(r.references)
+		private.NPopF = len(r.references)
+		s.Config = &private
+
+		r.decomposition = newDecomposition(&private, r.references)
+	}
+
+	s.Males = append(s.Males, r.newPopulation(s.Config.NPop-1)...)
+	s.Females = r.newPopulation(s.Config.NPopF)
+
+	if r.decomposition != nil {
+		// Males and females stay in subproblem order
+		for _, m := range append(s.Males, s.Females...) {
+			r.decomposition.updateIdeal(r.objectives[m])
+		}
+	} else {
+		r.rank(s.Males)
+		r.rank(s.Females)
+	}
+
+	r.updateArchive(s.Males)
+	r.updateArchive(s.Females)
+
+	return r
+}
+
+// newPopulation creates n evaluated mayflies at uniformly random positions.
+func (r *multiRun) newPopulation(n int) []*Mayfly {
+	config := r.state.Config
+	population := make([]*Mayfly, n)
+
+	for i := range population {
+		m := newMayfly(config.ProblemSize)
+		m.Position = unifrndVec(config.LowerBound, config.UpperBound, config.ProblemSize, r.state.Rand)
+		r.evaluate(m)
+
+		copy(m.Best.Position, m.Position)
+		r.bestObjectives[m] = r.objectives[m]
+		r.bestViolations[m] = r.violations[m]
+		population[i] = m
+	}
+
+	return population
+}
+
+// evaluate computes and stores the objective vector and constraint
+// violation of m's position.
+func (r *multiRun) evaluate(m *Mayfly) {
+	s := r.state
+	sanitizeVec(m.Position, s.Config.LowerBound, s.Config.UpperBound, s.Rand)
+
+	values := r.objective(m.Position)
+	objectives := make([]float64, len(values))
+
+	for i, v := range values {
+		objectives[i] = sanitizeCost(v)
+	}
+
+	s.FuncEvals++
+	r.objectives[m] = objectives
+
+	if s.Config.ConstraintFunc != nil {
+		r.violations[m] = TotalViolation(s.Config.ConstraintFunc(m.Position))
+	}
+}
+
+// current returns the position of m as a solution for comparisons.
+func (r *multiRun) current(m *Mayfly) *ParetoSolution {
+	return &ParetoSolution{Position: m.Position, ObjectiveValues: r.objectives[m], ConstraintViolation: r.violations[m]}
+}
+
+// best returns the personal best of m as a solution for comparisons.
+func (r *multiRun) best(m *Mayfly) *ParetoSolution {
+	return &ParetoSolution{Position: m.Best.Position, ObjectiveValues: r.bestObjectives[m], ConstraintViolation: r.bestViolations[m]}
+}
+
+// rank sorts population by the crowded comparison, or by reference-point
+// niching in NSGA-III mode, and encodes the order in the costs.
+func (r *multiRun) rank(population []*Mayfly) {
+	solutions := make([]*ParetoSolution, len(population))
+	for i, m := range population {
+		solutions[i] = r.current(m)
+	}
+
+	if r.mode == MultiObjectiveNSGA3 {
+		// cost = (rank - 1) + position in the niching order of the front
+		for _, front := range referencePointOrder(solutions, r.references, r.state.Rand) {
+			for k, i := range front {
+				population[i].Cost = float64(solutions[i].Rank-1) + float64(k+1)/float64(len(front)+1)
+			}
+		}
+
+		sortMayflies(population)
+
+		return
+	}
+
+	for _, front := range fastNonDominatedSort(solutions) {
+		calculateCrowdingDistance(solutions, front)
+	}
+
+	for i, m := range population {
+		m.Cost = float64(solutions[i].Rank-1) + 1/(1+solutions[i].CrowdingDistance)
+	}
+
+	sortMayflies(population)
+}
+
+// leader selects an archived solution by binary tournament on crowding distance.
+func (r *multiRun) leader() *ParetoSolution {
+	a := r.archive[r.state.Rand.Intn(len(r.archive))]
+	b := r.archive[r.state.Rand.Intn(len(r.archive))]
+
+	if b.CrowdingDistance > a.CrowdingDistance {
+		return b
+	}
+
+	return a
+}
+
+// iterate performs main loop iteration it.
+func (r *multiRun) iterate(it int) {
+	s, config := r.state, r.state.Config
+	s.Iteration = it
+
+	r.moveFemales()
+	r.moveMales()
+
+	r.rank(s.Males)
+	r.rank(s.Females)
+
+	r.breed()
+
+	split := len(s.Offspring) / 2
+	males := append(s.Males, s.Offspring[:split]...)
+	females := append(s.Females, s.Offspring[split:]...)
+
+	r.updateArchive(males)
+	r.updateArchive(females)
+
+	// Crowded-comparison survivor selection
+	r.rank(males)
+	r.rank(females)
+	s.Males = males[:config.NPop]
+	s.Females = females[:config.NPopF]
+
+	r.forget(males[config.NPop:])
+	r.forget(females[config.NPopF:])
+
+	// Crowding distances of the survivors
+	r.rank(s.Males)
+	r.rank(s.Females)
+
+	// Update parameters
+	s.G *= config.GDamp
+	s.Dance *= config.DanceDamp
+	s.FL *= config.FLDamp
+}
+
+// moveFemales moves every female towards her male if he dominates her and
+// performs a random flight otherwise.
+func (r *multiRun) moveFemales() {
+	s, config := r.state, r.state.Config
+
+	for i, female := range s.Females {
+		e := unifrndVec(-1, 1, config.ProblemSize, s.Rand)
+
+		var male *Mayfly
+		if i < len(s.Males) && solutionDominates(r.current(s.Males[i]), r.current(female)) {
+			male = s.Males[i]
+		}
+
+		r.femaleVelocity(female, male, e)
+		r.move(female)
+	}
+}
+
+// femaleVelocity updates the velocity of female for attraction to male, or
+// for a random flight with the random vector e if male is nil.
+func (r *multiRun) femaleVelocity(female, male *Mayfly, e []float64) {
+	s, config := r.state, r.state.Config
+
+	if male == nil {
+		for j := 0; j < config.ProblemSize; j++ {
+			female.Velocity[j] = s.G*female.Velocity[j] + s.FL*e[j]
+		}
+
+		return
+	}
+
+	for j := 0; j < config.ProblemSize; j++ {
+		rmf := male.Position[j] - female.Position[j]
+		female.Velocity[j] = s.G*female.Velocity[j] +
+			config.A3*math.Exp(-config.Beta*rmf*rmf)*(male.Position[j]-female.Position[j])
+	}
+}
+
+// moveMales moves every male dominated by his leader towards his personal
+// best and the leader; the others perform the nuptial dance.
+func (r *multiRun) moveMales() {
+	s, config := r.state, r.state.Config
+
+	for _, male := range s.Males {
+		e := unifrndVec(-1, 1, config.ProblemSize, s.Rand)
+		leader := r.leader()
+
+		var target []float64
+		if solutionDominates(leader, r.current(male)) {
+			target = leader.Position
+		}
+
+		r.maleVelocity(male, target, e)
+		r.move(male)
+		r.updatePersonalBest(male)
+	}
+}
+
+// maleVelocity updates the velocity of male for attraction to his personal
+// best and the leader position, or for the nuptial dance with the random
+// vector e if leader is nil.
+func (r *multiRun) maleVelocity(male *Mayfly, leader []float64, e []float64) {
+	s, config := r.state, r.state.Config
+
+	if leader == nil {
+		for j := 0; j < config.ProblemSize; j++ {
+			male.Velocity[j] = s.G*male.Velocity[j] + s.Dance*e[j]
+		}
+
+		return
+	}
+
+	for j := 0; j < config.ProblemSize; j++ {
+		rpbest := male.Best.Position[j] - male.Position[j]
+		rgbest := leader[j] - male.Position[j]
+		male.Velocity[j] = s.G*male.Velocity[j] +
+			config.A1*math.Exp(-config.Beta*rpbest*rpbest)*(male.Best.Position[j]-male.Position[j]) +
+			config.A2*math.Exp(-config.Beta*rgbest*rgbest)*(leader[j]-male.Position[j])
+	}
+}
+
+// move clamps the velocity, moves the mayfly and evaluates its new position.
+func (r *multiRun) move(m *Mayfly) {
+	config := r.state.Config
+
+	maxVec(m.Velocity, config.VelMin)
+	minVec(m.Velocity, config.VelMax)
+
+	for j := 0; j < config.ProblemSize; j++ {
+		m.Position[j] += m.Velocity[j]
+	}
+
+	maxVec(m.Position, config.LowerBound)
+	minVec(m.Position, config.UpperBound)
+
+	r.evaluate(m)
+}
+
+// updatePersonalBest replaces the personal best of m if its position
+// dominates it, or with probability 0.5 if neither dominates the other.
+func (r *multiRun) updatePersonalBest(m *Mayfly) {
+	current, best := r.current(m), r.best(m)
+
+	switch {
+	case solutionDominates(best, current):
+		return
+	case !solutionDominates(current, best) && r.state.Rand.Float64() >= 0.5:
+		return
+	}
+
+	copy(m.Best.Position, m.Position)
+	r.bestObjectives[m] = r.objectives[m]
+	r.bestViolations[m] = r.violations[m]
+}
+
+// breed performs mating and mutation, leaving the evaluated offspring in s.Offspring.
+func (r *multiRun) breed() {
+	s, p, config := r.state, r.pipeline, r.state.Config
+	s.Offspring = make([]*Mayfly, 0, config.NC+config.NM)
+
+	var selection ParentSelector = RankSelection{}
+	if p.Selection != nil {
+		selection = p.Selection
+	}
+
+	var mutationParents MutationParentSelector = RandomMutationParent{}
+	if p.MutationParents != nil {
+		mutationParents = p.MutationParents
+	}
+
+	males, females := selection.SelectParents(s, config.NC/2)
+
+	for k := range males {
+		pos1, pos2 := p.Crossover.Cross(s, males[k].Position, females[k].Position)
+		sigma := inheritedSigma(males[k], females[k])
+
+		s.Offspring = append(s.Offspring, r.newOffspring(pos1, sigma), r.newOffspring(pos2, sigma))
+	}
+
+	// Mutation parent selectors compare the costs of the offspring
+	r.rank(s.Offspring)
+
+	for k := 0; k < config.NM; k++ {
+		parent := mutationParents.SelectMutationParent(s)
+
+		if adaptive, ok := p.Mutation.(StepSizeMutationOperator); ok {
+			position, sigma := adaptive.MutateWithStepSize(s, parent.Position, parent.Sigma)
+			s.Offspring = append(s.Offspring, r.newOffspring(position, sigma))
+		} else {
+			s.Offspring = append(s.Offspring, r.newOffspring(p.Mutation.Mutate(s, parent.Position), 0))
+		}
+	}
+}
+
+// newOffspring returns an evaluated mayfly at position.
+func (r *multiRun) newOffspring(position []float64, sigma float64) *Mayfly {
+	off := newMayfly(r.state.Config.ProblemSize)
+	copy(off.Position, position)
+	off.Sigma = sigma
+
+	r.evaluate(off)
+
+	copy(off.Best.Position, off.Position)
+	r.bestObjectives[off] = r.objectives[off]
+	r.bestViolations[off] = r.violations[off]
+
+	return off
+}
+
+// forget drops the objective vectors of discarded mayflies.
+func (r *multiRun) forget(discarded []*Mayfly) {
+	for _, m := range discarded {
+		delete(r.objectives, m)
+		delete(r.bestObjectives, m)
+		delete(r.violations, m)
+		delete(r.bestViolations, m)
+	}
+}
+
+// updateArchive offers the current positions of candidates to the archive.
+func (r *multiRun) updateArchive(candidates []*Mayfly) {
+	for _, m := range candidates {
+		// Archive a copy, the mayfly moves on
+		current := r.current(m)
+		r.archive, _ = insertNonDominated(r.archive, &ParetoSolution{
+			Position:            append([]float64(nil), current.Position...),
+			ObjectiveValues:     append([]float64(nil), current.ObjectiveValues...),
+			ConstraintViolation: current.ConstraintViolation,
+			Rank:                1,
+		})
+	}
+
+	if r.references != nil {
+		r.archive = truncateByReferencePoints(r.archive, r.references, r.archiveSize, r.state.Rand)
+	} else {
+		r.archive = truncateByCrowding(r.archive, r.archiveSize)
+	}
+}
+
+// equalObjectives reports whether a and b are the same objective vector.
+func equalObjectives(a, b []float64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return len(a) == len(b)
+}
+
+// truncateByCrowding removes the most crowded solution of the non-dominated
+// set front until at most size remain. The crowding distances of the
+// remaining solutions are up to date afterwards.
+func truncateByCrowding(front []*ParetoSolution, size int) []*ParetoSolution {
+	indices := make([]int, len(front))
+	for i := range indices {
+		indices[i] = i
+	}
+
+	calculateCrowdingDistance(front, indices)
+
+	for len(front) > size {
+		worst := 0
+		for i, solution := range front {
+			if solution.CrowdingDistance < front[worst].CrowdingDistance {
+				worst = i
+			}
+		}
+
+		front = append(front[:worst], front[worst+1:]...)
+		indices = indices[:len(front)]
+		calculateCrowdingDistance(front, indices)
+	}
+
+	return front
+}
+
+// result returns the archive as the Pareto front of the run.
+func (r *multiRun) result() *MultiResult {
+	front := make([]*ParetoSolution, len(r.archive))
+	for i, solution := range r.archive {
+		front[i] = &ParetoSolution{
+			Position:            append([]float64(nil), solution.Position...),
+			ObjectiveValues:     append([]float64(nil), solution.ObjectiveValues...),
+			ConstraintViolation: solution.ConstraintViolation,
+			Rank:                1,
+			CrowdingDistance:    solution.CrowdingDistance,
+		}
+	}
+
+	sort.SliceStable(front, func(i, j int) bool {
+		return front[i].ObjectiveValues[0] < front[j].ObjectiveValues[0]
+	})
+
+	return &MultiResult{
+		Front:          front,
+		FuncEvalCount:  r.state.FuncEvals,
+		IterationCount: r.state.Config.MaxIterations,
+		Seed:           r.seed,
+	}
+}
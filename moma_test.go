@@ -0,0 +1,254 @@
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Tests for moma.go - Multi-objective Mayfly Algorithm
+// =============================================================================
+
+func newMultiTestConfig(problemSize int, seed int64) *Config {
+	config := NewDefaultConfig()
+	config.ProblemSize = problemSize
+	config.LowerBound = 0
+	config.UpperBound = 1
+	config.MaxIterations = 300
+	config.Rand = rand.New(rand.NewSource(seed))
+
+	return config
+}
+
+func TestOptimizeMultiZDT1(t *testing.T) {
+	config := newMultiTestConfig(10, 1)
+
+	calls := 0
+	objective := func(x []float64) []float64 {
+		calls++
+		return ZDT1(x)
+	}
+
+	result, err := OptimizeMulti(config, objective)
+	if err != nil {
+		t.Fatalf("OptimizeMulti failed: %v", err)
+	}
+
+	if result.FuncEvalCount != calls {
+		t.Errorf("FuncEvalCount = %d, objective called %d times", result.FuncEvalCount, calls)
+	}
+
+	if len(result.Front) < 20 || len(result.Front) > defaultMultiArchiveSize {
+		t.Fatalf("Unexpected front size %d", len(result.Front))
+	}
+
+	for i, a := range result.Front {
+		if i > 0 && a.ObjectiveValues[0] < result.Front[i-1].ObjectiveValues[0] {
+			t.Fatalf("Front not sorted by the first objective at %d", i)
+		}
+
+		if expected := ZDT1(a.Position); !equalObjectives(expected, a.ObjectiveValues) {
+			t.Fatalf("Objectives %v do not belong to position (expected %v)", a.ObjectiveValues, expected)
+		}
+
+		for _, b := range result.Front {
+			if dominates(b.ObjectiveValues, a.ObjectiveValues) {
+				t.Fatalf("Front contains dominated solution %v", a.ObjectiveValues)
+			}
+		}
+	}
+
+	var trueFront []*ParetoSolution
+	for i := 0; i <= 200; i++ {
+		f1 := float64(i) / 200
+		trueFront = append(trueFront, &ParetoSolution{ObjectiveValues: []float64{f1, 1 - math.Sqrt(f1)}})
+	}
+
+	if igd := CalculateIGD(result.Front, trueFront); igd > 0.05 {
+		t.Errorf("IGD %.4f to the true ZDT1 front is too large", igd)
+	}
+}
+
+func TestOptimizeMultiDTLZ2(t *testing.T) {
+	config := newMultiTestConfig(12, 2)
+	config.ArchiveSize = 50
+
+	result, err := OptimizeMulti(config, DTLZ2(3))
+	if err != nil {
+		t.Fatalf("OptimizeMulti failed: %v", err)
+	}
+
+	if len(result.Front) > 50 {
+		t.Errorf("Front size %d exceeds ArchiveSize", len(result.Front))
+	}
+
+	// The DTLZ2 front is the positive octant of the unit sphere
+	deviation := 0.0
+	for _, solution := range result.Front {
+		deviation += math.Abs(norm(solution.ObjectiveValues) - 1)
+	}
+
+	if mean := deviation / float64(len(result.Front)); mean > 0.15 {
+		t.Errorf("Mean distance %.4f to the DTLZ2 front is too large", mean)
+	}
+}
+
+func TestOptimizeMultiConstrained(t *testing.T) {
+	problem := CDTLZProblems(3)[2] // C2-DTLZ2
+
+	for _, mode := range []string{MultiObjectiveNSGA2, MultiObjectiveMOEAD} {
+		t.Run(mode, func(t *testing.T) {
+			config := newMultiTestConfig(problem.ProblemSize, 3)
+			config.MultiObjectiveMode = mode
+			config.ConstraintFunc = problem.Constraints
+
+			result, err := OptimizeMulti(config, problem.Func)
+			if err != nil {
+				t.Fatalf("OptimizeMulti failed: %v", err)
+			}
+
+			for _, s := range result.Front {
+				if !s.Feasible() || TotalViolation(problem.Constraints(s.Position)) != 0 {
+					t.Fatalf("Front contains infeasible solution %v", s.ObjectiveValues)
+				}
+			}
+
+			// Uniform weights cover the four disconnected regions unevenly
+			if igd := CalculateIGD(result.Front, problem.TrueFront(500)); igd > 0.25 {
+				t.Errorf("IGD %.4f to the true C2-DTLZ2 front is too large", igd)
+			}
+		})
+	}
+}
+
+// configOption enables the option named option of a config.
+type configOption struct {
+	option string
+	modify func(*Config)
+}
+
+// mayflyOnlyOptions enable each option that only Optimize implements.
+var mayflyOnlyOptions = []configOption{
+	{"UseDESMA", func(c *Config) { c.UseDESMA = true }},
+	{"UseOLCE", func(c *Config) { c.UseOLCE = true }},
+	{"UseGSASMA", func(c *Config) { c.UseGSASMA = true }},
+	{"UseEOBBMA", func(c *Config) { c.UseEOBBMA = true }},
+	{"UseMPMA", func(c *Config) { c.UseMPMA = true }},
+	{"UseAOBLMOA", func(c *Config) { c.UseAOBLMOA = true }},
+	{"UseSurrogate", func(c *Config) { c.UseSurrogate = true }},
+	{"LocalSearch", func(c *Config) { c.LocalSearch = LocalSearchNelderMead }},
+	{"Gradient", func(c *Config) { c.Gradient = func(x []float64) []float64 { return x } }},
+	{"AdaptiveParameters", func(c *Config) { c.AdaptiveParameters = true }},
+}
+
+func TestOptimizeMultiUnsupportedOptions(t *testing.T) {
+	for _, tt := range mayflyOnlyOptions {
+		t.Run(tt.option, func(t *testing.T) {
+			config := newMultiTestConfig(5, 1)
+			tt.modify(config)
+
+			_, err := OptimizeMulti(config, ZDT1)
+			if err == nil || !strings.Contains(err.Error(), tt.option) {
+				t.Errorf("Expected an error naming %s, got %v", tt.option, err)
+			}
+		})
+	}
+}
+
+func TestOptimizeRejectsConstraints(t *testing.T) {
+	config := newTestConfig(Sphere, 5, 10, 30, 3)
+	config.ConstraintFunc = func(x []float64) []float64 { return []float64{1 - x[0]} }
+
+	if _, err := Optimize(config); err == nil {
+		t.Error("Optimize should reject ConstraintFunc")
+	}
+}
+
+func TestOptimizeMultiReproducible(t *testing.T) {
+	run := func() []*ParetoSolution {
+		config := newMultiTestConfig(5, 3)
+		config.MaxIterations = 30
+
+		result, err := OptimizeMulti(config, ZDT2)
+		if err != nil {
+			t.Fatalf("OptimizeMulti failed: %v", err)
+		}
+
+		return result.Front
+	}
+
+	a, b := run(), run()
+	if len(a) != len(b) {
+		t.Fatalf("Front sizes differ: %d vs %d", len(a), len(b))
+	}
+
+	for i := range a {
+		if !equalObjectives(a[i].ObjectiveValues, b[i].ObjectiveValues) {
+			t.Fatalf("Seeded runs differ at %d", i)
+		}
+	}
+}
+
+func TestInsertNonDominated(t *testing.T) {
+	var front []*ParetoSolution
+	for _, objectives := range [][]float64{{1, 3}, {2, 2}, {3, 1}, {2, 2}, {4, 4}, {1.5, 1.5}} {
+		front, _ = insertNonDominated(front, &ParetoSolution{ObjectiveValues: objectives})
+	}
+
+	// {2,2} is dominated by {1.5,1.5}, the duplicate and {4,4} are rejected
+	if len(front) != 3 {
+		t.Fatalf("Expected 3 non-dominated solutions, got %d", len(front))
+	}
+
+	front = truncateByCrowding(front, 2)
+	if len(front) != 2 {
+		t.Fatalf("Expected 2 solutions after truncation, got %d", len(front))
+	}
+
+	// The boundary solutions have infinite crowding distance and survive
+	for _, solution := range front {
+		if solution.ObjectiveValues[0] == 1.5 {
+			t.Error("The most crowded solution should be removed")
+		}
+	}
+}
+
+func TestOptimizeMultiValidation(t *testing.T) {
+	if _, err := OptimizeMulti(nil, ZDT1); err == nil {
+		t.Error("Expected error for nil config")
+	}
+
+	if _, err := OptimizeMulti(newMultiTestConfig(5, 1), nil); err == nil {
+		t.Error("Expected error for nil objective")
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"Invalid bounds", func(c *Config) { c.UpperBound = c.LowerBound }},
+		{"Restarts", func(c *Config) { c.RestartStrategy = RestartStrategyIPOP }},
+		{"Niching", func(c *Config) { c.NichingMethod = NichingClearing }},
+		{"Negative archive size", func(c *Config) { c.ArchiveSize = -1 }},
+		{"Unknown mode", func(c *Config) { c.MultiObjectiveMode = "spea2" }},
+		{"Unknown scalarization", func(c *Config) { c.Scalarization = "chebyshev" }},
+		{"Negative reference divisions", func(c *Config) { c.ReferenceDivisions = -1 }},
+		{"Negative neighborhood size", func(c *Config) { c.NeighborhoodSize = -1 }},
+		{"Neighborhood probability above 1", func(c *Config) { c.NeighborhoodProbability = 1.5 }},
+		{"Negative max replacements", func(c *Config) { c.MaxReplacements = -1 }},
+		{"Negative PBI theta", func(c *Config) { c.PBITheta = -1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newMultiTestConfig(5, 1)
+			tt.modify(config)
+
+			if _, err := OptimizeMulti(config, ZDT1); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
@@ -117,3 +117,68 @@ func calculateGravityCoefficient(gravityType string, iteration, maxIterations in
 		return 1.0 - t
 	}
 }
+
+// mpmaMover extends the standard movement with median-position guidance and
+// a non-linear gravity coefficient for the males.
+type mpmaMover struct {
+	standardMover
+}
+
+// MoveMales moves the males using the MPMA velocity update.
+func (m *mpmaMover) MoveMales(s *State) {
+	config := s.Config
+	males := s.Males
+
+	var medianPos []float64
+
+	if config.UseWeightedMedian {
+		// Create fitness weights (better fitness = higher weight)
+		weights := make([]float64, len(males))
+		maxCost := males[len(males)-1].Cost // Worst cost (sorted)
+		minCost := males[0].Cost            // Best cost
+
+		for i := range males {
+			// Normalize and invert (better solutions get higher weight)
+			if maxCost > minCost {
+				weights[i] = 1.0 - (males[i].Cost-minCost)/(maxCost-minCost)
+			} else {
+				weights[i] = 1.0 // All equal
+			}
+		}
+
+		medianPos = calculateWeightedMedianPosition(males, weights)
+	} else {
+		medianPos = calculateMedianPosition(males)
+	}
+
+	// Calculate non-linear gravity coefficient
+	mpmaG := calculateGravityCoefficient(config.GravityType, s.Iteration, config.MaxIterations)
+
+	for i := 0; i < config.NPop; i++ {
+		male := males[i]
+		e := unifrndVec(-1, 1, config.ProblemSize, s.Rand)
+
+		leader := s.socialBest(male.Position)
+
+		if male.Cost > leader.Cost {
+			// Modified velocity update with median position and non-linear gravity
+			c := s.coefficients(male, ruleFollow)
+			for j := 0; j < config.ProblemSize; j++ {
+				rpbest := male.Best.Position[j] - male.Position[j]
+				rgbest := leader.Position[j] - male.Position[j]
+				rmedian := medianPos[j] - male.Position[j]
+
+				male.Velocity[j] = mpmaG*male.Velocity[j] +
+					c.A1*math.Exp(-c.Beta*rpbest*rpbest)*(male.Best.Position[j]-male.Position[j]) +
+					c.A2*math.Exp(-c.Beta*rgbest*rgbest)*(leader.Position[j]-male.Position[j]) +
+					config.MedianWeight*math.Exp(-c.Beta*rmedian*rmedian)*(medianPos[j]-male.Position[j])
+			}
+		} else {
+			// Nuptial dance with MPMA gravity
+			nuptialDance(s, male, mpmaG, s.coefficients(male, ruleDance).Dance, e)
+		}
+
+		moveMayfly(s, male)
+		updatePersonalBest(s, male)
+	}
+}
@@ -9,14 +9,38 @@ import (
 // It takes a position vector and returns multiple objective values.
 type MultiObjectiveFunction func([]float64) []float64
 
+// ConstraintFunction returns the constraint values c_j(x) of a position.
+// A position is feasible if c_j(x) >= 0 for every j.
+type ConstraintFunction func([]float64) []float64
+
 // ParetoSolution represents a solution in the Pareto archive.
 type ParetoSolution struct {
-	Position           []float64
-	ObjectiveValues    []float64
-	DominatedSolutions []int
-	Rank               int
-	CrowdingDistance   float64
-	DominationCount    int
+	Position            []float64
+	ObjectiveValues     []float64
+	ConstraintViolation float64 // Total constraint violation; 0 if feasible
+	DominatedSolutions  []int   // Scratch space of the domination count sort
+	Rank                int
+	CrowdingDistance    float64
+	DominationCount     int // Scratch space of the domination count sort
+}
+
+// Feasible reports whether the solution violates no constraint.
+func (s *ParetoSolution) Feasible() bool {
+	return s.ConstraintViolation == 0
+}
+
+// TotalViolation returns the total violation Σ max(0, -c_j) of the
+// constraint values c, which is 0 for a feasible position. Invalid values
+// count as a very large violation.
+func TotalViolation(c []float64) float64 {
+	total := 0.0
+	for _, v := range c {
+		if !(v >= 0) {
+			total += sanitizeCost(-v)
+		}
+	}
+
+	return total
 }
 
 // For minimization: a[i] <= b[i] for all i, and a[j] < b[j] for at least one j.
@@ -42,17 +66,66 @@ func dominates(a, b []float64) bool {
 	return strictlyBetter
 }
 
-// Algorithm complexity: O(MN²) where M is number of objectives, N is population size.
+// constrainedDominates applies Deb's constrained-domination principle to
+// objective vectors a and b with total constraint violations va and vb: a
+// feasible solution dominates an infeasible one, of two infeasible solutions
+// the one with the smaller violation dominates, and feasible solutions are
+// compared by Pareto dominance.
+func constrainedDominates(a []float64, va float64, b []float64, vb float64) bool {
+	switch {
+	case va == 0 && vb == 0:
+		return dominates(a, b)
+	case va == 0:
+		return true
+	case vb == 0:
+		return false
+	default:
+		return va < vb
+	}
+}
+
+// solutionDominates reports whether a constrained-dominates b.
+func solutionDominates(a, b *ParetoSolution) bool {
+	return constrainedDominates(a.ObjectiveValues, a.ConstraintViolation, b.ObjectiveValues, b.ConstraintViolation)
+}
+
+// solutionWeaklyDominates reports whether a constrained-dominates b or
+// equals it in objectives and constraint violation.
+func solutionWeaklyDominates(a, b *ParetoSolution) bool {
+	return solutionDominates(a, b) ||
+		(a.ConstraintViolation == b.ConstraintViolation && equalObjectives(a.ObjectiveValues, b.ObjectiveValues))
+}
+
+// nonDominatedSortThreshold is the number of solutions from which
+// fastNonDominatedSort uses efficientNonDominatedSort instead of
+// dominationCountSort.
+const nonDominatedSortThreshold = 16
+
+// fastNonDominatedSort sorts solutions into non-dominated fronts by
+// constrained domination, sets their Rank (starting at 1) and returns the
+// indices of every front. Small sets are sorted by domination counts, large
+// sets by ENS-BS.
 func fastNonDominatedSort(solutions []*ParetoSolution) [][]int {
+	if len(solutions) < nonDominatedSortThreshold {
+		return dominationCountSort(solutions)
+	}
+
+	return efficientNonDominatedSort(solutions)
+}
+
+// dominationCountSort is the non-dominated sort of NSGA-II (Deb et al., 2002).
+// It fills DominationCount and DominatedSolutions of every solution.
+// Algorithm complexity: O(MN²) where M is number of objectives, N is population size.
+func dominationCountSort(solutions []*ParetoSolution) [][]int {
 	n := len(solutions)
 	if n == 0 {
 		return nil
 	}
 
-	// Initialize domination data
+	// Initialize domination data, reusing the slices of earlier sorts
 	for i := 0; i < n; i++ {
 		solutions[i].DominationCount = 0
-		solutions[i].DominatedSolutions = make([]int, 0)
+		solutions[i].DominatedSolutions = solutions[i].DominatedSolutions[:0]
 	}
 
 	// First front (non-dominated solutions)
@@ -65,10 +138,10 @@ func fastNonDominatedSort(solutions []*ParetoSolution) [][]int {
 				continue
 			}
 
-			if dominates(solutions[i].ObjectiveValues, solutions[j].ObjectiveValues) {
+			if solutionDominates(solutions[i], solutions[j]) {
 				// i dominates j
 				solutions[i].DominatedSolutions = append(solutions[i].DominatedSolutions, j)
-			} else if dominates(solutions[j].ObjectiveValues, solutions[i].ObjectiveValues) {
+			} else if solutionDominates(solutions[j], solutions[i]) {
 				// j dominates i
 				solutions[i].DominationCount++
 			}
@@ -115,6 +188,85 @@ func fastNonDominatedSort(solutions []*ParetoSolution) [][]int {
 	return fronts
 }
 
+// efficientNonDominatedSort is the Efficient Non-dominated Sort with binary
+// search, ENS-BS (Zhang et al., 2015). Solutions are visited in
+// lexicographic order of their objectives, feasible solutions first and
+// infeasible ones by increasing violation, so that no solution dominates an
+// earlier one; each is appended to the first front that has no member
+// dominating it, found by binary search over the fronts. With two objectives
+// only the last member of a front has to be compared.
+// Algorithm complexity: O(MN log N) in the best case, O(MN²) in the worst
+// case, with far fewer comparisons than dominationCountSort in practice.
+func efficientNonDominatedSort(solutions []*ParetoSolution) [][]int {
+	n := len(solutions)
+	if n == 0 {
+		return nil
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		sa, sb := solutions[order[a]], solutions[order[b]]
+		if sa.ConstraintViolation != sb.ConstraintViolation {
+			return sa.ConstraintViolation < sb.ConstraintViolation
+		}
+
+		fa, fb := sa.ObjectiveValues, sb.ObjectiveValues
+		for j := range fa {
+			if fa[j] != fb[j] {
+				return fa[j] < fb[j]
+			}
+		}
+
+		return false
+	})
+
+	biObjective := len(solutions[0].ObjectiveValues) == 2
+
+	// dominated reports whether a member of front dominates solution i.
+	// A front is either feasible or holds infeasible solutions of equal
+	// violation, so the two-objective shortcut holds for both.
+	dominated := func(front []int, i int) bool {
+		if biObjective {
+			return solutionDominates(solutions[front[len(front)-1]], solutions[i])
+		}
+
+		for k := len(front) - 1; k >= 0; k-- {
+			if solutionDominates(solutions[front[k]], solutions[i]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var fronts [][]int
+
+	for _, i := range order {
+		low, high := 0, len(fronts)
+		for low < high {
+			mid := (low + high) / 2
+			if dominated(fronts[mid], i) {
+				low = mid + 1
+			} else {
+				high = mid
+			}
+		}
+
+		if low == len(fronts) {
+			fronts = append(fronts, nil)
+		}
+
+		fronts[low] = append(fronts[low], i)
+		solutions[i].Rank = low + 1
+	}
+
+	return fronts
+}
+
 // calculateCrowdingDistance calculates the crowding distance for solutions in a front.
 // Crowding distance measures how close a solution is to its neighbors.
 // Higher values indicate more isolated solutions (better diversity).
@@ -200,105 +352,6 @@ func crowdingDistanceComparison(a, b *ParetoSolution) bool {
 	return a.CrowdingDistance > b.CrowdingDistance
 }
 
-// calculateHypervolume calculates the hypervolume indicator for a Pareto front.
-// The hypervolume is the volume of the objective space dominated by the front.
-// Higher values indicate better convergence and diversity.
-//
-// This is a simplified 2D implementation. For 3+ objectives, more sophisticated
-// algorithms like WFG or FPRAS should be used.
-//
-// Parameters:
-//   - solutions: Solutions in the Pareto front
-//   - referencePoint: Worst acceptable point (usually slightly worse than nadir point)
-//
-// Note: Currently only supports 2 objectives for simplicity.
-func calculateHypervolume(solutions []*ParetoSolution, referencePoint []float64) float64 {
-	if len(solutions) == 0 {
-		return 0
-	}
-
-	numObjectives := len(solutions[0].ObjectiveValues)
-	if numObjectives != 2 {
-		// For now, only 2D hypervolume is supported
-		// For higher dimensions, need more complex algorithms
-		return 0
-	}
-
-	// Sort solutions by first objective
-	sorted := make([]*ParetoSolution, len(solutions))
-	copy(sorted, solutions)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].ObjectiveValues[0] < sorted[j].ObjectiveValues[0]
-	})
-
-	hypervolume := 0.0
-	previousY := referencePoint[1]
-
-	for _, sol := range sorted {
-		width := referencePoint[0] - sol.ObjectiveValues[0]
-		height := previousY - sol.ObjectiveValues[1]
-
-		if width > 0 && height > 0 {
-			hypervolume += width * height
-		}
-
-		// Update for next iteration
-		if sol.ObjectiveValues[1] < previousY {
-			previousY = sol.ObjectiveValues[1]
-		}
-	}
-
-	return hypervolume
-}
-
-// calculateIGD calculates the Inverted Generational Distance (IGD) metric.
-// IGD measures both convergence and diversity by computing the average
-// distance from each point in the true Pareto front to the nearest
-// point in the obtained front.
-//
-// Lower values indicate better performance (closer to true Pareto front).
-//
-// Parameters:
-//   - obtainedFront: The Pareto front obtained by the algorithm
-//   - trueFront: The true/reference Pareto front (if known)
-//
-// Returns:
-//   - IGD value (lower is better)
-func calculateIGD(obtainedFront, trueFront []*ParetoSolution) float64 {
-	if len(trueFront) == 0 || len(obtainedFront) == 0 {
-		return math.Inf(1)
-	}
-
-	totalDistance := 0.0
-
-	// For each point in the true front
-	for _, truePoint := range trueFront {
-		// Find minimum distance to obtained front
-		minDistance := math.Inf(1)
-
-		for _, obtainedPoint := range obtainedFront {
-			// Calculate Euclidean distance in objective space
-			distance := 0.0
-
-			for i := 0; i < len(truePoint.ObjectiveValues); i++ {
-				diff := truePoint.ObjectiveValues[i] - obtainedPoint.ObjectiveValues[i]
-				distance += diff * diff
-			}
-
-			distance = math.Sqrt(distance)
-
-			if distance < minDistance {
-				minDistance = distance
-			}
-		}
-
-		totalDistance += minDistance
-	}
-
-	// Average distance
-	return totalDistance / float64(len(trueFront))
-}
-
 // selectByNSGA2 selects the best N solutions using NSGA-II selection.
 // This combines Pareto ranking and crowding distance.
 //
@@ -0,0 +1,199 @@
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for multiobjective.go - Non-dominated sorting
+// =============================================================================
+
+// randomSolutions returns n solutions with objectives in [0, 1), rounded to
+// the given number of levels to produce ties and duplicates (0: no rounding).
+func randomSolutions(rng *rand.Rand, n, objectives, levels int) []*ParetoSolution {
+	solutions := make([]*ParetoSolution, n)
+	for i := range solutions {
+		f := make([]float64, objectives)
+		for j := range f {
+			f[j] = rng.Float64()
+			if levels > 0 {
+				f[j] = math.Floor(f[j]*float64(levels)) / float64(levels)
+			}
+		}
+
+		solutions[i] = &ParetoSolution{ObjectiveValues: f}
+	}
+
+	return solutions
+}
+
+func TestEfficientNonDominatedSortMatchesDominationCount(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, objectives := range []int{2, 3, 5} {
+		for _, levels := range []int{0, 4} {
+			for _, n := range []int{1, 10, 300} {
+				solutions := randomSolutions(rng, n, objectives, levels)
+
+				fronts := dominationCountSort(solutions)
+				expected := make([]int, n)
+				for i, s := range solutions {
+					expected[i] = s.Rank
+				}
+
+				efficient := efficientNonDominatedSort(solutions)
+				if len(efficient) != len(fronts) {
+					t.Fatalf("M=%d levels=%d n=%d: %d fronts, expected %d", objectives, levels, n, len(efficient), len(fronts))
+				}
+
+				count := 0
+				for k, front := range efficient {
+					count += len(front)
+					for _, i := range front {
+						if solutions[i].Rank != k+1 || expected[i] != k+1 {
+							t.Fatalf("M=%d levels=%d n=%d: solution %d in front %d, expected rank %d", objectives, levels, n, i, k+1, expected[i])
+						}
+					}
+				}
+
+				if count != n {
+					t.Fatalf("Fronts hold %d of %d solutions", count, n)
+				}
+			}
+		}
+	}
+}
+
+func TestFastNonDominatedSortLarge(t *testing.T) {
+	solutions := randomSolutions(rand.New(rand.NewSource(2)), 2*nonDominatedSortThreshold, 3, 0)
+
+	fronts := fastNonDominatedSort(solutions)
+	for k, front := range fronts {
+		for _, i := range front {
+			for _, j := range front {
+				if dominates(solutions[i].ObjectiveValues, solutions[j].ObjectiveValues) {
+					t.Fatalf("Front %d contains dominated solution %d", k+1, j)
+				}
+			}
+
+			if k > 0 && !dominatedByFront(solutions, fronts[k-1], i) {
+				t.Fatalf("Solution %d in front %d is not dominated by front %d", i, k+1, k)
+			}
+		}
+	}
+}
+
+func dominatedByFront(solutions []*ParetoSolution, front []int, i int) bool {
+	for _, j := range front {
+		if dominates(solutions[j].ObjectiveValues, solutions[i].ObjectiveValues) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func BenchmarkNonDominatedSort(b *testing.B) {
+	sorts := []struct {
+		name string
+		sort func([]*ParetoSolution) [][]int
+	}{
+		{"DominationCount", dominationCountSort},
+		{"ENS-BS", efficientNonDominatedSort},
+	}
+
+	for _, objectives := range []int{2, 3, 5} {
+		for _, n := range []int{100, 1000, 5000} {
+			solutions := randomSolutions(rand.New(rand.NewSource(1)), n, objectives, 0)
+
+			for _, s := range sorts {
+				b.Run(fmt.Sprintf("%s/M=%d/N=%d", s.name, objectives, n), func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						s.sort(solutions)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestTotalViolation(t *testing.T) {
+	if v := TotalViolation([]float64{1, 0, 2}); v != 0 {
+		t.Errorf("Feasible constraints have violation %g", v)
+	}
+
+	if v := TotalViolation([]float64{-1, 3, -0.5}); v != 1.5 {
+		t.Errorf("Expected violation 1.5, got %g", v)
+	}
+
+	if v := TotalViolation([]float64{math.NaN()}); v <= 0 {
+		t.Errorf("NaN constraint should be violated, got %g", v)
+	}
+}
+
+func TestConstrainedDominates(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        []float64
+		va       float64
+		b        []float64
+		vb       float64
+		expected bool
+	}{
+		{"both feasible", []float64{1, 1}, 0, []float64{2, 2}, 0, true},
+		{"both feasible, non-dominated", []float64{1, 3}, 0, []float64{2, 2}, 0, false},
+		{"feasible beats infeasible", []float64{5, 5}, 0, []float64{1, 1}, 0.1, true},
+		{"infeasible loses to feasible", []float64{1, 1}, 0.1, []float64{5, 5}, 0, false},
+		{"smaller violation wins", []float64{5, 5}, 0.1, []float64{1, 1}, 0.2, true},
+		{"equal violation", []float64{1, 1}, 0.1, []float64{2, 2}, 0.1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := constrainedDominates(tt.a, tt.va, tt.b, tt.vb); got != tt.expected {
+				t.Errorf("Got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNonDominatedSortWithConstraints(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	for _, n := range []int{10, 300} {
+		solutions := randomSolutions(rng, n, 3, 4)
+		for i, s := range solutions {
+			if i%3 == 0 {
+				s.ConstraintViolation = float64(rng.Intn(3)+1) / 4
+			}
+		}
+
+		fronts := dominationCountSort(solutions)
+		expected := make([]int, n)
+		for i, s := range solutions {
+			expected[i] = s.Rank
+		}
+
+		for k, front := range efficientNonDominatedSort(solutions) {
+			for _, i := range front {
+				if expected[i] != k+1 {
+					t.Fatalf("n=%d: solution %d in front %d, expected rank %d", n, i, k+1, expected[i])
+				}
+			}
+		}
+
+		// Every feasible solution ranks before every infeasible one
+		for _, front := range fronts {
+			for _, i := range front {
+				for _, j := range front {
+					if solutions[i].Feasible() != solutions[j].Feasible() {
+						t.Fatalf("n=%d: feasible and infeasible solutions share a front", n)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Package mayfly - Mutation Operators
+//
+// Implements the selectable mutation operators used by the pipeline.
+//
+// Config.MutationType selects the operator for every variant:
+//   - "gaussian": fixed-scale Gaussian mutation (default, see MutateGaussian)
+//   - "cauchy": fixed-scale Cauchy mutation (see MutateCauchy)
+//   - "hybrid": GSASMA's progress-dependent Cauchy/Gaussian mix (default for GSASMA)
+//   - "polynomial": bounded polynomial mutation (Deb & Goyal, 1996)
+//   - "nonuniform": step sizes shrink with the iteration count (Michalewicz, 1996)
+//   - "selfadaptive": log-normal self-adaptation of a per-individual step size,
+//     as in evolution strategies (Schwefel, 1981)
+//
+// An empty MutationType keeps the variant's default operator.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Mutation type names accepted by Config.MutationType.
+const (
+	MutationTypeGaussian     = "gaussian"
+	MutationTypeCauchy       = "cauchy"
+	MutationTypeHybrid       = "hybrid"
+	MutationTypePolynomial   = "polynomial"
+	MutationTypeNonUniform   = "nonuniform"
+	MutationTypeSelfAdaptive = "selfadaptive"
+)
+
+// Settings of the polynomial, non-uniform and self-adaptive operators for an
+// unset MutationEta, NonUniformShape or MutationSigma, and the floor that
+// keeps a self-adapted step size from collapsing to zero.
+const (
+	defaultMutationEta     = 20.0 // Polynomial distribution index
+	defaultNonUniformShape = 5.0  // Non-uniform decay exponent b
+	defaultMutationSigma   = 0.1  // Initial self-adaptive step size (fraction of the range)
+	minMutationSigma       = 1e-8 // Lower bound on the self-adaptive step size (fraction of the range)
+)
+
+// StepSizeMutationOperator is implemented by mutation operators that adapt
+// a per-individual step size. The pipeline passes the parent's Mayfly.Sigma
+// and stores the returned step size in the mutant.
+type StepSizeMutationOperator interface {
+	MutateWithStepSize(s *State, x []float64, sigma float64) ([]float64, float64)
+}
+
+// NewMutationOperator returns the MutationOperator for a Config.MutationType value.
+// An empty name selects Gaussian mutation.
+func NewMutationOperator(mutationType string) (MutationOperator, error) {
+	switch mutationType {
+	case "", MutationTypeGaussian:
+		return GaussianMutation{}, nil
+	case MutationTypeCauchy:
+		return CauchyMutation{}, nil
+	case MutationTypeHybrid:
+		return HybridMutation{}, nil
+	case MutationTypePolynomial:
+		return PolynomialMutation{}, nil
+	case MutationTypeNonUniform:
+		return NonUniformMutation{}, nil
+	case MutationTypeSelfAdaptive:
+		return SelfAdaptiveMutation{}, nil
+	default:
+		return nil, fmt.Errorf("unknown mutation type '%s' (expected gaussian, cauchy, hybrid, polynomial, nonuniform or selfadaptive)",
+			mutationType)
+	}
+}
+
+// validateMutationConfig checks the mutation type and its parameters.
+func validateMutationConfig(config *Config) error {
+	if _, err := NewMutationOperator(config.MutationType); err != nil {
+		return err
+	}
+
+	if config.MutationEta < 0 {
+		return fmt.Errorf("MutationEta must be non-negative, got %v", config.MutationEta)
+	}
+
+	if config.NonUniformShape < 0 {
+		return fmt.Errorf("NonUniformShape must be non-negative, got %v", config.NonUniformShape)
+	}
+
+	if config.MutationSigma < 0 || config.MutationSigma > 1 {
+		return fmt.Errorf("MutationSigma must be in [0, 1], got %v", config.MutationSigma)
+	}
+
+	if config.MutationTau < 0 {
+		return fmt.Errorf("MutationTau must be non-negative, got %v", config.MutationTau)
+	}
+
+	return nil
+}
+
+// mutationIndices returns ceil(mu * nVar) distinct random indices.
+func mutationIndices(nVar int, mu float64, rng *rand.Rand) []int {
+	nMu := int(math.Ceil(mu * float64(nVar)))
+	return rng.Perm(nVar)[:nMu]
+}
+
+// MutatePolynomial applies bounded polynomial mutation with distribution
+// index eta to a fraction mu of the dimensions. Perturbations never leave
+// the bounds, and larger eta values produce smaller steps.
+func MutatePolynomial(x []float64, mu, eta, lowerBound, upperBound float64, rng *rand.Rand) []float64 {
+	y := make([]float64, len(x))
+	copy(y, x)
+
+	width := upperBound - lowerBound
+	power := 1 / (eta + 1)
+
+	for _, j := range mutationIndices(len(x), mu, rng) {
+		delta1 := (y[j] - lowerBound) / width
+		delta2 := (upperBound - y[j]) / width
+		r := rng.Float64()
+
+		var deltaQ float64
+		if r < 0.5 {
+			val := 2*r + (1-2*r)*math.Pow(1-delta1, eta+1)
+			deltaQ = math.Pow(val, power) - 1
+		} else {
+			val := 2*(1-r) + 2*(r-0.5)*math.Pow(1-delta2, eta+1)
+			deltaQ = 1 - math.Pow(val, power)
+		}
+
+		y[j] += deltaQ * width
+	}
+
+	clampVec(y, lowerBound, upperBound)
+
+	return y
+}
+
+// MutateNonUniform applies non-uniform mutation to a fraction mu of the
+// dimensions. progress is the fraction of the run completed (0 to 1); the
+// expected step shrinks to zero as progress approaches 1, with shape
+// controlling how fast.
+func MutateNonUniform(x []float64, mu, progress, shape, lowerBound, upperBound float64, rng *rand.Rand) []float64 {
+	y := make([]float64, len(x))
+	copy(y, x)
+
+	decay := math.Pow(math.Max(0, 1-progress), shape)
+
+	for _, j := range mutationIndices(len(x), mu, rng) {
+		step := 1 - math.Pow(rng.Float64(), decay)
+
+		if rng.Float64() < 0.5 {
+			y[j] += step * (upperBound - y[j])
+		} else {
+			y[j] -= step * (y[j] - lowerBound)
+		}
+	}
+
+	clampVec(y, lowerBound, upperBound)
+
+	return y
+}
+
+// MutateSelfAdaptive applies evolution-strategy style mutation with a single
+// self-adapted step size. The step size is first updated log-normally,
+// sigma' = sigma * exp(tau * N(0,1)), and every dimension is then perturbed
+// by sigma' * N(0,1). Returns the mutant and its step size, which is kept
+// within [1e-8, 1] times the search range.
+func MutateSelfAdaptive(x []float64, sigma, tau, lowerBound, upperBound float64, rng *rand.Rand) ([]float64, float64) {
+	width := upperBound - lowerBound
+
+	newSigma := sigma * math.Exp(tau*randn(rng))
+	newSigma = math.Max(minMutationSigma*width, math.Min(newSigma, width))
+
+	y := make([]float64, len(x))
+	for j := range x {
+		y[j] = x[j] + newSigma*randn(rng)
+	}
+
+	clampVec(y, lowerBound, upperBound)
+
+	return y, newSigma
+}
+
+// inheritedSigma returns the step size of an offspring of male and female:
+// the mean of the parents' step sizes that have been assigned, or 0 if
+// neither has one.
+func inheritedSigma(male, female *Mayfly) float64 {
+	switch {
+	case male.Sigma > 0 && female.Sigma > 0:
+		return 0.5 * (male.Sigma + female.Sigma)
+	case male.Sigma > 0:
+		return male.Sigma
+	default:
+		return female.Sigma
+	}
+}
+
+// CauchyMutation is a MutationOperator using MutateCauchy with Config.Mu.
+type CauchyMutation struct{}
+
+// Mutate implements MutationOperator.
+func (CauchyMutation) Mutate(s *State, x []float64) []float64 {
+	return MutateCauchy(x, s.Config.Mu, s.Config.LowerBound, s.Config.UpperBound, s.Rand)
+}
+
+// PolynomialMutation is a MutationOperator using MutatePolynomial with
+// Config.Mu and Config.MutationEta.
+type PolynomialMutation struct{}
+
+// Mutate implements MutationOperator.
+func (PolynomialMutation) Mutate(s *State, x []float64) []float64 {
+	eta := orDefault(s.Config.MutationEta, defaultMutationEta)
+	return MutatePolynomial(x, s.Config.Mu, eta, s.Config.LowerBound, s.Config.UpperBound, s.Rand)
+}
+
+// NonUniformMutation is a MutationOperator using MutateNonUniform with
+// Config.Mu, Config.NonUniformShape and the current iteration progress.
+type NonUniformMutation struct{}
+
+// Mutate implements MutationOperator.
+func (NonUniformMutation) Mutate(s *State, x []float64) []float64 {
+	shape := orDefault(s.Config.NonUniformShape, defaultNonUniformShape)
+	return MutateNonUniform(x, s.Config.Mu, s.Progress(), shape, s.Config.LowerBound, s.Config.UpperBound, s.Rand)
+}
+
+// SelfAdaptiveMutation is a MutationOperator using MutateSelfAdaptive.
+// Individuals without a step size start at Config.MutationSigma times the
+// search range. The learning rate is Config.MutationTau, or 1/sqrt(n) if zero.
+type SelfAdaptiveMutation struct{}
+
+// Mutate implements MutationOperator for callers that do not track step sizes.
+func (m SelfAdaptiveMutation) Mutate(s *State, x []float64) []float64 {
+	y, _ := m.MutateWithStepSize(s, x, 0)
+	return y
+}
+
+// MutateWithStepSize implements StepSizeMutationOperator.
+func (SelfAdaptiveMutation) MutateWithStepSize(s *State, x []float64, sigma float64) ([]float64, float64) {
+	config := s.Config
+
+	if sigma == 0 {
+		sigma = orDefault(config.MutationSigma, defaultMutationSigma) * (config.UpperBound - config.LowerBound)
+	}
+
+	tau := config.MutationTau
+	if tau == 0 {
+		tau = 1 / math.Sqrt(float64(len(x)))
+	}
+
+	return MutateSelfAdaptive(x, sigma, tau, config.LowerBound, config.UpperBound, s.Rand)
+}
@@ -0,0 +1,208 @@
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for mutation.go - Selectable mutation operators
+// =============================================================================
+
+func TestMutatePolynomialStaysInBounds(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	x := []float64{-5, 0, 4.9, 5}
+
+	for trial := 0; trial < 200; trial++ {
+		y := MutatePolynomial(x, 1.0, 5, -5, 5, rng)
+
+		for j, v := range y {
+			if v < -5 || v > 5 {
+				t.Fatalf("Gene %d = %v outside bounds", j, v)
+			}
+		}
+	}
+}
+
+func TestMutatePolynomialRespectsMu(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	x := make([]float64, 10)
+
+	y := MutatePolynomial(x, 0.2, 20, -1, 1, rng)
+
+	changed := 0
+	for j := range x {
+		if y[j] != x[j] {
+			changed++
+		}
+	}
+
+	if changed > 2 {
+		t.Errorf("Expected at most 2 mutated genes, got %d", changed)
+	}
+}
+
+func TestMutateNonUniformShrinks(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	x := make([]float64, 20)
+
+	meanStep := func(progress float64) float64 {
+		total := 0.0
+		for trial := 0; trial < 200; trial++ {
+			y := MutateNonUniform(x, 1.0, progress, 5, -10, 10, rng)
+			for j := range y {
+				total += math.Abs(y[j])
+			}
+		}
+
+		return total
+	}
+
+	early, late := meanStep(0), meanStep(0.95)
+	if late >= early/10 {
+		t.Errorf("Late steps (%v) should be much smaller than early steps (%v)", late, early)
+	}
+
+	if final := meanStep(1); final != 0 {
+		t.Errorf("Mutation at the end of the run should not move, moved %v", final)
+	}
+}
+
+func TestMutateSelfAdaptive(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	x := make([]float64, 5)
+
+	_, sigma := MutateSelfAdaptive(x, 1.0, 0, -10, 10, rng)
+	if sigma != 1.0 {
+		t.Errorf("Step size should be unchanged with tau = 0, got %v", sigma)
+	}
+
+	_, sigma = MutateSelfAdaptive(x, 0, 0.5, -10, 10, rng)
+	if sigma < 1e-8*20 {
+		t.Errorf("Step size %v below the minimum", sigma)
+	}
+
+	_, sigma = MutateSelfAdaptive(x, 1e6, 0, -10, 10, rng)
+	if sigma != 20 {
+		t.Errorf("Step size should be capped at the range, got %v", sigma)
+	}
+}
+
+func TestSelfAdaptiveStepSizesPropagate(t *testing.T) {
+	config := newTestConfig(Sphere, 5, 10, 30, 8)
+	config.MutationType = MutationTypeSelfAdaptive
+	config.NM = 4
+
+	recorder := &sigmaRecorder{}
+	pipeline := NewPipeline(config).AddStage(recorder)
+
+	if _, err := OptimizeWithPipeline(config, pipeline); err != nil {
+		t.Fatalf("OptimizeWithPipeline failed: %v", err)
+	}
+
+	if recorder.assigned == 0 {
+		t.Error("Expected offspring to carry self-adapted step sizes")
+	}
+}
+
+// sigmaRecorder counts surviving mayflies with an assigned step size.
+type sigmaRecorder struct {
+	assigned int
+}
+
+func (r *sigmaRecorder) Name() string { return "sigma" }
+
+func (r *sigmaRecorder) PostSelect(s *State) {
+	for _, population := range [][]*Mayfly{s.Males, s.Females} {
+		for _, m := range population {
+			if m.Sigma > 0 {
+				r.assigned++
+			}
+		}
+	}
+}
+
+func TestInheritedSigma(t *testing.T) {
+	tests := []struct {
+		male, female, expected float64
+	}{
+		{0.2, 0.4, 0.3},
+		{0.2, 0, 0.2}, // An unassigned step size does not halve the other
+		{0, 0.4, 0.4},
+		{0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := inheritedSigma(&Mayfly{Sigma: tt.male}, &Mayfly{Sigma: tt.female}); math.Abs(got-tt.expected) > 1e-15 {
+			t.Errorf("inheritedSigma(%v, %v) = %v, expected %v", tt.male, tt.female, got, tt.expected)
+		}
+	}
+}
+
+func TestMutationTypesOptimize(t *testing.T) {
+	for _, mutationType := range []string{
+		MutationTypeGaussian, MutationTypeCauchy, MutationTypeHybrid,
+		MutationTypePolynomial, MutationTypeNonUniform, MutationTypeSelfAdaptive,
+	} {
+		t.Run(mutationType, func(t *testing.T) {
+			config := newTestConfig(Sphere, 5, 10, 30, 9)
+			config.MaxIterations = 100
+			config.MutationType = mutationType
+
+			if err := ValidateConfig(config); err != nil {
+				t.Fatalf("ValidateConfig failed: %v", err)
+			}
+
+			result, err := Optimize(config)
+			if err != nil {
+				t.Fatalf("Optimize failed: %v", err)
+			}
+
+			if result.GlobalBest.Cost > 1.0 {
+				t.Errorf("%s did not converge on Sphere: %v", mutationType, result.GlobalBest.Cost)
+			}
+		})
+	}
+}
+
+func TestNewPipelineMutationType(t *testing.T) {
+	if _, ok := NewPipeline(NewGSASMAConfig()).Mutation.(HybridMutation); !ok {
+		t.Error("GSASMA should default to hybrid mutation")
+	}
+
+	config := NewGSASMAConfig()
+	config.MutationType = MutationTypePolynomial
+
+	if _, ok := NewPipeline(config).Mutation.(PolynomialMutation); !ok {
+		t.Error("MutationType should override the variant default")
+	}
+}
+
+func TestMutationConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"Unknown type", func(c *Config) { c.MutationType = "uniform" }},
+		{"Negative eta", func(c *Config) { c.MutationEta = -1 }},
+		{"Negative shape", func(c *Config) { c.NonUniformShape = -1 }},
+		{"Sigma above one", func(c *Config) { c.MutationSigma = 2 }},
+		{"Negative tau", func(c *Config) { c.MutationTau = -0.1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newTestConfig(Sphere, 5, 10, 30, 1)
+			tt.modify(config)
+
+			if err := ValidateConfig(config); err == nil {
+				t.Error("ValidateConfig should reject the config")
+			}
+
+			if _, err := Optimize(config); err == nil {
+				t.Error("Optimize should reject the config")
+			}
+		})
+	}
+}
@@ -0,0 +1,477 @@
+// Package mayfly - Niching
+//
+// Implements a multimodal mode that keeps several niches alive and returns
+// multiple distinct optima instead of a single global best.
+//
+// Config.NichingMethod replaces the truncation selection of males and
+// females by a niche-preserving survivor selection:
+//   - "clearing": the best NicheCapacity individuals within NicheRadius of a
+//     niche winner survive first, all others only fill remaining slots
+//     (Pétrowski, 1996)
+//   - "sharing": individuals are ranked by fitness divided by their niche
+//     count, using the sharing function 1-(d/NicheRadius)^SharingAlpha
+//     (Goldberg & Richardson, 1987)
+//   - "speciation": the population is divided into species of radius
+//     NicheRadius around the species seeds; the species take turns in
+//     passing on their best remaining member (Li et al., 2002)
+//
+// After every iteration the species seeds update an archive holding the best
+// solution per niche. Entries closer than NicheRadius are merged, keeping
+// the better one, and at most MaxOptima entries are kept. The archive is
+// returned as Result.Optima, sorted by cost.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Niching method names accepted by Config.NichingMethod.
+const (
+	NichingNone       = "none"
+	NichingClearing   = "clearing"
+	NichingSharing    = "sharing"
+	NichingSpeciation = "speciation"
+)
+
+// Niche settings for an unset NicheRadius, NicheCapacity or SharingAlpha: a
+// radius of a tenth of the search range, one winner per niche in clearing,
+// and the triangular sharing function.
+const (
+	defaultNicheRadius   = 0.1 // Fraction of the search range
+	defaultNicheCapacity = 1
+	defaultSharingAlpha  = 1.0
+)
+
+// validateNichingConfig checks the niching method and its parameters.
+func validateNichingConfig(config *Config) error {
+	switch config.NichingMethod {
+	case "", NichingNone, NichingClearing, NichingSharing, NichingSpeciation:
+	default:
+		return fmt.Errorf("unknown niching method '%s' (expected none, clearing, sharing or speciation)", config.NichingMethod)
+	}
+
+	if config.NicheRadius < 0 {
+		return fmt.Errorf("NicheRadius must be non-negative, got %v", config.NicheRadius)
+	}
+
+	if config.NicheCapacity < 0 {
+		return fmt.Errorf("NicheCapacity must be non-negative, got %d", config.NicheCapacity)
+	}
+
+	if config.SharingAlpha < 0 {
+		return fmt.Errorf("SharingAlpha must be non-negative, got %v", config.SharingAlpha)
+	}
+
+	if config.MaxOptima < 0 {
+		return fmt.Errorf("MaxOptima must be non-negative, got %d", config.MaxOptima)
+	}
+
+	return nil
+}
+
+// nichingEnabled reports whether config requests the niching mode.
+func nichingEnabled(config *Config) bool {
+	return config.NichingMethod != "" && config.NichingMethod != NichingNone
+}
+
+// nicheRadius returns the configured niche radius or its default.
+func nicheRadius(config *Config) float64 {
+	return orDefault(config.NicheRadius, defaultNicheRadius*(config.UpperBound-config.LowerBound))
+}
+
+// nicheController performs niche-preserving survivor selection and keeps
+// the archive of the best solution per niche.
+type nicheController struct {
+	method    string
+	radius    float64
+	capacity  int
+	alpha     float64
+	maxOptima int
+	archive   []Best
+	leaders   []Best // Species seeds of the males, attract the other males
+}
+
+// newNicheController creates the niching component for config.
+func newNicheController(config *Config) *nicheController {
+	n := &nicheController{
+		method:    config.NichingMethod,
+		radius:    nicheRadius(config),
+		capacity:  config.NicheCapacity,
+		alpha:     orDefault(config.SharingAlpha, defaultSharingAlpha),
+		maxOptima: config.MaxOptima,
+	}
+
+	if n.capacity == 0 {
+		n.capacity = defaultNicheCapacity
+	}
+
+	if n.maxOptima == 0 {
+		n.maxOptima = config.NPop
+	}
+
+	return n
+}
+
+// survivors selects size individuals of population, which must be sorted
+// by cost. The survivors are returned sorted by cost.
+func (n *nicheController) survivors(population []*Mayfly, size int) []*Mayfly {
+	if len(population) <= size {
+		return population
+	}
+
+	var selected []*Mayfly
+
+	switch n.method {
+	case NichingClearing:
+		selected = n.clearing(population, size)
+	case NichingSharing:
+		selected = n.sharing(population, size)
+	default:
+		selected = n.speciation(population, size)
+	}
+
+	sortMayflies(selected)
+
+	return selected
+}
+
+// clearing keeps the niche winners first and fills up with cleared individuals.
+func (n *nicheController) clearing(population []*Mayfly, size int) []*Mayfly {
+	cleared := make([]bool, len(population))
+	winners := make([]*Mayfly, 0, size)
+	losers := make([]*Mayfly, 0, len(population))
+
+	for i, m := range population {
+		if cleared[i] {
+			losers = append(losers, m)
+			continue
+		}
+
+		winners = append(winners, m)
+		members := 1
+
+		for j := i + 1; j < len(population); j++ {
+			if cleared[j] || euclideanDistance(m.Position, population[j].Position) >= n.radius {
+				continue
+			}
+
+			if members < n.capacity {
+				members++
+			} else {
+				cleared[j] = true
+			}
+		}
+	}
+
+	return fill(winners, losers, size)
+}
+
+// sharing keeps the individuals with the best shared fitness. The best
+// individual always survives.
+func (n *nicheController) sharing(population []*Mayfly, size int) []*Mayfly {
+	worst := population[len(population)-1].Cost
+	best := population[0].Cost
+
+	shared := make([]float64, len(population))
+
+	for i, m := range population {
+		// Fitness in (0, 1] for maximization, 1 for the best individual
+		fitness := 1.0
+		if worst > best && !math.IsInf(worst, 0) {
+			fitness = (worst-m.Cost)/(worst-best) + 1e-12
+		}
+
+		count := 0.0
+		for _, other := range population {
+			if d := euclideanDistance(m.Position, other.Position); d < n.radius {
+				count += 1 - math.Pow(d/n.radius, n.alpha)
+			}
+		}
+
+		shared[i] = fitness / count
+	}
+
+	order := make([]int, len(population)-1)
+	for k := range order {
+		order[k] = k + 1
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return shared[order[a]] > shared[order[b]]
+	})
+
+	selected := make([]*Mayfly, 0, size)
+	selected = append(selected, population[0])
+
+	for _, i := range order[:size-1] {
+		selected = append(selected, population[i])
+	}
+
+	return selected
+}
+
+// speciation assigns every individual to the species of the first seed
+// within the niche radius. Species take turns: first every seed survives,
+// then the second-best member of every species, and so on.
+func (n *nicheController) speciation(population []*Mayfly, size int) []*Mayfly {
+	seeds := n.seeds(population)
+	species := make([][]*Mayfly, len(seeds))
+
+	for _, m := range population {
+		for k, seed := range seeds {
+			if m == seed || euclideanDistance(m.Position, seed.Position) < n.radius {
+				species[k] = append(species[k], m)
+				break
+			}
+		}
+	}
+
+	selected := make([]*Mayfly, 0, size)
+
+	for rank := 0; len(selected) < size; rank++ {
+		for _, members := range species {
+			if rank < len(members) && len(selected) < size {
+				selected = append(selected, members[rank])
+			}
+		}
+	}
+
+	return selected
+}
+
+// seeds returns the species seeds of population, which must be sorted by
+// cost: every individual that is not within the niche radius of a better seed.
+func (n *nicheController) seeds(population []*Mayfly) []*Mayfly {
+	var seeds []*Mayfly
+
+	for _, m := range population {
+		found := false
+
+		for _, seed := range seeds {
+			if euclideanDistance(m.Position, seed.Position) < n.radius {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			seeds = append(seeds, m)
+		}
+	}
+
+	return seeds
+}
+
+// fill returns the first size individuals of preferred followed by rest.
+func fill(preferred, rest []*Mayfly, size int) []*Mayfly {
+	if len(preferred) >= size {
+		return append([]*Mayfly(nil), preferred[:size]...)
+	}
+
+	selected := append([]*Mayfly(nil), preferred...)
+
+	return append(selected, rest[:size-len(preferred)]...)
+}
+
+// update offers the species seeds of the current populations and the
+// global best to the archive, and determines the niche leaders of the males.
+func (n *nicheController) update(s *State) {
+	males := append([]*Mayfly(nil), s.Males...)
+	sortMayflies(males)
+
+	n.leaders = n.leaders[:0]
+	for _, seed := range n.seeds(males) {
+		n.leaders = append(n.leaders, Best{
+			Position: append([]float64(nil), seed.Position...),
+			Cost:     seed.Cost,
+		})
+	}
+
+	population := make([]*Mayfly, 0, len(s.Males)+len(s.Females))
+	population = append(population, s.Males...)
+	population = append(population, s.Females...)
+	sortMayflies(population)
+
+	candidates := []Best{s.GlobalBest}
+	for _, seed := range n.seeds(population) {
+		candidates = append(candidates, Best{Position: seed.Position, Cost: seed.Cost})
+	}
+
+	for _, c := range candidates {
+		n.offer(c)
+	}
+
+	n.archive = mergeOptima(n.archive, n.radius, n.maxOptima)
+}
+
+// leader returns the niche leader nearest to position.
+func (n *nicheController) leader(position []float64) Best {
+	nearest, distance := 0, math.Inf(1)
+
+	for i, leader := range n.leaders {
+		if d := euclideanDistance(position, leader.Position); d < distance {
+			nearest, distance = i, d
+		}
+	}
+
+	return n.leaders[nearest]
+}
+
+// offer adds candidate to the archive, or replaces the nearest archived
+// solution within the niche radius if the candidate is better.
+func (n *nicheController) offer(candidate Best) {
+	if math.IsInf(candidate.Cost, 0) || math.IsNaN(candidate.Cost) {
+		return
+	}
+
+	nearest, distance := -1, math.Inf(1)
+
+	for i, entry := range n.archive {
+		if d := euclideanDistance(candidate.Position, entry.Position); d < distance {
+			nearest, distance = i, d
+		}
+	}
+
+	entry := Best{Position: append([]float64(nil), candidate.Position...), Cost: candidate.Cost}
+
+	switch {
+	case nearest < 0 || distance >= n.radius:
+		n.archive = append(n.archive, entry)
+	case candidate.Cost < n.archive[nearest].Cost:
+		n.archive[nearest] = entry
+	}
+}
+
+// optima returns a copy of the archive, sorted by cost.
+func (n *nicheController) optima() []Best {
+	optima := make([]Best, len(n.archive))
+	for i, entry := range n.archive {
+		optima[i] = Best{Position: append([]float64(nil), entry.Position...), Cost: entry.Cost}
+	}
+
+	return optima
+}
+
+// mergeOptima sorts optima by cost and removes every solution within radius
+// of a better one. At most limit solutions are kept (0 = no limit).
+func mergeOptima(optima []Best, radius float64, limit int) []Best {
+	sort.SliceStable(optima, func(i, j int) bool {
+		return optima[i].Cost < optima[j].Cost
+	})
+
+	merged := optima[:0]
+
+	for _, candidate := range optima {
+		distinct := true
+
+		for _, kept := range merged {
+			if euclideanDistance(candidate.Position, kept.Position) < radius {
+				distinct = false
+				break
+			}
+		}
+
+		if distinct {
+			merged = append(merged, candidate)
+		}
+
+		if limit > 0 && len(merged) == limit {
+			break
+		}
+	}
+
+	return merged
+}
+
+// NichingProblem describes a multimodal benchmark with several global
+// optima, in the minimization form used by this package. LowerBound and
+// UpperBound enclose the domain; where the reference domain is narrower in
+// some dimensions, Func costs +Inf outside it.
+type NichingProblem struct {
+	Name         string
+	Func         ObjectiveFunction
+	ProblemSize  int
+	LowerBound   float64
+	UpperBound   float64
+	OptimumCost  float64 // Cost of every global optimum
+	GlobalOptima int     // Number of global optima
+	Radius       float64 // Distance within which two optima are the same
+	MaxFuncEvals int     // Evaluation budget of the benchmark
+}
+
+// CECNichingProblems returns the problems F1-F8 of the CEC 2013 niching
+// benchmark (Li, Engelbrecht & Epitropakis, 2013), negated for minimization.
+// The composition functions F9-F12 need the benchmark's shift data and are
+// not included.
+func CECNichingProblems() []NichingProblem {
+	return []NichingProblem{
+		{"F1 Five-Uneven-Peak Trap", FiveUnevenPeakTrap, 1, 0, 30, -200, 2, 0.01, 50000},
+		{"F2 Equal Maxima", EqualMaxima, 1, 0, 1, -1, 5, 0.01, 50000},
+		{"F3 Uneven Decreasing Maxima", UnevenDecreasingMaxima, 1, 0, 1, -1, 1, 0.01, 50000},
+		{"F4 Himmelblau", Himmelblau, 2, -6, 6, 0, 4, 0.01, 50000},
+		{"F5 Six-Hump Camel Back", restrictDomain(SixHumpCamelBack, []float64{-1.9, -1.1}, []float64{1.9, 1.1}),
+			2, -1.9, 1.9, -1.031628453489877, 2, 0.5, 50000},
+		{"F6 Shubert 2D", Shubert, 2, -10, 10, -186.7309088310239, 18, 0.5, 200000},
+		{"F7 Vincent 2D", Vincent, 2, 0.25, 10, -1, 36, 0.2, 200000},
+		{"F8 Modified Rastrigin 2D", ModifiedRastrigin, 2, 0, 1, 2, 12, 0.01, 200000},
+	}
+}
+
+// restrictDomain returns fn limited to the box [lower, upper]: positions
+// outside it cost +Inf.
+func restrictDomain(fn ObjectiveFunction, lower, upper []float64) ObjectiveFunction {
+	return func(x []float64) float64 {
+		for j, v := range x {
+			if v < lower[j] || v > upper[j] {
+				return math.Inf(1)
+			}
+		}
+
+		return fn(x)
+	}
+}
+
+// FoundOptima returns the number of global optima of the problem found in
+// optima with the given accuracy, following the CEC 2013 niching protocol:
+// solutions within accuracy of the optimum cost count once per Radius.
+func (p NichingProblem) FoundOptima(optima []Best, accuracy float64) int {
+	sorted := append([]Best(nil), optima...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Cost < sorted[j].Cost
+	})
+
+	var found []Best
+
+	for _, candidate := range sorted {
+		if math.Abs(candidate.Cost-p.OptimumCost) > accuracy {
+			continue
+		}
+
+		distinct := true
+
+		for _, seed := range found {
+			if euclideanDistance(candidate.Position, seed.Position) <= p.Radius {
+				distinct = false
+				break
+			}
+		}
+
+		if distinct {
+			found = append(found, candidate)
+		}
+	}
+
+	if len(found) > p.GlobalOptima {
+		return p.GlobalOptima
+	}
+
+	return len(found)
+}
+
+// PeakRatio returns the fraction of the problem's global optima found in
+// optima with the given accuracy.
+func (p NichingProblem) PeakRatio(optima []Best, accuracy float64) float64 {
+	return float64(p.FoundOptima(optima, accuracy)) / float64(p.GlobalOptima)
+}
@@ -0,0 +1,250 @@
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for niching.go - Niching mode and multimodal benchmarks
+// =============================================================================
+
+func newNichingTestConfig(problem NichingProblem, method string, seed int64) *Config {
+	config := NewDefaultConfig()
+	config.ObjectiveFunc = problem.Func
+	config.ProblemSize = problem.ProblemSize
+	config.LowerBound = problem.LowerBound
+	config.UpperBound = problem.UpperBound
+	config.MaxIterations = 200
+	config.Rand = rand.New(rand.NewSource(seed))
+	config.NichingMethod = method
+
+	return config
+}
+
+func cecNichingProblem(t *testing.T, prefix string) NichingProblem {
+	t.Helper()
+
+	for _, problem := range CECNichingProblems() {
+		if problem.Name[:len(prefix)] == prefix {
+			return problem
+		}
+	}
+
+	t.Fatalf("CEC niching problem %s not found", prefix)
+
+	return NichingProblem{}
+}
+
+func TestCECNichingFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		f        ObjectiveFunction
+		x        []float64
+		expected float64
+	}{
+		{"FiveUnevenPeakTrap", FiveUnevenPeakTrap, []float64{30}, -200},
+		{"EqualMaxima", EqualMaxima, []float64{0.7}, -1},
+		{"UnevenDecreasingMaxima", UnevenDecreasingMaxima, []float64{0.0797}, -1},
+		{"Himmelblau", Himmelblau, []float64{3, 2}, 0},
+		{"SixHumpCamelBack", SixHumpCamelBack, []float64{0.0898420131, -0.7126564030}, -1.031628453489877},
+		{"Shubert", Shubert, []float64{-1.4251284283, -0.8003211004}, -186.7309088310239},
+		{"Vincent", Vincent, []float64{math.Exp(math.Pi / 20), math.Exp(5 * math.Pi / 20)}, -1},
+		{"ModifiedRastrigin", ModifiedRastrigin, []float64{0.5, 0.375}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f(tt.x); math.Abs(got-tt.expected) > 1e-6 {
+				t.Errorf("%s(%v) = %v, expected %v", tt.name, tt.x, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCECNichingDomain(t *testing.T) {
+	// F5 is defined on [-1.9, 1.9] x [-1.1, 1.1]
+	problem := cecNichingProblem(t, "F5")
+
+	if got := problem.Func([]float64{0.0898420131, -0.7126564030}); math.Abs(got-problem.OptimumCost) > 1e-6 {
+		t.Errorf("F5 optimum costs %v, expected %v", got, problem.OptimumCost)
+	}
+
+	for _, x := range [][]float64{{0, 1.5}, {0, -1.2}} {
+		if got := problem.Func(x); !math.IsInf(got, 1) {
+			t.Errorf("F5(%v) = %v outside the domain, expected +Inf", x, got)
+		}
+	}
+
+	result, err := Optimize(newNichingTestConfig(problem, NichingClearing, 1))
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if found := problem.FoundOptima(result.Optima, 1e-2); found != problem.GlobalOptima {
+		t.Errorf("Found %d of %d optima", found, problem.GlobalOptima)
+	}
+}
+
+func TestNichingEqualMaxima(t *testing.T) {
+	problem := cecNichingProblem(t, "F2")
+
+	for _, method := range []string{NichingClearing, NichingSharing, NichingSpeciation} {
+		t.Run(method, func(t *testing.T) {
+			result, err := Optimize(newNichingTestConfig(problem, method, 1))
+			if err != nil {
+				t.Fatalf("Optimize failed: %v", err)
+			}
+
+			found := problem.FoundOptima(result.Optima, 1e-2)
+			if found < 3 || (method != NichingSharing && found != problem.GlobalOptima) {
+				t.Errorf("Found %d of %d optima", found, problem.GlobalOptima)
+			}
+		})
+	}
+}
+
+func TestNichingHimmelblau(t *testing.T) {
+	problem := cecNichingProblem(t, "F4")
+
+	result, err := Optimize(newNichingTestConfig(problem, NichingSpeciation, 2))
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if ratio := problem.PeakRatio(result.Optima, 1e-2); ratio < 0.5 {
+		t.Errorf("Peak ratio %.2f, expected at least 0.5", ratio)
+	}
+
+	if result.Optima[0].Cost != result.GlobalBest.Cost {
+		t.Errorf("First optimum %v should be the global best %v", result.Optima[0].Cost, result.GlobalBest.Cost)
+	}
+
+	radius := nicheRadius(newNichingTestConfig(problem, NichingSpeciation, 2))
+
+	for i := range result.Optima {
+		if i > 0 && result.Optima[i].Cost < result.Optima[i-1].Cost {
+			t.Fatalf("Optima not sorted by cost at %d", i)
+		}
+
+		for j := 0; j < i; j++ {
+			if euclideanDistance(result.Optima[i].Position, result.Optima[j].Position) < radius {
+				t.Fatalf("Optima %d and %d lie in the same niche", j, i)
+			}
+		}
+	}
+}
+
+func TestNicheSurvivors(t *testing.T) {
+	// Two niches around 0 and 10, sorted by cost
+	var population []*Mayfly
+	for _, pc := range [][2]float64{{0, 0}, {0.1, 1}, {0.2, 2}, {10, 2.5}, {0.3, 3}, {10.1, 5}} {
+		m := newMayfly(1)
+		m.Position[0] = pc[0]
+		m.Cost = pc[1]
+		population = append(population, m)
+	}
+
+	for _, method := range []string{NichingClearing, NichingSharing, NichingSpeciation} {
+		t.Run(method, func(t *testing.T) {
+			n := &nicheController{method: method, radius: 1, capacity: 1, alpha: 1}
+			survivors := n.survivors(population, 3)
+
+			if len(survivors) != 3 || survivors[0].Cost != 0 {
+				t.Fatalf("Unexpected survivors %v", survivors)
+			}
+
+			// Truncation would keep three members of the first niche
+			second := false
+			for _, m := range survivors {
+				second = second || m.Position[0] == 10
+			}
+
+			if !second {
+				t.Error("Best of the second niche should survive")
+			}
+		})
+	}
+
+	n := &nicheController{method: NichingClearing, radius: 1, capacity: 2}
+	if survivors := n.survivors(population, 4); survivors[2].Position[0] != 10 || survivors[3].Position[0] != 10.1 {
+		t.Errorf("Clearing with capacity 2 should keep two per niche, got %v", survivors)
+	}
+}
+
+func TestNicheArchive(t *testing.T) {
+	n := &nicheController{radius: 1, maxOptima: 2}
+
+	n.offer(Best{Position: []float64{0}, Cost: 5})
+	n.offer(Best{Position: []float64{0.5}, Cost: 3}) // Same niche, better
+	n.offer(Best{Position: []float64{4}, Cost: 4})
+	n.offer(Best{Position: []float64{8}, Cost: 6})
+	n.archive = mergeOptima(n.archive, n.radius, n.maxOptima)
+
+	optima := n.optima()
+	if len(optima) != 2 || optima[0].Position[0] != 0.5 || optima[1].Cost != 4 {
+		t.Errorf("Unexpected archive %v", optima)
+	}
+}
+
+func TestNichingWithRestarts(t *testing.T) {
+	problem := cecNichingProblem(t, "F2")
+	config := newNichingTestConfig(problem, NichingClearing, 3)
+	config.RestartStrategy = RestartStrategyRestart
+	config.RestartStagnation = 20
+
+	result, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if len(result.Restarts) == 0 {
+		t.Fatal("Expected at least one restart")
+	}
+
+	// The archive carries over the niches of earlier runs
+	if found := problem.FoundOptima(result.Optima, 1e-2); found != problem.GlobalOptima {
+		t.Errorf("Found %d of %d optima", found, problem.GlobalOptima)
+	}
+}
+
+func TestNoOptimaByDefault(t *testing.T) {
+	result, err := Optimize(newTestConfig(Sphere, 5, 10, 30, 1))
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if result.Optima != nil {
+		t.Errorf("Expected no optima without niching, got %d", len(result.Optima))
+	}
+}
+
+func TestNichingConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"Unknown method", func(c *Config) { c.NichingMethod = "crowding" }},
+		{"Negative radius", func(c *Config) { c.NicheRadius = -1 }},
+		{"Negative capacity", func(c *Config) { c.NicheCapacity = -1 }},
+		{"Negative alpha", func(c *Config) { c.SharingAlpha = -1 }},
+		{"Negative max optima", func(c *Config) { c.MaxOptima = -1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newTestConfig(Sphere, 5, 10, 30, 1)
+			config.NichingMethod = NichingClearing
+			tt.modify(config)
+
+			if err := ValidateConfig(config); err == nil {
+				t.Error("ValidateConfig should reject the config")
+			}
+
+			if _, err := Optimize(config); err == nil {
+				t.Error("Optimize should reject the config")
+			}
+		})
+	}
+}
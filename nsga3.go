@@ -0,0 +1,305 @@
+// Package mayfly - Reference-point niching (NSGA-III)
+//
+// Implements the survivor selection of NSGA-III (Deb & Jain, 2014), used by
+// the "nsga3" mode of OptimizeMulti. Crowding distance loses its meaning in
+// many-objective spaces; NSGA-III instead spreads the solutions over a set of
+// reference directions:
+//   - objectives are normalized by the ideal point and the intercepts of the
+//     hyperplane through the extreme points of the population
+//   - every solution is associated with the reference direction of smallest
+//     perpendicular distance
+//   - within the last front that fits only partly, solutions are picked one
+//     at a time from the direction with the fewest associated survivors,
+//     preferring the closest solution for directions without any
+//
+// Reference directions are Das-Dennis points on the unit simplex.
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+)
+
+// DasDennisPoints returns all points on the unit simplex in objectives
+// dimensions whose coordinates are multiples of 1/divisions (Das & Dennis,
+// 1998). There are C(divisions+objectives-1, objectives-1) points; they
+// serve as reference directions and weight vectors (e.g. for CalculateR2).
+func DasDennisPoints(objectives, divisions int) [][]float64 {
+	var points [][]float64
+
+	var generate func(prefix []float64, left, depth int)
+	generate = func(prefix []float64, left, depth int) {
+		if depth == objectives-1 {
+			point := append(append([]float64(nil), prefix...), float64(left)/float64(divisions))
+			points = append(points, point)
+
+			return
+		}
+
+		for i := 0; i <= left; i++ {
+			generate(append(prefix, float64(i)/float64(divisions)), left-i, depth+1)
+		}
+	}
+
+	generate(nil, divisions, 0)
+
+	return points
+}
+
+// dasDennisDivisions returns the largest number of divisions (at least 1)
+// for which m-dimensional Das-Dennis points number at most points.
+func dasDennisDivisions(m, points int) int {
+	divisions := 1
+	for binomial(divisions+m, m-1) <= points {
+		divisions++
+	}
+
+	return divisions
+}
+
+// binomial returns n choose k.
+func binomial(n, k int) int {
+	result := 1
+	for i := 1; i <= k; i++ {
+		result = result * (n - k + i) / i
+	}
+
+	return result
+}
+
+// referencePointOrder sorts solutions into non-dominated fronts and orders
+// every front by reference-point niching: the members are listed in the
+// order in which NSGA-III would pick them, given that all better fronts
+// survive. Taking the first n solutions of the concatenated fronts is the
+// NSGA-III selection of n survivors. The Rank of the solutions is set.
+func referencePointOrder(solutions []*ParetoSolution, references [][]float64, rng *rand.Rand) [][]int {
+	fronts := fastNonDominatedSort(solutions)
+	if len(fronts) == 0 {
+		return fronts
+	}
+
+	normalized := normalizeObjectives(solutions, fronts[0])
+
+	// Association with the nearest reference direction
+	niche := make([]int, len(solutions))
+	distance := make([]float64, len(solutions))
+
+	for i, f := range normalized {
+		distance[i] = math.Inf(1)
+		for j, w := range references {
+			if d := perpendicularDistance(f, w); d < distance[i] {
+				niche[i], distance[i] = j, d
+			}
+		}
+	}
+
+	counts := make([]int, len(references))
+	ordered := make([][]int, len(fronts))
+
+	for k, front := range fronts {
+		members := make([][]int, len(references))
+		for _, i := range front {
+			members[niche[i]] = append(members[niche[i]], i)
+		}
+
+		var candidates []int
+
+		for len(ordered[k]) < len(front) {
+			// Least crowded directions that still have members
+			candidates = candidates[:0]
+			for j, list := range members {
+				switch {
+				case len(list) == 0:
+				case len(candidates) == 0 || counts[j] < counts[candidates[0]]:
+					candidates = append(candidates[:0], j)
+				case counts[j] == counts[candidates[0]]:
+					candidates = append(candidates, j)
+				}
+			}
+
+			j := candidates[rng.Intn(len(candidates))]
+			list := members[j]
+
+			pick := 0
+			if counts[j] == 0 {
+				for p, i := range list {
+					if distance[i] < distance[list[pick]] {
+						pick = p
+					}
+				}
+			} else {
+				pick = rng.Intn(len(list))
+			}
+
+			ordered[k] = append(ordered[k], list[pick])
+			members[j] = append(list[:pick], list[pick+1:]...)
+			counts[j]++
+		}
+	}
+
+	return ordered
+}
+
+// truncateByReferencePoints keeps the first size solutions of the
+// non-dominated set front in reference-point niching order. Crowding
+// distances are up to date afterwards.
+func truncateByReferencePoints(front []*ParetoSolution, references [][]float64, size int, rng *rand.Rand) []*ParetoSolution {
+	if len(front) > size {
+		kept := make([]*ParetoSolution, 0, size)
+		for _, i := range referencePointOrder(front, references, rng)[0][:size] {
+			kept = append(kept, front[i])
+		}
+
+		front = kept
+	}
+
+	indices := make([]int, len(front))
+	for i := range indices {
+		indices[i] = i
+	}
+
+	calculateCrowdingDistance(front, indices)
+
+	return front
+}
+
+// normalizeObjectives translates the objectives by the ideal point and
+// divides them by the intercepts of the hyperplane through the extreme
+// points. If the hyperplane is degenerate, the maxima of the first front
+// are used instead.
+func normalizeObjectives(solutions []*ParetoSolution, first []int) [][]float64 {
+	m := len(solutions[0].ObjectiveValues)
+	ideal := IdealPoint(solutions)
+
+	translated := make([][]float64, len(solutions))
+	for i, sol := range solutions {
+		translated[i] = make([]float64, m)
+		for j, f := range sol.ObjectiveValues {
+			translated[i][j] = f - ideal[j]
+		}
+	}
+
+	// Extreme point of every axis: minimum achievement scalarizing function
+	extremes := make([][]float64, m)
+	for axis := range extremes {
+		best := math.Inf(1)
+		for _, f := range translated {
+			asf := 0.0
+			for j, v := range f {
+				w := 1e-6
+				if j == axis {
+					w = 1
+				}
+
+				asf = math.Max(asf, v/w)
+			}
+
+			if asf < best {
+				best, extremes[axis] = asf, f
+			}
+		}
+	}
+
+	intercepts, ok := hyperplaneIntercepts(extremes)
+	if !ok {
+		intercepts = make([]float64, m)
+		for _, i := range first {
+			for j, v := range translated[i] {
+				intercepts[j] = math.Max(intercepts[j], v)
+			}
+		}
+	}
+
+	for i := range translated {
+		for j := range translated[i] {
+			if intercepts[j] > 1e-10 {
+				translated[i][j] /= intercepts[j]
+			}
+		}
+	}
+
+	return translated
+}
+
+// hyperplaneIntercepts returns the axis intercepts of the hyperplane through
+// points, or false if they are not well defined.
+func hyperplaneIntercepts(points [][]float64) ([]float64, bool) {
+	ones := make([]float64, len(points))
+	for i := range ones {
+		ones[i] = 1
+	}
+
+	normal, ok := solveLinearSystem(points, ones)
+	if !ok {
+		return nil, false
+	}
+
+	intercepts := make([]float64, len(normal))
+	for j, b := range normal {
+		intercepts[j] = 1 / b
+		if math.IsNaN(intercepts[j]) || intercepts[j] <= 1e-6 {
+			return nil, false
+		}
+	}
+
+	return intercepts, true
+}
+
+// solveLinearSystem solves a·x = b by Gaussian elimination with partial
+// pivoting. It reports false for a (numerically) singular matrix.
+func solveLinearSystem(a [][]float64, b []float64) ([]float64, bool) {
+	n := len(b)
+
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = append(append([]float64(nil), a[i]...), b[i])
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(m[row][col]) > math.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+
+		if math.Abs(m[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+
+		m[col], m[pivot] = m[pivot], m[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := m[row][col] / m[col][col]
+			for k := col; k <= n; k++ {
+				m[row][k] -= factor * m[col][k]
+			}
+		}
+	}
+
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := m[row][n]
+		for k := row + 1; k < n; k++ {
+			sum -= m[row][k] * x[k]
+		}
+
+		x[row] = sum / m[row][row]
+	}
+
+	return x, true
+}
+
+// perpendicularDistance returns the distance of f from the line through the
+// origin in direction w.
+func perpendicularDistance(f, w []float64) float64 {
+	scale := dot(f, w) / dot(w, w)
+
+	sum := 0.0
+	for j := range f {
+		d := f[j] - scale*w[j]
+		sum += d * d
+	}
+
+	return math.Sqrt(sum)
+}
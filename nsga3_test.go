@@ -0,0 +1,128 @@
+package mayfly
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for nsga3.go - Reference-point niching (NSGA-III)
+// =============================================================================
+
+func TestDasDennisPoints(t *testing.T) {
+	points := DasDennisPoints(3, 12)
+	if len(points) != 91 {
+		t.Fatalf("Expected 91 points for 3 objectives and 12 divisions, got %d", len(points))
+	}
+
+	for _, p := range points {
+		sum := 0.0
+		for _, v := range p {
+			if v < 0 {
+				t.Fatalf("Point %v has a negative coordinate", p)
+			}
+
+			sum += v
+		}
+
+		if math.Abs(sum-1) > 1e-12 {
+			t.Fatalf("Point %v does not lie on the unit simplex", p)
+		}
+	}
+
+	if got := len(DasDennisPoints(2, 99)); got != 100 {
+		t.Errorf("Expected 100 points for 2 objectives and 99 divisions, got %d", got)
+	}
+
+	if got := dasDennisDivisions(3, 100); got != 12 {
+		t.Errorf("Expected 12 divisions for 100 points in 3 objectives, got %d", got)
+	}
+
+	if got := dasDennisDivisions(10, 5); got != 1 {
+		t.Errorf("Expected at least 1 division, got %d", got)
+	}
+}
+
+func TestReferencePointOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	references := DasDennisPoints(2, 4)
+
+	// A crowded cluster near (0, 1), two extremes and one dominated solution
+	solutions := paretoFront(
+		[]float64{0, 1}, []float64{0.05, 0.95}, []float64{0.1, 0.9},
+		[]float64{1, 0}, []float64{0.5, 0.5}, []float64{1, 1},
+	)
+
+	fronts := referencePointOrder(solutions, references, rng)
+	if len(fronts) != 2 || len(fronts[0]) != 5 || len(fronts[1]) != 1 {
+		t.Fatalf("Unexpected fronts %v", fronts)
+	}
+
+	if solutions[5].Rank != 2 {
+		t.Errorf("Dominated solution has rank %d, expected 2", solutions[5].Rank)
+	}
+
+	// The first three picks cover three different directions
+	picked := map[int]bool{}
+	for _, i := range fronts[0][:3] {
+		picked[i] = true
+	}
+
+	if !picked[3] || !picked[4] {
+		t.Errorf("Isolated solutions should be picked first, got order %v", fronts[0])
+	}
+
+	kept := truncateByReferencePoints(solutions[:5], references, 3, rng)
+	if len(kept) != 3 {
+		t.Fatalf("Expected 3 solutions after truncation, got %d", len(kept))
+	}
+
+	cluster := 0
+	for _, s := range kept {
+		if s.ObjectiveValues[0] < 0.2 {
+			cluster++
+		}
+	}
+
+	if cluster != 1 {
+		t.Errorf("Expected one survivor of the cluster, got %d", cluster)
+	}
+}
+
+func TestNormalizeObjectives(t *testing.T) {
+	// Scaled linear front: intercepts 2 and 10 after translation by the ideal point (1, 0)
+	solutions := paretoFront([]float64{1, 10}, []float64{2, 5}, []float64{3, 0})
+
+	normalized := normalizeObjectives(solutions, []int{0, 1, 2})
+	expected := [][]float64{{0, 1}, {0.5, 0.5}, {1, 0}}
+
+	for i := range expected {
+		for j := range expected[i] {
+			if math.Abs(normalized[i][j]-expected[i][j]) > 1e-9 {
+				t.Fatalf("Got %v, expected %v", normalized, expected)
+			}
+		}
+	}
+}
+
+func TestOptimizeMultiNSGA3(t *testing.T) {
+	config := newMultiTestConfig(12, 4)
+	config.MultiObjectiveMode = MultiObjectiveNSGA3
+	config.ArchiveSize = 91
+	config.ReferenceDivisions = 12
+
+	result, err := OptimizeMulti(config, DTLZ2(3))
+	if err != nil {
+		t.Fatalf("OptimizeMulti failed: %v", err)
+	}
+
+	if len(result.Front) > 91 {
+		t.Errorf("Front size %d exceeds ArchiveSize", len(result.Front))
+	}
+
+	trueFront := DTLZProblems(3)[1].TrueFront(1000)
+	if igd := CalculateIGD(result.Front, trueFront); igd > 0.15 {
+		t.Errorf("IGD %.4f to the true DTLZ2 front is too large", igd)
+	}
+}
@@ -0,0 +1,88 @@
+// Package mayfly - OLCE-MA (Orthogonal Learning and Chaotic Exploitation MA)
+//
+// Implements the OLCE-MA variant as a pipeline stage.
+//
+// Reference:
+// Zhou, D., Kang, Z., Su, X., & Yang, C. (2022). An enhanced Mayfly
+// optimization algorithm based on orthogonal learning and chaotic exploitation
+// strategy. International Journal of Machine Learning and Cybernetics, 13,
+// 3625-3643.
+// DOI: 10.1007/s13042-022-01617-4
+//
+// OLCE-MA enhances the standard Mayfly Algorithm with:
+// - Orthogonal learning on the elite males (see orthogonal.go)
+// - Chaotic perturbation of all offspring using a logistic map (see chaos.go)
+package mayfly
+
+// olceStage applies orthogonal learning to the elite males and chaotic
+// exploitation to every offspring.
+type olceStage struct {
+	chaosMap *LogisticMap
+}
+
+func (o *olceStage) Name() string {
+	return "olce"
+}
+
+// Initialize seeds the chaotic map from the run's random number generator.
+func (o *olceStage) Initialize(s *State) error {
+	o.chaosMap = NewLogisticMap(s.Rand.Float64())
+	return nil
+}
+
+// Enhance applies orthogonal learning to the top 20% of the males.
+func (o *olceStage) Enhance(s *State) {
+	config := s.Config
+
+	// Prepare bounds vectors for orthogonal learning
+	lb := make([]float64, config.ProblemSize)
+	ub := make([]float64, config.ProblemSize)
+
+	for j := 0; j < config.ProblemSize; j++ {
+		lb[j] = config.LowerBound
+		ub[j] = config.UpperBound
+	}
+
+	ApplyOrthogonalLearningToElite(
+		s.Males,
+		0.2, // Top 20%
+		s.GlobalBest.Position,
+		config.OrthogonalFactor,
+		lb, ub,
+		s.Evaluate,
+		s.Rand,
+	)
+
+	numElite := int(float64(len(s.Males)) * 0.2)
+	if numElite < 1 {
+		numElite = 1
+	}
+
+	// Update global best if orthogonal learning found better solution
+	for i := 0; i < numElite; i++ {
+		s.UpdateGlobalBest(s.Males[i].Position, s.Males[i].Cost)
+	}
+
+	// Re-sort after orthogonal learning
+	sortMayflies(s.Males)
+}
+
+// ModifyOffspring applies a chaotic perturbation to an offspring position.
+func (o *olceStage) ModifyOffspring(s *State, position []float64) {
+	config := s.Config
+
+	for j := range position {
+		chaosValue := o.chaosMap.Next()
+		perturbation := config.ChaosFactor * (chaosValue - 0.5) * (config.UpperBound - config.LowerBound)
+		position[j] += perturbation
+
+		// Apply bounds
+		if position[j] < config.LowerBound {
+			position[j] = config.LowerBound
+		}
+
+		if position[j] > config.UpperBound {
+			position[j] = config.UpperBound
+		}
+	}
+}
@@ -60,3 +60,21 @@ func MutateGaussian(x []float64, mu, lowerBound, upperBound float64, rng *rand.R
 func Mutate(x []float64, mu, lowerBound, upperBound float64, rng *rand.Rand) []float64 {
 	return MutateGaussian(x, mu, lowerBound, upperBound, rng)
 }
+
+// ArithmeticCrossover is the default CrossoverOperator. It blends the
+// parents per dimension with a uniform random weight (see Crossover).
+type ArithmeticCrossover struct{}
+
+// Cross implements CrossoverOperator.
+func (ArithmeticCrossover) Cross(s *State, p1, p2 []float64) ([]float64, []float64) {
+	return Crossover(p1, p2, s.Config.LowerBound, s.Config.UpperBound, s.Rand)
+}
+
+// GaussianMutation is the default MutationOperator. It perturbs a fraction
+// Mu of the dimensions with Gaussian noise (see MutateGaussian).
+type GaussianMutation struct{}
+
+// Mutate implements MutationOperator.
+func (GaussianMutation) Mutate(s *State, x []float64) []float64 {
+	return Mutate(x, s.Config.Mu, s.Config.LowerBound, s.Config.UpperBound, s.Rand)
+}
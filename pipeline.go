@@ -0,0 +1,650 @@
+// Package mayfly - Operator Pipeline
+//
+// Implements the stage-based main loop that drives every Mayfly variant.
+//
+// An iteration is split into a fixed sequence of phases:
+//  1. Movement: female and male position updates (Mover)
+//  2. Post-sort enhancement: elite refinement after sorting (Enhancer)
+//  3. Mating and mutation: offspring generation (CrossoverOperator,
+//     MutationOperator, OffspringModifier)
+//  4. Post-selection: work on the truncated populations (PostSelector)
+//  5. Iteration end: bookkeeping such as cooling schedules (IterationEnder)
+//
+// A variant is expressed as a Mover, a pair of genetic operators and a list
+// of Stages. Stages opt into phases by implementing the corresponding hook
+// interfaces, so several enhancements can be combined in one Pipeline and
+// custom stages can be added without editing library code.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// State is the mutable optimizer state shared by all pipeline stages.
+type State struct {
+	Config       *Config
+	Rand         *rand.Rand
+	Males        []*Mayfly
+	Females      []*Mayfly
+	Offspring    []*Mayfly
+	GlobalBest   Best
+	BestSolution []float64
+	FuncEvals    int
+	GradEvals    int
+	Iteration    int
+
+	// Damped movement coefficients for the current iteration.
+	G     float64
+	Dance float64
+	FL    float64
+
+	niches     *nicheController      // Set in niching mode
+	surrogate  *surrogateController  // Set in surrogate-assisted mode
+	adaptation *adaptationController // Set in adaptive parameter mode
+}
+
+// Evaluate computes the objective value of a position and counts the evaluation.
+func (s *State) Evaluate(position []float64) float64 {
+	s.FuncEvals++
+	cost := s.Config.ObjectiveFunc(position)
+
+	if s.surrogate != nil {
+		s.surrogate.record(position, cost)
+	}
+
+	return cost
+}
+
+// Gradient computes the gradient of the objective at a position with
+// Config.Gradient and counts the call. It returns a copy of the gradient
+// with non-finite components set to 0, so the slice returned by
+// Config.Gradient is left unchanged.
+func (s *State) Gradient(position []float64) []float64 {
+	s.GradEvals++
+	g := append([]float64(nil), s.Config.Gradient(position)...)
+
+	for j, v := range g {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			g[j] = 0
+		}
+	}
+
+	return g
+}
+
+// UpdateGlobalBest replaces the global best if cost improves on it.
+// Returns true if the global best was updated.
+func (s *State) UpdateGlobalBest(position []float64, cost float64) bool {
+	if cost >= s.GlobalBest.Cost {
+		return false
+	}
+
+	s.GlobalBest.Cost = cost
+	copy(s.GlobalBest.Position, position)
+
+	return true
+}
+
+// socialBest returns the best solution a male at position is attracted to:
+// the global best, or in niching mode the nearest niche leader.
+func (s *State) socialBest(position []float64) Best {
+	if s.niches == nil || len(s.niches.leaders) == 0 {
+		return s.GlobalBest
+	}
+
+	return s.niches.leader(position)
+}
+
+// Progress returns the fraction of the iteration budget that has been used.
+func (s *State) Progress() float64 {
+	return float64(s.Iteration) / float64(s.Config.MaxIterations)
+}
+
+// Stage is a named pipeline component. A Stage takes part in every phase
+// whose hook interface it implements (Initializer, Enhancer, PostSelector,
+// IterationEnder, OffspringModifier).
+type Stage interface {
+	Name() string
+}
+
+// Initializer is called once after the initial populations have been evaluated.
+type Initializer interface {
+	Initialize(s *State) error
+}
+
+// Enhancer is called after the populations have been sorted by cost.
+// Enhancers typically refine the elite males.
+type Enhancer interface {
+	Enhance(s *State)
+}
+
+// OffspringModifier is called for every crossover offspring and mutant
+// before it is evaluated.
+type OffspringModifier interface {
+	ModifyOffspring(s *State, position []float64)
+}
+
+// PostSelector is called after offspring have been merged and the
+// populations truncated to their configured sizes.
+type PostSelector interface {
+	PostSelect(s *State)
+}
+
+// IterationEnder is called at the end of every iteration, after the
+// convergence history has been recorded.
+type IterationEnder interface {
+	EndIteration(s *State)
+}
+
+// Mover updates the positions and costs of the female and male populations.
+type Mover interface {
+	MoveFemales(s *State)
+	MoveMales(s *State)
+}
+
+// CrossoverOperator combines two parent positions into two offspring positions.
+type CrossoverOperator interface {
+	Cross(s *State, p1, p2 []float64) ([]float64, []float64)
+}
+
+// MutationOperator produces a mutated copy of a parent position.
+type MutationOperator interface {
+	Mutate(s *State, x []float64) []float64
+}
+
+// Pipeline describes one complete Mayfly variant as a set of stages.
+// Selection and MutationParents are optional; when nil the original
+// rank-aligned pairing and random mutation parents are used.
+type Pipeline struct {
+	Mover           Mover
+	Crossover       CrossoverOperator
+	Mutation        MutationOperator
+	Selection       ParentSelector
+	MutationParents MutationParentSelector
+	Stages          []Stage
+}
+
+// NewPipeline assembles the pipeline described by the variant flags of config.
+// The returned pipeline can be extended with custom stages before it is
+// passed to OptimizeWithPipeline.
+func NewPipeline(config *Config) *Pipeline {
+	p := &Pipeline{
+		Mover:     &standardMover{},
+		Crossover: ArithmeticCrossover{},
+		Mutation:  GaussianMutation{},
+	}
+
+	switch {
+	case config.UseAOBLMOA:
+		p.Mover = &aoblmoaMover{}
+	case config.UseEOBBMA:
+		p.Mover = &eobbmaMover{}
+	case config.UseMPMA:
+		p.Mover = &mpmaMover{}
+	}
+
+	if crossover, err := NewCrossoverOperator(config.CrossoverType); err == nil {
+		p.Crossover = crossover
+	}
+
+	if selection, err := NewParentSelector(config.ParentSelection); err == nil {
+		p.Selection = selection
+	}
+
+	if parents, err := NewMutationParentSelector(config.MutationParentSelection); err == nil {
+		p.MutationParents = parents
+	}
+
+	if config.UseGSASMA {
+		p.Mutation = HybridMutation{}
+	}
+
+	if config.MutationType != "" {
+		if mutation, err := NewMutationOperator(config.MutationType); err == nil {
+			p.Mutation = mutation
+		}
+	}
+
+	// Within each phase, stages run in the order they appear here.
+	if config.UseOLCE {
+		p.Stages = append(p.Stages, &olceStage{})
+	}
+
+	if config.UseEOBBMA {
+		p.Stages = append(p.Stages, &eobbmaStage{})
+	}
+
+	if config.UseDESMA {
+		p.Stages = append(p.Stages, &desmaStage{})
+	}
+
+	if config.UseGSASMA {
+		p.Stages = append(p.Stages, &gsasmaStage{})
+	}
+
+	if config.UseAOBLMOA {
+		p.Stages = append(p.Stages, &aoblmoaStage{})
+	}
+
+	return p
+}
+
+// AddStage appends stages to the pipeline and returns it for chaining.
+func (p *Pipeline) AddStage(stages ...Stage) *Pipeline {
+	p.Stages = append(p.Stages, stages...)
+	return p
+}
+
+// OptimizeWithPipeline runs the Mayfly main loop using the given pipeline.
+// The config is validated in the same way as by Optimize.
+func OptimizeWithPipeline(config *Config, pipeline *Pipeline) (*Result, error) {
+	if err := validateOptimizeConfig(config); err != nil {
+		return nil, err
+	}
+
+	if pipeline == nil {
+		return nil, fmt.Errorf("pipeline cannot be nil")
+	}
+
+	if pipeline.Mover == nil || pipeline.Crossover == nil || pipeline.Mutation == nil {
+		return nil, fmt.Errorf("pipeline requires a Mover, Crossover and Mutation operator")
+	}
+
+	return runPipeline(config, pipeline)
+}
+
+// newState initializes parameters, the random number generator and the
+// evaluated initial populations.
+func newState(config *Config) (*State, int64) {
+	rng, seed := prepareRun(config)
+
+	s := &State{
+		Config: config,
+		Rand:   rng,
+		GlobalBest: Best{
+			Position: make([]float64, config.ProblemSize),
+			Cost:     math.Inf(1),
+		},
+		BestSolution: make([]float64, config.MaxIterations),
+		G:            config.G,
+		Dance:        config.Dance,
+		FL:           config.FL,
+	}
+
+	if config.UseSurrogate {
+		s.surrogate = newSurrogateController(config)
+	}
+
+	if config.AdaptiveParameters {
+		s.adaptation = newAdaptationController(config)
+	}
+
+	s.initPopulations()
+
+	return s, seed
+}
+
+// prepareRun fills in the derived parameters of config and returns the
+// random number generator of the run and its seed.
+func prepareRun(config *Config) (*rand.Rand, int64) {
+	// Initialize parameters
+	if config.NM == 0 {
+		config.NM = int(math.Round(0.05 * float64(config.NPop)))
+	}
+
+	if config.VelMax == 0 {
+		config.VelMax = 0.1 * (config.UpperBound - config.LowerBound)
+		config.VelMin = -config.VelMax
+	}
+
+	// Initialize random number generator if not provided
+	rng := config.Rand
+	seed := int64(0)
+	if rng == nil {
+		seed = time.Now().UnixNano()
+		rng = rand.New(rand.NewSource(seed))
+	} else {
+		// Try to extract seed from the random source if possible
+		// This is a best-effort attempt for reproducibility tracking
+		seed = time.Now().UnixNano() // Fallback if we can't determine
+	}
+
+	return rng, seed
+}
+
+// initPopulations creates and evaluates uniformly random male and female
+// populations of the configured sizes. Only the males update the global best.
+func (s *State) initPopulations() {
+	config := s.Config
+	s.Males = make([]*Mayfly, config.NPop)
+	s.Females = make([]*Mayfly, config.NPopF)
+
+	// Initialize male population
+	for i := 0; i < config.NPop; i++ {
+		s.Males[i] = newMayfly(config.ProblemSize)
+		s.Males[i].Position = unifrndVec(config.LowerBound, config.UpperBound, config.ProblemSize, s.Rand)
+		s.Males[i].Cost = s.evaluateSanitized(s.Males[i].Position)
+
+		// Update personal best
+		copy(s.Males[i].Best.Position, s.Males[i].Position)
+		s.Males[i].Best.Cost = s.Males[i].Cost
+
+		// Update global best
+		s.UpdateGlobalBest(s.Males[i].Best.Position, s.Males[i].Best.Cost)
+	}
+
+	// Initialize female population
+	for i := 0; i < config.NPopF; i++ {
+		s.Females[i] = newMayfly(config.ProblemSize)
+		s.Females[i].Position = unifrndVec(config.LowerBound, config.UpperBound, config.ProblemSize, s.Rand)
+		s.Females[i].Cost = s.evaluateSanitized(s.Females[i].Position)
+	}
+}
+
+// evaluateSanitized sanitizes position, evaluates it and returns a finite cost.
+func (s *State) evaluateSanitized(position []float64) float64 {
+	sanitizeVec(position, s.Config.LowerBound, s.Config.UpperBound, s.Rand)
+	return sanitizeCost(s.Evaluate(position))
+}
+
+// runPipeline executes the main optimization loop.
+func runPipeline(config *Config, p *Pipeline) (*Result, error) {
+	r, err := newPipelineRun(config, p)
+	if err != nil {
+		return nil, err
+	}
+
+	for it := 0; it < config.MaxIterations; it++ {
+		r.iterate(it)
+	}
+
+	return r.result(), nil
+}
+
+// pipelineRun holds one run of a pipeline so that it can be advanced one
+// iteration at a time (e.g. by the island model).
+type pipelineRun struct {
+	config   *Config
+	pipeline *Pipeline
+	state    *State
+	seed     int64
+	restarts *restartController
+	niching  *nicheController
+	polish   *localSearchController
+}
+
+// newPipelineRun initializes the state and the stages of a run.
+func newPipelineRun(config *Config, p *Pipeline) (*pipelineRun, error) {
+	s, seed := newState(config)
+	r := &pipelineRun{config: config, pipeline: p, state: s, seed: seed}
+
+	if restartsEnabled(config) {
+		r.restarts = newRestartController(s)
+	}
+
+	if config.LocalSearch != "" {
+		r.polish = newLocalSearchController(config)
+	}
+
+	if nichingEnabled(config) {
+		r.niching = newNicheController(config)
+		r.niching.update(s)
+		s.niches = r.niching
+	}
+
+	for _, stage := range p.Stages {
+		if init, ok := stage.(Initializer); ok {
+			if err := init.Initialize(s); err != nil {
+				return nil, fmt.Errorf("stage %s: %w", stage.Name(), err)
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// iterate performs main loop iteration it.
+func (r *pipelineRun) iterate(it int) {
+	s, p, config := r.state, r.pipeline, r.config
+	s.Iteration = it
+
+	p.Mover.MoveFemales(s)
+	if s.surrogate != nil {
+		s.surrogate.settleMoves(s, false)
+	}
+
+	p.Mover.MoveMales(s)
+	if s.surrogate != nil {
+		s.surrogate.settleMoves(s, true)
+	}
+
+	if s.adaptation != nil {
+		s.adaptation.update()
+	}
+
+	// Sort populations by cost
+	sortMayflies(s.Males)
+	sortMayflies(s.Females)
+
+	for _, stage := range p.Stages {
+		if enhancer, ok := stage.(Enhancer); ok {
+			enhancer.Enhance(s)
+		}
+	}
+
+	p.breed(s)
+
+	// Merge offspring into populations
+	split := len(s.Offspring) / 2
+	s.Males = append(s.Males, s.Offspring[:split]...)
+	s.Females = append(s.Females, s.Offspring[split:]...)
+
+	// Sort and keep best
+	sortMayflies(s.Males)
+	sortMayflies(s.Females)
+
+	if r.niching != nil {
+		s.Males = r.niching.survivors(s.Males, s.Config.NPop)
+		s.Females = r.niching.survivors(s.Females, s.Config.NPopF)
+	} else {
+		s.Males = s.Males[:s.Config.NPop]
+		s.Females = s.Females[:s.Config.NPopF]
+	}
+
+	for _, stage := range p.Stages {
+		if selector, ok := stage.(PostSelector); ok {
+			selector.PostSelect(s)
+		}
+	}
+
+	if r.niching != nil {
+		r.niching.update(s)
+	}
+
+	if r.polish != nil && r.polish.due(it, config.MaxIterations) {
+		r.polish.polish(s)
+	}
+
+	s.BestSolution[it] = s.GlobalBest.Cost
+
+	for _, stage := range p.Stages {
+		if ender, ok := stage.(IterationEnder); ok {
+			ender.EndIteration(s)
+		}
+	}
+
+	// Update parameters
+	s.G *= config.GDamp
+	s.Dance *= config.DanceDamp
+	s.FL *= config.FLDamp
+
+	if r.restarts != nil {
+		if reason := r.restarts.check(s); reason != "" {
+			r.restarts.restart(s, p, reason)
+		}
+	}
+}
+
+// result returns the Result of the run so far.
+func (r *pipelineRun) result() *Result {
+	result := &Result{
+		GlobalBest:     r.state.GlobalBest,
+		BestSolution:   r.state.BestSolution,
+		FuncEvalCount:  r.state.FuncEvals,
+		IterationCount: r.config.MaxIterations,
+		Seed:           r.seed,
+	}
+
+	if r.restarts != nil {
+		result.Restarts = r.restarts.events
+	}
+
+	if r.niching != nil {
+		result.Optima = r.niching.optima()
+	}
+
+	if r.state.surrogate != nil {
+		result.SurrogateSkipped = r.state.surrogate.skipped
+	}
+
+	if r.polish != nil {
+		result.LocalSearchEvals = r.polish.evals
+	}
+
+	result.GradientEvals = r.state.GradEvals
+
+	if r.state.adaptation != nil {
+		result.Parameters = r.state.adaptation.trace
+	}
+
+	return result
+}
+
+// breed performs mating and mutation, leaving the evaluated offspring in s.Offspring.
+func (p *Pipeline) breed(s *State) {
+	config := s.Config
+	s.Offspring = make([]*Mayfly, 0, config.NC+config.NM)
+
+	var selection ParentSelector = RankSelection{}
+	if p.Selection != nil {
+		selection = p.Selection
+	}
+
+	var mutationParents MutationParentSelector = RandomMutationParent{}
+	if p.MutationParents != nil {
+		mutationParents = p.MutationParents
+	}
+
+	// Mating - Create offspring from the selected males and females
+	males, females := selection.SelectParents(s, config.NC/2)
+
+	for k := range males {
+		male, female := males[k], females[k]
+		off1Pos, off2Pos := p.Crossover.Cross(s, male.Position, female.Position)
+		off1, off2 := p.newOffspring(s, off1Pos), p.newOffspring(s, off2Pos)
+
+		// Offspring inherit the mean step size of their parents
+		off1.Sigma = inheritedSigma(male, female)
+		off2.Sigma = off1.Sigma
+
+		s.Offspring = append(s.Offspring, off1, off2)
+	}
+
+	// Mutation - Perturb the selected parents
+	pool := mutationPool(s)
+	for k := 0; k < config.NM; k++ {
+		parent := mutationParents.SelectMutationParent(pool)
+		s.Offspring = append(s.Offspring, p.mutate(s, parent))
+	}
+
+	if s.surrogate != nil {
+		s.Offspring = screenOffspring(s, s.Offspring)
+	}
+}
+
+// mutationPool returns the state that mutation parents are selected from.
+// In surrogate-assisted mode the crossover offspring have no cost yet, so
+// the selectors see a copy of s whose offspring are the evaluated males and
+// females.
+func mutationPool(s *State) *State {
+	if s.surrogate == nil {
+		return s
+	}
+
+	pool := *s
+	pool.Offspring = append(append([]*Mayfly(nil), s.Males...), s.Females...)
+
+	return &pool
+}
+
+// screenOffspring evaluates the offspring that the surrogate selects and
+// discards the others. The candidate proposed by the model is added.
+func screenOffspring(s *State, offspring []*Mayfly) []*Mayfly {
+	positions := make([][]float64, len(offspring))
+	for i, off := range offspring {
+		positions[i] = off.Position
+	}
+
+	selected := s.surrogate.screen(s, positions, nil)
+	evaluated := make([]*Mayfly, len(selected))
+
+	for k, i := range selected {
+		evaluateOffspring(s, offspring[i])
+		evaluated[k] = offspring[i]
+	}
+
+	if position := s.surrogate.infillPoint(s); position != nil {
+		off := newMayfly(s.Config.ProblemSize)
+		copy(off.Position, position)
+		evaluateOffspring(s, off)
+		evaluated = append(evaluated, off)
+	}
+
+	return evaluated
+}
+
+// mutate creates an evaluated mutant of parent. Step-size adapting operators
+// receive the parent's step size and pass the adapted one to the mutant.
+func (p *Pipeline) mutate(s *State, parent *Mayfly) *Mayfly {
+	if adaptive, ok := p.Mutation.(StepSizeMutationOperator); ok {
+		position, sigma := adaptive.MutateWithStepSize(s, parent.Position, parent.Sigma)
+		mutant := p.newOffspring(s, position)
+		mutant.Sigma = sigma
+
+		return mutant
+	}
+
+	return p.newOffspring(s, p.Mutation.Mutate(s, parent.Position))
+}
+
+// newOffspring applies the offspring modifiers to position, evaluates it and
+// returns the resulting mayfly. In surrogate-assisted mode the evaluation is
+// left to screenOffspring.
+func (p *Pipeline) newOffspring(s *State, position []float64) *Mayfly {
+	off := newMayfly(s.Config.ProblemSize)
+	copy(off.Position, position)
+
+	for _, stage := range p.Stages {
+		if modifier, ok := stage.(OffspringModifier); ok {
+			modifier.ModifyOffspring(s, off.Position)
+		}
+	}
+
+	if s.surrogate == nil {
+		evaluateOffspring(s, off)
+	}
+
+	return off
+}
+
+// evaluateOffspring evaluates an offspring and sets its personal best.
+func evaluateOffspring(s *State, off *Mayfly) {
+	off.Cost = s.Evaluate(off.Position)
+	s.UpdateGlobalBest(off.Position, off.Cost)
+
+	copy(off.Best.Position, off.Position)
+	off.Best.Cost = off.Cost
+}
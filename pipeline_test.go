@@ -0,0 +1,224 @@
+package mayfly
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for pipeline.go - Stage-based main loop
+// =============================================================================
+
+// recordingStage records which hooks were invoked and how often.
+type recordingStage struct {
+	calls map[string]int
+}
+
+func newRecordingStage() *recordingStage {
+	return &recordingStage{calls: make(map[string]int)}
+}
+
+func (r *recordingStage) Name() string { return "recording" }
+
+func (r *recordingStage) Initialize(s *State) error {
+	r.calls["init"]++
+	return nil
+}
+
+func (r *recordingStage) Enhance(s *State)      { r.calls["enhance"]++ }
+func (r *recordingStage) PostSelect(s *State)   { r.calls["post"]++ }
+func (r *recordingStage) EndIteration(s *State) { r.calls["end"]++ }
+
+func (r *recordingStage) ModifyOffspring(s *State, position []float64) {
+	r.calls["offspring"]++
+}
+
+// newTestConfig returns a seeded default configuration that minimizes fn
+// in problemSize dimensions on [-bound, bound] for iterations iterations.
+func newTestConfig(fn ObjectiveFunction, problemSize int, bound float64, iterations int, seed int64) *Config {
+	config := NewDefaultConfig()
+	config.ObjectiveFunc = fn
+	config.ProblemSize = problemSize
+	config.LowerBound = -bound
+	config.UpperBound = bound
+	config.MaxIterations = iterations
+	config.Rand = rand.New(rand.NewSource(seed))
+
+	return config
+}
+
+func TestNewPipelineComposition(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+		stages []string
+	}{
+		{"MA", NewDefaultConfig(), nil},
+		{"DESMA", NewDESMAConfig(), []string{"desma"}},
+		{"OLCE", NewOLCEConfig(), []string{"olce"}},
+		{"EOBBMA", NewEOBBMAConfig(), []string{"eobbma"}},
+		{"GSASMA", NewGSASMAConfig(), []string{"gsasma"}},
+		{"MPMA", NewMPMAConfig(), nil},
+		{"AOBLMOA", NewAOBLMOAConfig(), []string{"aoblmoa"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPipeline(tt.config)
+
+			if len(p.Stages) != len(tt.stages) {
+				t.Fatalf("Expected %d stages, got %d", len(tt.stages), len(p.Stages))
+			}
+
+			for i, name := range tt.stages {
+				if p.Stages[i].Name() != name {
+					t.Errorf("Stage %d: expected %s, got %s", i, name, p.Stages[i].Name())
+				}
+			}
+		})
+	}
+}
+
+func TestPipelineHookCounts(t *testing.T) {
+	config := newTestConfig(Sphere, 5, 10, 30, 1)
+	config.NC = 10
+	config.NM = 2
+
+	recorder := newRecordingStage()
+	pipeline := NewPipeline(config).AddStage(recorder)
+
+	result, err := OptimizeWithPipeline(config, pipeline)
+	if err != nil {
+		t.Fatalf("OptimizeWithPipeline failed: %v", err)
+	}
+
+	iterations := config.MaxIterations
+
+	if recorder.calls["init"] != 1 {
+		t.Errorf("Initialize called %d times, want 1", recorder.calls["init"])
+	}
+
+	for _, hook := range []string{"enhance", "post", "end"} {
+		if recorder.calls[hook] != iterations {
+			t.Errorf("%s called %d times, want %d", hook, recorder.calls[hook], iterations)
+		}
+	}
+
+	wantOffspring := iterations * (config.NC + config.NM)
+	if recorder.calls["offspring"] != wantOffspring {
+		t.Errorf("ModifyOffspring called %d times, want %d", recorder.calls["offspring"], wantOffspring)
+	}
+
+	wantEvals := config.NPop + config.NPopF + iterations*(config.NPop+config.NPopF+config.NC+config.NM)
+	if result.FuncEvalCount != wantEvals {
+		t.Errorf("FuncEvalCount = %d, want %d", result.FuncEvalCount, wantEvals)
+	}
+}
+
+func TestOptimizeMatchesDefaultPipeline(t *testing.T) {
+	for _, newConfig := range []func() *Config{NewDefaultConfig, NewDESMAConfig, NewOLCEConfig, NewGSASMAConfig} {
+		c1 := newConfig()
+		c2 := newConfig()
+
+		for _, c := range []*Config{c1, c2} {
+			c.ObjectiveFunc = Rastrigin
+			c.ProblemSize = 5
+			c.LowerBound = -5.12
+			c.UpperBound = 5.12
+			c.MaxIterations = 40
+			c.Rand = rand.New(rand.NewSource(99))
+		}
+
+		r1, err := Optimize(c1)
+		if err != nil {
+			t.Fatalf("Optimize failed: %v", err)
+		}
+
+		r2, err := OptimizeWithPipeline(c2, NewPipeline(c2))
+		if err != nil {
+			t.Fatalf("OptimizeWithPipeline failed: %v", err)
+		}
+
+		if r1.GlobalBest.Cost != r2.GlobalBest.Cost || r1.FuncEvalCount != r2.FuncEvalCount {
+			t.Errorf("Optimize and default pipeline diverged: %v/%d vs %v/%d",
+				r1.GlobalBest.Cost, r1.FuncEvalCount, r2.GlobalBest.Cost, r2.FuncEvalCount)
+		}
+	}
+}
+
+// constantMutation is a custom operator that always returns the origin.
+type constantMutation struct{}
+
+func (constantMutation) Mutate(s *State, x []float64) []float64 {
+	return make([]float64, len(x))
+}
+
+func TestPipelineCustomOperator(t *testing.T) {
+	config := newTestConfig(Sphere, 5, 10, 30, 5)
+	config.NM = 1
+
+	pipeline := NewPipeline(config)
+	pipeline.Mutation = constantMutation{}
+
+	result, err := OptimizeWithPipeline(config, pipeline)
+	if err != nil {
+		t.Fatalf("OptimizeWithPipeline failed: %v", err)
+	}
+
+	// The origin is evaluated every iteration, so the optimum is found.
+	if result.GlobalBest.Cost != 0 {
+		t.Errorf("Expected custom mutation to reach the optimum, got %v", result.GlobalBest.Cost)
+	}
+}
+
+// failingStage returns an error during initialization.
+type failingStage struct{}
+
+func (failingStage) Name() string { return "failing" }
+
+func (failingStage) Initialize(s *State) error {
+	return errTestStage
+}
+
+var errTestStage = errors.New("stage failure")
+
+func TestOptimizeWithPipelineErrors(t *testing.T) {
+	config := newTestConfig(Sphere, 5, 10, 30, 3)
+
+	if _, err := OptimizeWithPipeline(config, nil); err == nil {
+		t.Error("Expected error for nil pipeline")
+	}
+
+	if _, err := OptimizeWithPipeline(config, &Pipeline{}); err == nil {
+		t.Error("Expected error for pipeline without operators")
+	}
+
+	if _, err := OptimizeWithPipeline(nil, NewPipeline(config)); err == nil {
+		t.Error("Expected error for nil config")
+	}
+
+	_, err := OptimizeWithPipeline(config, NewPipeline(config).AddStage(failingStage{}))
+	if !errors.Is(err, errTestStage) {
+		t.Errorf("Expected wrapped stage error, got %v", err)
+	}
+}
+
+func TestStateUpdateGlobalBest(t *testing.T) {
+	s := &State{
+		GlobalBest: Best{Position: make([]float64, 2), Cost: math.Inf(1)},
+	}
+
+	if !s.UpdateGlobalBest([]float64{1, 2}, 5) {
+		t.Error("Expected update for first finite cost")
+	}
+
+	if s.UpdateGlobalBest([]float64{3, 4}, 6) {
+		t.Error("Expected no update for worse cost")
+	}
+
+	if s.GlobalBest.Position[0] != 1 || s.GlobalBest.Cost != 5 {
+		t.Errorf("Unexpected global best %v", s.GlobalBest)
+	}
+}
@@ -0,0 +1,320 @@
+// Package mayfly - Restart Strategies
+//
+// Implements automatic restarts when a run stagnates or its population
+// collapses into a single basin.
+//
+// Config.RestartStrategy selects how population sizes evolve:
+//   - "restart": every run uses the initial population sizes
+//   - "ipop": every restart multiplies the population sizes by
+//     RestartPopulationFactor (Auger & Hansen, 2005)
+//   - "bipop": alternates a large regime with increasing sizes (as IPOP) and
+//     a small regime with random sizes between the initial and the current
+//     large size, keeping the evaluations spent in both regimes balanced
+//     (Hansen, 2009)
+//
+// A restart is triggered at the end of an iteration when the best cost of
+// the current run has not improved for RestartStagnation iterations, or when
+// the population diversity (mean per-dimension standard deviation divided by
+// the search range) drops below RestartDiversity.
+//
+// On restart the populations are re-initialized uniformly at random and the
+// movement coefficients are reset. The global best, the iteration budget and
+// the evaluation count carry over, and stages implementing Restarter reset
+// their per-run state. Every restart is recorded in Result.Restarts.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+)
+
+// Restart strategy names accepted by Config.RestartStrategy.
+const (
+	RestartStrategyNone    = "none"
+	RestartStrategyRestart = "restart"
+	RestartStrategyIPOP    = "ipop"
+	RestartStrategyBIPOP   = "bipop"
+)
+
+// Limits for an unset RestartStagnation, MaxRestarts or RestartMaxPopulation,
+// shared with IPOP-CMA-ES. The population cap allows four doublings.
+const (
+	defaultRestartStagnation    = 50
+	defaultMaxRestarts          = 9
+	defaultRestartMaxPopulation = 16 // Multiple of the initial NPop
+)
+
+// restartImprovementTolerance is the relative improvement required to reset
+// the stagnation counter.
+const restartImprovementTolerance = 1e-12
+
+// RestartEvent records one restart.
+type RestartEvent struct {
+	Iteration   int     // Iteration after which the restart happened
+	FuncEvals   int     // Evaluations used when the restart happened
+	Reason      string  // "stagnation" or "diversity"
+	RunBestCost float64 // Best cost found by the run that ended
+	BestCost    float64 // Global best cost carried into the new run
+	Regime      string  // "restart", "large" or "small"
+	NPop        int     // Male population size of the new run
+	NPopF       int     // Female population size of the new run
+}
+
+// Restarter is implemented by stages that keep per-run state. Restart is
+// called after the populations have been re-initialized.
+type Restarter interface {
+	Restart(s *State)
+}
+
+// validateRestartConfig checks the restart strategy and its parameters.
+func validateRestartConfig(config *Config) error {
+	switch config.RestartStrategy {
+	case "", RestartStrategyNone, RestartStrategyRestart, RestartStrategyIPOP, RestartStrategyBIPOP:
+	default:
+		return fmt.Errorf("unknown restart strategy '%s' (expected none, restart, ipop or bipop)", config.RestartStrategy)
+	}
+
+	if config.RestartStagnation < 0 {
+		return fmt.Errorf("RestartStagnation must be non-negative, got %d", config.RestartStagnation)
+	}
+
+	if config.RestartDiversity < 0 {
+		return fmt.Errorf("RestartDiversity must be non-negative, got %v", config.RestartDiversity)
+	}
+
+	growing := config.RestartStrategy == RestartStrategyIPOP || config.RestartStrategy == RestartStrategyBIPOP
+	if growing && config.RestartPopulationFactor < 1 {
+		return fmt.Errorf("RestartPopulationFactor must be at least 1, got %v", config.RestartPopulationFactor)
+	}
+
+	if config.MaxRestarts < 0 {
+		return fmt.Errorf("MaxRestarts must be non-negative, got %d", config.MaxRestarts)
+	}
+
+	if config.RestartMaxPopulation < 0 {
+		return fmt.Errorf("RestartMaxPopulation must be non-negative, got %d", config.RestartMaxPopulation)
+	}
+
+	return nil
+}
+
+// restartsEnabled reports whether config requests automatic restarts.
+func restartsEnabled(config *Config) bool {
+	return config.RestartStrategy != "" && config.RestartStrategy != RestartStrategyNone
+}
+
+// populationDiversity returns the mean per-dimension standard deviation of
+// the male and female positions divided by the search range.
+func populationDiversity(s *State) float64 {
+	n := len(s.Males) + len(s.Females)
+	if n == 0 {
+		return 0
+	}
+
+	size := s.Config.ProblemSize
+	total := 0.0
+
+	for j := 0; j < size; j++ {
+		mean := 0.0
+		for _, population := range [][]*Mayfly{s.Males, s.Females} {
+			for _, m := range population {
+				mean += m.Position[j]
+			}
+		}
+
+		mean /= float64(n)
+
+		variance := 0.0
+		for _, population := range [][]*Mayfly{s.Males, s.Females} {
+			for _, m := range population {
+				d := m.Position[j] - mean
+				variance += d * d
+			}
+		}
+
+		total += math.Sqrt(variance / float64(n))
+	}
+
+	return total / float64(size) / (s.Config.UpperBound - s.Config.LowerBound)
+}
+
+// restartController detects stagnation and restarts the run.
+type restartController struct {
+	base        Config // Population parameters of the first run
+	stagnation  int
+	diversity   float64
+	factor      float64
+	maxRestarts int
+	maxNPop     int
+
+	events       []RestartEvent
+	runBest      float64
+	stagnant     int
+	runStartEval int
+	regime       string
+	largeRuns    int     // Number of large (IPOP) restarts so far
+	largeEvals   int     // BIPOP: evaluations spent in the large regime
+	smallEvals   int     // BIPOP: evaluations spent in the small regime
+	largeScale   float64 // BIPOP: population multiplier of the latest large run
+}
+
+// newRestartController prepares s for restarts. The state switches to a
+// private copy of its config, so that population sizes can change without
+// modifying the caller's config.
+func newRestartController(s *State) *restartController {
+	config := *s.Config
+	s.Config = &config
+
+	r := &restartController{
+		base:        config,
+		stagnation:  config.RestartStagnation,
+		diversity:   config.RestartDiversity,
+		factor:      config.RestartPopulationFactor,
+		maxRestarts: config.MaxRestarts,
+		maxNPop:     config.RestartMaxPopulation,
+		runBest:     math.Inf(1),
+		regime:      "large",
+		largeScale:  1,
+	}
+
+	if r.stagnation == 0 {
+		r.stagnation = defaultRestartStagnation
+	}
+
+	if r.maxRestarts == 0 {
+		r.maxRestarts = defaultMaxRestarts
+	}
+
+	if r.maxNPop == 0 {
+		r.maxNPop = defaultRestartMaxPopulation * config.NPop
+	}
+
+	if config.RestartStrategy == RestartStrategyRestart {
+		r.regime = RestartStrategyRestart
+	}
+
+	return r
+}
+
+// check updates the stagnation counter and returns the restart reason, or
+// an empty string if no restart is needed.
+func (r *restartController) check(s *State) string {
+	if len(r.events) >= r.maxRestarts || s.Iteration >= s.Config.MaxIterations-1 {
+		return ""
+	}
+
+	best := math.Inf(1)
+	for _, population := range [][]*Mayfly{s.Males, s.Females} {
+		for _, m := range population {
+			best = math.Min(best, m.Cost)
+		}
+	}
+
+	if best < r.runBest-restartImprovementTolerance*math.Max(1, math.Abs(r.runBest)) {
+		r.runBest = best
+		r.stagnant = 0
+	} else {
+		r.stagnant++
+	}
+
+	if r.stagnant >= r.stagnation {
+		return "stagnation"
+	}
+
+	if populationDiversity(s) < r.diversity {
+		return "diversity"
+	}
+
+	return ""
+}
+
+// nextScale returns the population multiplier and regime of the next run.
+func (r *restartController) nextScale(s *State) (float64, string) {
+	switch r.base.RestartStrategy {
+	case RestartStrategyIPOP:
+		r.largeRuns++
+		return math.Pow(r.factor, float64(r.largeRuns)), "large"
+
+	case RestartStrategyBIPOP:
+		spent := s.FuncEvals - r.runStartEval
+		if r.regime == "small" {
+			r.smallEvals += spent
+		} else {
+			r.largeEvals += spent
+		}
+
+		if r.smallEvals < r.largeEvals {
+			u := s.Rand.Float64()
+			return math.Pow(r.largeScale, u*u), "small"
+		}
+
+		r.largeRuns++
+		r.largeScale = math.Pow(r.factor, float64(r.largeRuns))
+
+		return r.largeScale, "large"
+
+	default:
+		return 1, RestartStrategyRestart
+	}
+}
+
+// scaled returns n multiplied by scale, rounded and at least minimum.
+func scaled(n int, scale float64, minimum int) int {
+	v := int(math.Round(float64(n) * scale))
+	if v < minimum {
+		return minimum
+	}
+
+	return v
+}
+
+// restart re-initializes the populations of s for a new run.
+func (r *restartController) restart(s *State, p *Pipeline, reason string) {
+	scale, regime := r.nextScale(s)
+
+	config := s.Config
+	config.NPop = scaled(r.base.NPop, scale, 1)
+	config.NPopF = scaled(r.base.NPopF, scale, 1)
+
+	if config.NPop > r.maxNPop {
+		scale = float64(r.maxNPop) / float64(r.base.NPop)
+		config.NPop = r.maxNPop
+		config.NPopF = scaled(r.base.NPopF, scale, 1)
+	}
+
+	config.NC = scaled(r.base.NC, scale, 0)
+	config.NM = scaled(r.base.NM, scale, 0)
+
+	// Mating needs a partner of each sex for every pair
+	if pairs := config.NC / 2; pairs > config.NPop || pairs > config.NPopF {
+		config.NC = 2 * int(math.Min(float64(config.NPop), float64(config.NPopF)))
+	}
+
+	r.events = append(r.events, RestartEvent{
+		Iteration:   s.Iteration,
+		FuncEvals:   s.FuncEvals,
+		Reason:      reason,
+		RunBestCost: r.runBest,
+		BestCost:    s.GlobalBest.Cost,
+		Regime:      regime,
+		NPop:        config.NPop,
+		NPopF:       config.NPopF,
+	})
+
+	s.initPopulations()
+
+	s.G = config.G
+	s.Dance = config.Dance
+	s.FL = config.FL
+
+	r.regime = regime
+	r.runBest = math.Inf(1)
+	r.stagnant = 0
+	r.runStartEval = s.FuncEvals
+
+	for _, stage := range p.Stages {
+		if restarter, ok := stage.(Restarter); ok {
+			restarter.Restart(s)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package mayfly
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for restart.go - IPOP/BIPOP restart strategies
+// =============================================================================
+
+func newRestartTestConfig(strategy string, seed int64) *Config {
+	config := newTestConfig(Rastrigin, 5, 5.12, 300, seed)
+	config.RestartStrategy = strategy
+	config.RestartStagnation = 15
+
+	return config
+}
+
+func TestIPOPRestarts(t *testing.T) {
+	config := newRestartTestConfig(RestartStrategyIPOP, 1)
+	config.RestartMaxPopulation = 80
+
+	result, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if len(result.Restarts) == 0 {
+		t.Fatal("Expected at least one restart")
+	}
+
+	prev := config.NPop
+	for i, event := range result.Restarts {
+		if event.NPop < prev || event.NPop > 80 {
+			t.Errorf("Restart %d: population %d should grow from %d and stay within the cap", i, event.NPop, prev)
+		}
+
+		if event.Reason != "stagnation" && event.Reason != "diversity" {
+			t.Errorf("Restart %d: unexpected reason %q", i, event.Reason)
+		}
+
+		prev = event.NPop
+	}
+
+	if result.Restarts[0].NPop != 2*config.NPop {
+		t.Errorf("First IPOP restart should double the population, got %d", result.Restarts[0].NPop)
+	}
+
+	if config.NPop != 20 {
+		t.Errorf("Restarts must not modify the caller's config, NPop = %d", config.NPop)
+	}
+}
+
+func TestRestartsKeepGlobalBest(t *testing.T) {
+	config := newRestartTestConfig(RestartStrategyRestart, 2)
+
+	calls := 0
+	config.ObjectiveFunc = func(x []float64) float64 {
+		calls++
+		return Rastrigin(x)
+	}
+
+	result, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if len(result.Restarts) == 0 {
+		t.Fatal("Expected at least one restart")
+	}
+
+	for i := 1; i < len(result.BestSolution); i++ {
+		if result.BestSolution[i] > result.BestSolution[i-1] {
+			t.Fatalf("Global best got worse at iteration %d", i)
+		}
+	}
+
+	for _, event := range result.Restarts {
+		if event.NPop != config.NPop || event.Regime != RestartStrategyRestart {
+			t.Errorf("Plain restarts should keep the population size, got %+v", event)
+		}
+
+		if event.BestCost > event.RunBestCost {
+			t.Errorf("Global best %v worse than run best %v", event.BestCost, event.RunBestCost)
+		}
+	}
+
+	if result.FuncEvalCount != calls {
+		t.Errorf("FuncEvalCount = %d, objective called %d times", result.FuncEvalCount, calls)
+	}
+}
+
+func TestBIPOPRegimes(t *testing.T) {
+	config := newRestartTestConfig(RestartStrategyBIPOP, 3)
+	config.MaxIterations = 600
+	config.RestartStagnation = 10
+	config.MaxRestarts = 20
+
+	result, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	regimes := make(map[string]int)
+	for _, event := range result.Restarts {
+		regimes[event.Regime]++
+	}
+
+	if regimes["large"] == 0 || regimes["small"] == 0 {
+		t.Errorf("BIPOP should alternate regimes, got %v", regimes)
+	}
+}
+
+func TestRestartsEveryVariant(t *testing.T) {
+	for _, name := range []string{"ma", "desma", "olce", "eobbma", "gsasma", "mpma", "aoblmoa", "desma-olce-gsasma"} {
+		t.Run(name, func(t *testing.T) {
+			config := NewVariant(name).GetConfig()
+			config.ObjectiveFunc = Rastrigin
+			config.ProblemSize = 3
+			config.LowerBound = -5.12
+			config.UpperBound = 5.12
+			config.MaxIterations = 100
+			config.Rand = rand.New(rand.NewSource(4))
+			config.RestartStrategy = RestartStrategyIPOP
+			config.RestartStagnation = 5
+			config.MaxRestarts = 2
+
+			result, err := Optimize(config)
+			if err != nil {
+				t.Fatalf("Optimize failed: %v", err)
+			}
+
+			if len(result.Restarts) == 0 || len(result.Restarts) > 2 {
+				t.Errorf("Expected 1-2 restarts, got %d", len(result.Restarts))
+			}
+		})
+	}
+}
+
+func TestNoRestartsByDefault(t *testing.T) {
+	config := newRestartTestConfig("", 5)
+
+	result, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if result.Restarts != nil {
+		t.Errorf("Expected no restart events, got %d", len(result.Restarts))
+	}
+}
+
+func TestRestartDiversityDisabled(t *testing.T) {
+	config := newRestartTestConfig(RestartStrategyRestart, 2)
+	config.ObjectiveFunc = Sphere
+	config.RestartDiversity = 0.5 // Every converging run restarts on diversity
+
+	result, err := Optimize(config)
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if len(result.Restarts) == 0 || result.Restarts[0].Reason != "diversity" {
+		t.Fatalf("Expected diversity restarts, got %+v", result.Restarts)
+	}
+
+	// 0 switches the diversity trigger off
+	config = newRestartTestConfig(RestartStrategyRestart, 2)
+	config.ObjectiveFunc = Sphere
+	config.RestartDiversity = 0
+
+	if result, err = Optimize(config); err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	for _, event := range result.Restarts {
+		if event.Reason != "stagnation" {
+			t.Fatalf("Unexpected %s restart with RestartDiversity 0", event.Reason)
+		}
+	}
+}
+
+func TestRestartConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"Unknown strategy", func(c *Config) { c.RestartStrategy = "lpop" }},
+		{"Negative stagnation", func(c *Config) { c.RestartStagnation = -1 }},
+		{"Negative diversity", func(c *Config) { c.RestartDiversity = -1 }},
+		{"Shrinking factor", func(c *Config) { c.RestartPopulationFactor = 0.5 }},
+		{"Zero factor", func(c *Config) { c.RestartPopulationFactor = 0 }},
+		{"Negative max restarts", func(c *Config) { c.MaxRestarts = -1 }},
+		{"Negative population cap", func(c *Config) { c.RestartMaxPopulation = -1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newRestartTestConfig(RestartStrategyIPOP, 1)
+			tt.modify(config)
+
+			if err := ValidateConfig(config); err == nil {
+				t.Error("ValidateConfig should reject the config")
+			}
+
+			if _, err := Optimize(config); err == nil {
+				t.Error("Optimize should reject the config")
+			}
+		})
+	}
+}
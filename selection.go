@@ -0,0 +1,422 @@
+// Package mayfly - Parent Selection
+//
+// Implements the selectable strategies that choose mating pairs and
+// mutation parents.
+//
+// Config.ParentSelection selects how the NC/2 male-female pairs are formed:
+//   - "rank" (default): males[k] mates with females[k] for the best ranks
+//   - "tournament": each parent wins a tournament of TournamentSize
+//   - "proportional": fitness-proportional (roulette wheel) selection
+//   - "sus": stochastic universal sampling
+//   - "elite": random pairing within the best NC/2 of each population
+//   - "assortative": each male mates with the most similar of TournamentSize
+//     random females
+//   - "disassortative": each male mates with the most distant of
+//     TournamentSize random females
+//
+// Config.MutationParentSelection selects the parent of each mutant:
+//   - "random" (default): a uniformly random offspring
+//   - "tournament": the winner of a tournament among the offspring
+//   - "best": the best offspring
+//   - "population": a uniformly random mayfly from the current populations
+//     or the offspring
+//
+// Fitness-based selection minimizes cost. Proportional selection and SUS use
+// the distance to the worst cost as fitness.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Parent selection names accepted by Config.ParentSelection.
+const (
+	ParentSelectionRank           = "rank"
+	ParentSelectionTournament     = "tournament"
+	ParentSelectionProportional   = "proportional"
+	ParentSelectionSUS            = "sus"
+	ParentSelectionElite          = "elite"
+	ParentSelectionAssortative    = "assortative"
+	ParentSelectionDisassortative = "disassortative"
+)
+
+// Mutation parent selection names accepted by Config.MutationParentSelection.
+const (
+	MutationParentRandom     = "random"
+	MutationParentTournament = "tournament"
+	MutationParentBest       = "best"
+	MutationParentPopulation = "population"
+)
+
+// defaultTournamentSize is used when Config.TournamentSize is zero.
+const defaultTournamentSize = 2
+
+// ParentSelector chooses the male and female parents of each mating.
+// SelectParents returns pairs males and pairs females; males[k] mates with females[k].
+type ParentSelector interface {
+	SelectParents(s *State, pairs int) (males, females []*Mayfly)
+}
+
+// MutationParentSelector chooses the parent of a mutant. It is called after
+// the crossover offspring have been added to s.Offspring. In surrogate-assisted
+// mode the offspring are not evaluated yet, so s.Offspring holds the males and
+// females instead.
+type MutationParentSelector interface {
+	SelectMutationParent(s *State) *Mayfly
+}
+
+// NewParentSelector returns the ParentSelector for a Config.ParentSelection value.
+// An empty name selects rank-aligned pairing.
+func NewParentSelector(selection string) (ParentSelector, error) {
+	switch selection {
+	case "", ParentSelectionRank:
+		return RankSelection{}, nil
+	case ParentSelectionTournament:
+		return TournamentSelection{}, nil
+	case ParentSelectionProportional:
+		return ProportionalSelection{}, nil
+	case ParentSelectionSUS:
+		return SUSSelection{}, nil
+	case ParentSelectionElite:
+		return EliteRandomSelection{}, nil
+	case ParentSelectionAssortative:
+		return AssortativeSelection{}, nil
+	case ParentSelectionDisassortative:
+		return AssortativeSelection{Disassortative: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown parent selection '%s' (expected rank, tournament, proportional, sus, elite, assortative or disassortative)",
+			selection)
+	}
+}
+
+// NewMutationParentSelector returns the MutationParentSelector for a
+// Config.MutationParentSelection value. An empty name selects a random offspring.
+func NewMutationParentSelector(selection string) (MutationParentSelector, error) {
+	switch selection {
+	case "", MutationParentRandom:
+		return RandomMutationParent{}, nil
+	case MutationParentTournament:
+		return TournamentMutationParent{}, nil
+	case MutationParentBest:
+		return BestMutationParent{}, nil
+	case MutationParentPopulation:
+		return PopulationMutationParent{}, nil
+	default:
+		return nil, fmt.Errorf("unknown mutation parent selection '%s' (expected random, tournament, best or population)",
+			selection)
+	}
+}
+
+// validateSelectionConfig checks the selection strategies and their parameters.
+func validateSelectionConfig(config *Config) error {
+	if _, err := NewParentSelector(config.ParentSelection); err != nil {
+		return err
+	}
+
+	if _, err := NewMutationParentSelector(config.MutationParentSelection); err != nil {
+		return err
+	}
+
+	if config.TournamentSize < 0 {
+		return fmt.Errorf("TournamentSize must be non-negative, got %d", config.TournamentSize)
+	}
+
+	return nil
+}
+
+// tournamentSize returns Config.TournamentSize or its default.
+func tournamentSize(config *Config) int {
+	if config.TournamentSize == 0 {
+		return defaultTournamentSize
+	}
+
+	return config.TournamentSize
+}
+
+// tournament returns the lowest-cost mayfly among size uniformly drawn
+// members of population (with replacement).
+func tournament(population []*Mayfly, size int, rng *rand.Rand) *Mayfly {
+	winner := population[rng.Intn(len(population))]
+
+	for i := 1; i < size; i++ {
+		candidate := population[rng.Intn(len(population))]
+		if candidate.Cost < winner.Cost {
+			winner = candidate
+		}
+	}
+
+	return winner
+}
+
+// selectionWeights converts costs to non-negative fitness values for
+// proportional selection: the distance to the worst cost, plus a small
+// offset so that every mayfly can be selected.
+func selectionWeights(population []*Mayfly) []float64 {
+	worst := math.Inf(-1)
+	best := math.Inf(1)
+
+	for _, m := range population {
+		worst = math.Max(worst, m.Cost)
+		best = math.Min(best, m.Cost)
+	}
+
+	offset := 1e-12
+	if spread := worst - best; spread > 0 && !math.IsInf(spread, 0) {
+		offset = 1e-3 * spread
+	}
+
+	weights := make([]float64, len(population))
+	for i, m := range population {
+		if math.IsInf(worst, 0) || math.IsNaN(m.Cost) {
+			weights[i] = 1
+		} else {
+			weights[i] = worst - m.Cost + offset
+		}
+	}
+
+	return weights
+}
+
+// rouletteSelect returns n indices drawn independently with probability
+// proportional to weights.
+func rouletteSelect(weights []float64, n int, rng *rand.Rand) []int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+
+	indices := make([]int, n)
+
+	for k := range indices {
+		r := rng.Float64() * total
+		indices[k] = len(weights) - 1
+
+		for i, w := range weights {
+			r -= w
+			if r < 0 {
+				indices[k] = i
+				break
+			}
+		}
+	}
+
+	return indices
+}
+
+// susSelect returns n indices by stochastic universal sampling: n equally
+// spaced pointers with a single random offset sweep the cumulative weights.
+func susSelect(weights []float64, n int, rng *rand.Rand) []int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+
+	step := total / float64(n)
+	pointer := rng.Float64() * step
+	indices := make([]int, 0, n)
+
+	cumulative := 0.0
+	i := 0
+
+	for len(indices) < n {
+		for i < len(weights)-1 && cumulative+weights[i] <= pointer {
+			cumulative += weights[i]
+			i++
+		}
+
+		indices = append(indices, i)
+		pointer += step
+	}
+
+	return indices
+}
+
+// pick returns the mayflies at the given indices.
+func pick(population []*Mayfly, indices []int) []*Mayfly {
+	selected := make([]*Mayfly, len(indices))
+	for k, i := range indices {
+		selected[k] = population[i]
+	}
+
+	return selected
+}
+
+// euclideanDistance returns the Euclidean distance between a and b.
+func euclideanDistance(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+
+	return math.Sqrt(sum)
+}
+
+// RankSelection pairs males[k] with females[k] for the best ranks.
+// It is the original Mayfly mating scheme.
+type RankSelection struct{}
+
+// SelectParents implements ParentSelector.
+func (RankSelection) SelectParents(s *State, pairs int) ([]*Mayfly, []*Mayfly) {
+	return s.Males[:pairs], s.Females[:pairs]
+}
+
+// TournamentSelection selects every parent by a tournament of
+// Config.TournamentSize within its population.
+type TournamentSelection struct{}
+
+// SelectParents implements ParentSelector.
+func (TournamentSelection) SelectParents(s *State, pairs int) ([]*Mayfly, []*Mayfly) {
+	size := tournamentSize(s.Config)
+	males := make([]*Mayfly, pairs)
+	females := make([]*Mayfly, pairs)
+
+	for k := 0; k < pairs; k++ {
+		males[k] = tournament(s.Males, size, s.Rand)
+		females[k] = tournament(s.Females, size, s.Rand)
+	}
+
+	return males, females
+}
+
+// ProportionalSelection selects parents with probability proportional to
+// their fitness (roulette wheel).
+type ProportionalSelection struct{}
+
+// SelectParents implements ParentSelector.
+func (ProportionalSelection) SelectParents(s *State, pairs int) ([]*Mayfly, []*Mayfly) {
+	males := pick(s.Males, rouletteSelect(selectionWeights(s.Males), pairs, s.Rand))
+	females := pick(s.Females, rouletteSelect(selectionWeights(s.Females), pairs, s.Rand))
+
+	return males, females
+}
+
+// SUSSelection selects parents by stochastic universal sampling. The
+// selected females are shuffled so that pairings are not rank-aligned.
+type SUSSelection struct{}
+
+// SelectParents implements ParentSelector.
+func (SUSSelection) SelectParents(s *State, pairs int) ([]*Mayfly, []*Mayfly) {
+	males := pick(s.Males, susSelect(selectionWeights(s.Males), pairs, s.Rand))
+	females := pick(s.Females, susSelect(selectionWeights(s.Females), pairs, s.Rand))
+
+	s.Rand.Shuffle(len(females), func(i, j int) {
+		females[i], females[j] = females[j], females[i]
+	})
+
+	return males, females
+}
+
+// EliteRandomSelection pairs uniformly random members of the best NC/2
+// males and females.
+type EliteRandomSelection struct{}
+
+// SelectParents implements ParentSelector.
+func (EliteRandomSelection) SelectParents(s *State, pairs int) ([]*Mayfly, []*Mayfly) {
+	eliteMales := s.Males
+	if pairs < len(eliteMales) {
+		eliteMales = eliteMales[:pairs]
+	}
+
+	eliteFemales := s.Females
+	if pairs < len(eliteFemales) {
+		eliteFemales = eliteFemales[:pairs]
+	}
+
+	males := make([]*Mayfly, pairs)
+	females := make([]*Mayfly, pairs)
+
+	for k := 0; k < pairs; k++ {
+		males[k] = eliteMales[s.Rand.Intn(len(eliteMales))]
+		females[k] = eliteFemales[s.Rand.Intn(len(eliteFemales))]
+	}
+
+	return males, females
+}
+
+// AssortativeSelection pairs the best males with the nearest (assortative)
+// or most distant (disassortative) of Config.TournamentSize randomly drawn
+// females.
+type AssortativeSelection struct {
+	Disassortative bool
+}
+
+// SelectParents implements ParentSelector.
+func (a AssortativeSelection) SelectParents(s *State, pairs int) ([]*Mayfly, []*Mayfly) {
+	size := tournamentSize(s.Config)
+	males := s.Males[:pairs]
+	females := make([]*Mayfly, pairs)
+
+	for k, male := range males {
+		var chosen *Mayfly
+
+		chosenDist := 0.0
+
+		for i := 0; i < size; i++ {
+			candidate := s.Females[s.Rand.Intn(len(s.Females))]
+			d := euclideanDistance(male.Position, candidate.Position)
+
+			if chosen == nil || (a.Disassortative && d > chosenDist) || (!a.Disassortative && d < chosenDist) {
+				chosen = candidate
+				chosenDist = d
+			}
+		}
+
+		females[k] = chosen
+	}
+
+	return males, females
+}
+
+// RandomMutationParent chooses a uniformly random offspring.
+// It is the original Mayfly mutation scheme.
+type RandomMutationParent struct{}
+
+// SelectMutationParent implements MutationParentSelector.
+func (RandomMutationParent) SelectMutationParent(s *State) *Mayfly {
+	return s.Offspring[s.Rand.Intn(len(s.Offspring))]
+}
+
+// TournamentMutationParent chooses the winner of a tournament of
+// Config.TournamentSize among the offspring.
+type TournamentMutationParent struct{}
+
+// SelectMutationParent implements MutationParentSelector.
+func (TournamentMutationParent) SelectMutationParent(s *State) *Mayfly {
+	return tournament(s.Offspring, tournamentSize(s.Config), s.Rand)
+}
+
+// BestMutationParent chooses the lowest-cost offspring.
+type BestMutationParent struct{}
+
+// SelectMutationParent implements MutationParentSelector.
+func (BestMutationParent) SelectMutationParent(s *State) *Mayfly {
+	best := s.Offspring[0]
+	for _, off := range s.Offspring[1:] {
+		if off.Cost < best.Cost {
+			best = off
+		}
+	}
+
+	return best
+}
+
+// PopulationMutationParent chooses a uniformly random mayfly from the males,
+// females and offspring.
+type PopulationMutationParent struct{}
+
+// SelectMutationParent implements MutationParentSelector.
+func (PopulationMutationParent) SelectMutationParent(s *State) *Mayfly {
+	i := s.Rand.Intn(len(s.Males) + len(s.Females) + len(s.Offspring))
+
+	switch {
+	case i < len(s.Males):
+		return s.Males[i]
+	case i < len(s.Males)+len(s.Females):
+		return s.Females[i-len(s.Males)]
+	default:
+		return s.Offspring[i-len(s.Males)-len(s.Females)]
+	}
+}
@@ -0,0 +1,196 @@
+package mayfly
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for selection.go - Parent selection strategies
+// =============================================================================
+
+// newSelectionTestState builds a state whose males and females have costs
+// 0..n-1 and positions equal to their cost.
+func newSelectionTestState(n int, seed int64) *State {
+	config := NewDefaultConfig()
+	config.ProblemSize = 1
+
+	s := &State{Config: config, Rand: rand.New(rand.NewSource(seed))}
+
+	for i := 0; i < n; i++ {
+		male := newMayfly(1)
+		male.Cost = float64(i)
+		male.Position[0] = float64(i)
+		s.Males = append(s.Males, male)
+
+		female := newMayfly(1)
+		female.Cost = float64(i)
+		female.Position[0] = float64(i)
+		s.Females = append(s.Females, female)
+	}
+
+	return s
+}
+
+func TestRankSelection(t *testing.T) {
+	s := newSelectionTestState(10, 1)
+	males, females := RankSelection{}.SelectParents(s, 3)
+
+	for k := 0; k < 3; k++ {
+		if males[k] != s.Males[k] || females[k] != s.Females[k] {
+			t.Errorf("Pair %d is not rank-aligned", k)
+		}
+	}
+}
+
+func TestSelectionPressure(t *testing.T) {
+	selectors := map[string]ParentSelector{
+		"tournament":   TournamentSelection{},
+		"proportional": ProportionalSelection{},
+		"sus":          SUSSelection{},
+	}
+
+	for name, selector := range selectors {
+		t.Run(name, func(t *testing.T) {
+			s := newSelectionTestState(10, 2)
+			males, females := selector.SelectParents(s, 1000)
+
+			if len(males) != 1000 || len(females) != 1000 {
+				t.Fatalf("Expected 1000 pairs, got %d/%d", len(males), len(females))
+			}
+
+			// Selection should favor low costs: mean cost below the population mean 4.5
+			total := 0.0
+			for _, m := range males {
+				total += m.Cost
+			}
+
+			if mean := total / 1000; mean >= 4.0 {
+				t.Errorf("Mean selected cost %.2f shows no selection pressure", mean)
+			}
+		})
+	}
+}
+
+func TestSUSSelectCounts(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	indices := susSelect([]float64{1, 1, 2}, 4, rng)
+
+	counts := make([]int, 3)
+	for _, i := range indices {
+		counts[i]++
+	}
+
+	// SUS has minimal spread: each index is selected its expected number of times
+	if counts[0] != 1 || counts[1] != 1 || counts[2] != 2 {
+		t.Errorf("Unexpected SUS counts %v", counts)
+	}
+}
+
+func TestEliteRandomSelection(t *testing.T) {
+	s := newSelectionTestState(10, 4)
+	males, females := EliteRandomSelection{}.SelectParents(s, 3)
+
+	for k := range males {
+		if males[k].Cost >= 3 || females[k].Cost >= 3 {
+			t.Errorf("Pair %d selected outside the elite: %v/%v", k, males[k].Cost, females[k].Cost)
+		}
+	}
+}
+
+func TestAssortativeSelection(t *testing.T) {
+	s := newSelectionTestState(20, 5)
+	s.Config.TournamentSize = 5
+
+	distance := func(selector ParentSelector) float64 {
+		males, females := selector.SelectParents(s, 10)
+		total := 0.0
+
+		for k := range males {
+			total += euclideanDistance(males[k].Position, females[k].Position)
+		}
+
+		return total
+	}
+
+	near := distance(AssortativeSelection{})
+	far := distance(AssortativeSelection{Disassortative: true})
+
+	if near >= far {
+		t.Errorf("Assortative pairs (%.1f) should be closer than disassortative pairs (%.1f)", near, far)
+	}
+}
+
+func TestMutationParentSelectors(t *testing.T) {
+	s := newSelectionTestState(5, 6)
+	s.Offspring = append([]*Mayfly{}, s.Males...)
+	s.Rand.Shuffle(len(s.Offspring), func(i, j int) {
+		s.Offspring[i], s.Offspring[j] = s.Offspring[j], s.Offspring[i]
+	})
+
+	if best := (BestMutationParent{}).SelectMutationParent(s); best.Cost != 0 {
+		t.Errorf("Best mutation parent has cost %v", best.Cost)
+	}
+
+	for _, selector := range []MutationParentSelector{
+		RandomMutationParent{}, TournamentMutationParent{}, PopulationMutationParent{},
+	} {
+		if parent := selector.SelectMutationParent(s); parent == nil {
+			t.Errorf("%T returned nil", selector)
+		}
+	}
+}
+
+func TestParentSelectionOptimize(t *testing.T) {
+	for _, selection := range []string{
+		ParentSelectionRank, ParentSelectionTournament, ParentSelectionProportional,
+		ParentSelectionSUS, ParentSelectionElite, ParentSelectionAssortative,
+		ParentSelectionDisassortative,
+	} {
+		t.Run(selection, func(t *testing.T) {
+			config := newTestConfig(Sphere, 5, 10, 30, 10)
+			config.MaxIterations = 100
+			config.ParentSelection = selection
+			config.MutationParentSelection = MutationParentTournament
+
+			if err := ValidateConfig(config); err != nil {
+				t.Fatalf("ValidateConfig failed: %v", err)
+			}
+
+			result, err := Optimize(config)
+			if err != nil {
+				t.Fatalf("Optimize failed: %v", err)
+			}
+
+			if result.GlobalBest.Cost > 1.0 {
+				t.Errorf("%s did not converge on Sphere: %v", selection, result.GlobalBest.Cost)
+			}
+		})
+	}
+}
+
+func TestSelectionConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"Unknown parent selection", func(c *Config) { c.ParentSelection = "lottery" }},
+		{"Unknown mutation parent", func(c *Config) { c.MutationParentSelection = "worst" }},
+		{"Negative tournament size", func(c *Config) { c.TournamentSize = -1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newTestConfig(Sphere, 5, 10, 30, 1)
+			tt.modify(config)
+
+			if err := ValidateConfig(config); err == nil {
+				t.Error("ValidateConfig should reject the config")
+			}
+
+			if _, err := Optimize(config); err == nil {
+				t.Error("Optimize should reject the config")
+			}
+		})
+	}
+}
@@ -94,6 +94,10 @@ func (s *AlgorithmSelector) calculateConfidence(characteristics ProblemCharacter
 		confidence = 0.85 // MPMA is designed for stability
 	}
 
+	if characteristics.Landscape == NarrowValley && strings.HasSuffix(variant.Name(), "CMA-ES") {
+		confidence = 0.9 // CMA-ES learns the shape of ill-conditioned valleys
+	}
+
 	// Lower confidence for expensive evaluations with high-overhead algorithms
 	if characteristics.ExpensiveEvaluations && variant.EstimatedOverhead() > 1.15 {
 		confidence *= 0.7
@@ -116,6 +120,8 @@ func (s *AlgorithmSelector) generateReasoning(characteristics ProblemCharacteris
 			reasons = append(reasons, "Highly multimodal problem benefits from orthogonal learning")
 		} else if variant.Name() == "DESMA" {
 			reasons = append(reasons, "Multimodal problem benefits from elite strategy")
+		} else if variant.Name() == "IPOP-CMA-ES" {
+			reasons = append(reasons, "Restarts with growing population escape local optima")
 		}
 	}
 
@@ -127,6 +133,10 @@ func (s *AlgorithmSelector) generateReasoning(characteristics ProblemCharacteris
 		reasons = append(reasons, "Median guidance handles ill-conditioned problems well")
 	}
 
+	if characteristics.Landscape == NarrowValley && strings.HasSuffix(variant.Name(), "CMA-ES") {
+		reasons = append(reasons, "Covariance adaptation handles ill-conditioned valleys")
+	}
+
 	if characteristics.RequiresFastConvergence && variant.Name() == "GSASMA" {
 		reasons = append(reasons, "Fast convergence via simulated annealing")
 	}
@@ -139,6 +149,10 @@ func (s *AlgorithmSelector) generateReasoning(characteristics ProblemCharacteris
 		reasons = append(reasons, "Low overhead suitable for expensive evaluations")
 	}
 
+	if characteristics.ExpensiveEvaluations {
+		reasons = append(reasons, "Set UseSurrogate to pre-screen candidates with a surrogate model")
+	}
+
 	if characteristics.Dimensionality >= 20 && variant.Name() == "OLCE-MA" {
 		reasons = append(reasons, "High dimensionality benefits from diversity")
 	}
@@ -0,0 +1,152 @@
+// Package mayfly - Standalone component optimizers
+//
+// Runs the building blocks of the hybrid variants on their own, for
+// ablation studies of what each component contributes:
+//   - OptimizeSA: simulated annealing with the AnnealingScheduler and the
+//     Metropolis criterion of GSASMA
+//   - OptimizeGoldenSine: the Golden Sine Algorithm with the adaptive
+//     goldenSineUpdate of GSASMA
+//   - OptimizeAquila: the Aquila Optimizer with the four hunting strategies
+//     of AOBLMOA
+//
+// The runners follow the Config/Result contract and the evaluation budget of
+// the baselines in baselines.go: an iteration is NPop + NPopF + NC + NM
+// evaluations, and iteration-dependent schedules (temperature, golden sine
+// factor, Aquila strategy) advance with the iterations of that budget.
+// Golden Sine and Aquila update a population of NPop + NPopF and keep a
+// candidate only if it improves its member.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+)
+
+// GSASMA settings for an unset InitialTemperature, CoolingRate or
+// GoldenFactor, so that the standalone components run like they do inside
+// NewGSASMAConfig.
+const (
+	defaultInitialTemperature = 100.0
+	defaultCoolingRate        = 0.95
+	defaultGoldenFactor       = 1.0
+)
+
+// saMinStep is the smallest neighbourhood radius of OptimizeSA, relative to
+// VelMax.
+const saMinStep = 1e-6
+
+// OptimizeSA minimizes config.ObjectiveFunc with simulated annealing from a
+// random position. Neighbours are Gaussian perturbations of every dimension
+// with standard deviation VelMax·√(T/T₀), so the search narrows as the
+// temperature T falls. The temperature follows CoolingSchedule with
+// InitialTemperature and CoolingRate and is updated once per iteration.
+func OptimizeSA(config *Config) (*Result, error) {
+	b, err := newBaselineRun(config, 0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	t0 := orDefault(config.InitialTemperature, defaultInitialTemperature)
+	rate := orDefault(config.CoolingRate, defaultCoolingRate)
+
+	if t0 <= 0 {
+		return nil, fmt.Errorf("InitialTemperature must be positive, got %v", config.InitialTemperature)
+	}
+
+	if rate <= 0 || rate >= 1 {
+		return nil, fmt.Errorf("CoolingRate must be in (0, 1), got %v", config.CoolingRate)
+	}
+
+	scheduler := NewAnnealingScheduler(t0, rate, config.CoolingSchedule)
+
+	current := unifrndVec(config.LowerBound, config.UpperBound, config.ProblemSize, b.rng)
+	cost := b.evaluate(current)
+	iteration := 0
+
+	for !b.exhausted() {
+		for ; iteration < b.evals/b.perIteration; iteration++ {
+			scheduler.Update()
+		}
+
+		step := config.VelMax * math.Max(math.Sqrt(scheduler.GetTemperature()/t0), saMinStep)
+
+		candidate := make([]float64, len(current))
+		for j := range candidate {
+			candidate[j] = current[j] + step*b.rng.NormFloat64()
+		}
+
+		clampVec(candidate, config.LowerBound, config.UpperBound)
+
+		if candidateCost := b.evaluate(candidate); shouldAccept(cost, candidateCost, scheduler.GetTemperature(), b.rng) {
+			current, cost = candidate, candidateCost
+		}
+	}
+
+	return b.result(), nil
+}
+
+// OptimizeGoldenSine minimizes config.ObjectiveFunc with the Golden Sine
+// Algorithm. Every member moves towards the best solution with the
+// adaptive golden sine step of GoldenFactor.
+func OptimizeGoldenSine(config *Config) (*Result, error) {
+	b, err := newBaselineRun(config, 1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	factor := orDefault(config.GoldenFactor, defaultGoldenFactor)
+	population := b.initPopulation(config.NPop + config.NPopF)
+
+	for !b.exhausted() {
+		for _, m := range population {
+			if b.exhausted() {
+				break
+			}
+
+			candidate := goldenSineUpdateAdaptive(m.Position, b.best.Position, factor,
+				b.evals/b.perIteration, config.MaxIterations, config.LowerBound, config.UpperBound, b.rng)
+
+			if cost := b.evaluate(candidate); cost < m.Cost {
+				m.Position, m.Cost = candidate, cost
+			}
+		}
+	}
+
+	return b.result(), nil
+}
+
+// OptimizeAquila minimizes config.ObjectiveFunc with the Aquila Optimizer.
+// Each member uses one of the two exploration strategies during the first
+// two thirds of the iterations and one of the two exploitation strategies
+// afterwards.
+func OptimizeAquila(config *Config) (*Result, error) {
+	b, err := newBaselineRun(config, 1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// The strategies draw from config.Rand
+	runConfig := *config
+	runConfig.Rand = b.rng
+
+	population := b.initPopulation(config.NPop + config.NPopF)
+	best := Best{Position: b.best.Position}
+
+	for !b.exhausted() {
+		for _, m := range population {
+			if b.exhausted() {
+				break
+			}
+
+			iteration := b.evals / b.perIteration
+			strategy := selectAquilaStrategy(iteration, config.MaxIterations, b.rng)
+			candidate := applyAquilaStrategy(m, best, population, strategy, iteration, config.MaxIterations, &runConfig)
+
+			if cost := b.evaluate(candidate); cost < m.Cost {
+				m.Position, m.Cost = candidate, cost
+			}
+		}
+	}
+
+	return b.result(), nil
+}
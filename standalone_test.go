@@ -0,0 +1,156 @@
+package mayfly
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Tests for standalone.go - SA, Golden Sine and Aquila runners
+// =============================================================================
+
+var standaloneOptimizers = []struct {
+	name     string
+	variant  string
+	optimize func(*Config) (*Result, error)
+}{
+	{"SA", "sa", OptimizeSA},
+	{"Gold-SA", "golden-sine", OptimizeGoldenSine},
+	{"AO", "aquila", OptimizeAquila},
+}
+
+func TestStandaloneBudgetAndHistory(t *testing.T) {
+	for _, tt := range standaloneOptimizers {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.optimize(newTestConfig(Rastrigin, 10, 5, 200, 1))
+			if err != nil {
+				t.Fatalf("%s failed: %v", tt.name, err)
+			}
+
+			if budget := 200 * (20 + 20 + 20 + 1); result.FuncEvalCount != budget {
+				t.Errorf("Expected %d evaluations, got %d", budget, result.FuncEvalCount)
+			}
+
+			if len(result.BestSolution) != 200 || result.BestSolution[199] != result.GlobalBest.Cost {
+				t.Fatalf("Expected 200 iterations of history ending at the best cost")
+			}
+
+			for i := 1; i < len(result.BestSolution); i++ {
+				if result.BestSolution[i] > result.BestSolution[i-1] {
+					t.Fatalf("History increases at iteration %d", i)
+				}
+			}
+
+			if got := Rastrigin(result.GlobalBest.Position); got != result.GlobalBest.Cost {
+				t.Errorf("Reported cost %g, position evaluates to %g", result.GlobalBest.Cost, got)
+			}
+
+			again, err := tt.optimize(newTestConfig(Rastrigin, 10, 5, 200, 1))
+			if err != nil {
+				t.Fatalf("%s failed: %v", tt.name, err)
+			}
+
+			if again.GlobalBest.Cost != result.GlobalBest.Cost {
+				t.Error("Seeded runs are not reproducible")
+			}
+		})
+	}
+}
+
+func TestStandaloneUnsupportedOptions(t *testing.T) {
+	options := append([]configOption{
+		{"niching", func(c *Config) { c.NichingMethod = NichingClearing }},
+		{"restarts", func(c *Config) { c.RestartStrategy = RestartStrategyIPOP }},
+	}, mayflyOnlyOptions...)
+
+	for _, tt := range standaloneOptimizers {
+		for _, o := range options {
+			config := newTestConfig(Sphere, 10, 5, 200, 1)
+			o.modify(config)
+
+			if _, err := tt.optimize(config); err == nil || !strings.Contains(err.Error(), o.option) {
+				t.Errorf("%s: expected an error naming %s, got %v", tt.name, o.option, err)
+			}
+		}
+	}
+}
+
+func TestStandaloneConverge(t *testing.T) {
+	random, err := OptimizeRandomSearch(newTestConfig(Sphere, 10, 5, 200, 1))
+	if err != nil {
+		t.Fatalf("OptimizeRandomSearch failed: %v", err)
+	}
+
+	for _, tt := range standaloneOptimizers {
+		result, err := tt.optimize(newTestConfig(Sphere, 10, 5, 200, 1))
+		if err != nil {
+			t.Fatalf("%s failed: %v", tt.name, err)
+		}
+
+		if result.GlobalBest.Cost > 1e-2*random.GlobalBest.Cost {
+			t.Errorf("%s reached %g on Sphere (random search %g)", tt.name, result.GlobalBest.Cost, random.GlobalBest.Cost)
+		}
+	}
+}
+
+func TestSATemperature(t *testing.T) {
+	// A hot, slowly cooling run accepts worse moves and ends further from
+	// the optimum than the default schedule
+	hot := newTestConfig(Sphere, 10, 5, 200, 1)
+	hot.InitialTemperature = 1e6
+	hot.CoolingRate = 0.999
+
+	hotResult, err := OptimizeSA(hot)
+	if err != nil {
+		t.Fatalf("OptimizeSA failed: %v", err)
+	}
+
+	result, err := OptimizeSA(newTestConfig(Sphere, 10, 5, 200, 1))
+	if err != nil {
+		t.Fatalf("OptimizeSA failed: %v", err)
+	}
+
+	if hotResult.GlobalBest.Cost <= result.GlobalBest.Cost {
+		t.Errorf("Expected the hot schedule (%g) to be worse than the default (%g)",
+			hotResult.GlobalBest.Cost, result.GlobalBest.Cost)
+	}
+
+	for _, rate := range []float64{-0.5, 1} {
+		config := newTestConfig(Sphere, 10, 5, 200, 1)
+		config.CoolingRate = rate
+
+		if _, err := OptimizeSA(config); err == nil {
+			t.Errorf("CoolingRate %v should be rejected", rate)
+		}
+	}
+}
+
+func TestStandaloneVariants(t *testing.T) {
+	for _, tt := range standaloneOptimizers {
+		variant := NewVariant(tt.variant)
+		if variant == nil || variant.Name() != tt.name {
+			t.Fatalf("NewVariant(%q) = %v, expected %s", tt.variant, variant, tt.name)
+		}
+
+		config := variant.GetConfig()
+		config.ObjectiveFunc = Sphere
+		config.ProblemSize = 5
+		config.LowerBound = -5
+		config.UpperBound = 5
+		config.MaxIterations = 30
+		config.Rand = rand.New(rand.NewSource(1))
+
+		if _, err := OptimizeVariant(variant, config); err != nil {
+			t.Errorf("OptimizeVariant(%s) failed: %v", tt.name, err)
+		}
+	}
+
+	// Ablation: GSASMA against its components
+	runner := NewComparisonRunner().WithVariantNames("gsasma", "sa", "golden-sine").WithRuns(3).WithIterations(50)
+	comparison := runner.Compare("Sphere", Sphere, 5, -5, 5)
+
+	if len(comparison.AlgorithmNames) != 3 || comparison.FriedmanResult == nil {
+		t.Fatalf("Expected a comparison of 3 algorithms, got %v", comparison.AlgorithmNames)
+	}
+}
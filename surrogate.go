@@ -0,0 +1,725 @@
+// Package mayfly - Surrogate-assisted optimization
+//
+// Implements a surrogate-assisted mode for expensive objective functions.
+// Every true evaluation is stored in an archive. A local model, trained on
+// the SurrogateSamples archive points closest to the global best, predicts
+// the cost μ and its uncertainty σ of a candidate position. Candidates are
+// ranked by the infill criterion and only the best SurrogateFraction of
+// each batch get a true evaluation:
+//   - movement: after the females and after the males have moved, the moves
+//     are ranked by their improvement on the current cost of the mayfly;
+//     mayflies that are not evaluated return to their previous position
+//     (standard and MPMA movement)
+//   - offspring and DESMA elites: candidates are ranked by their improvement
+//     on the global best; the others are discarded
+//
+// In addition, every iteration the best of surrogateInfillSamples random
+// points is evaluated as an extra offspring, so the model itself proposes a
+// candidate (as in efficient global optimization). Half of the points cover
+// the box of the training set, the others are Gaussian perturbations of the
+// global best with radii shrinking from half the box down to 1/2000 of it.
+//
+// Models (Config.SurrogateModel):
+//   - "rbf": cubic radial basis function interpolation with a linear tail;
+//     σ grows with the distance to the nearest training point
+//   - "kriging": Gaussian process with a Gaussian kernel and a constant
+//     mean, whose length scale maximizes the likelihood
+//
+// Infill criteria (Config.InfillCriterion):
+//   - "ei": expected improvement over the global best (Jones et al., 1998)
+//   - "lcb": lower confidence bound μ - κσ
+//
+// Populations only hold truly evaluated costs, so Result.GlobalBest and
+// Result.FuncEvalCount refer to the true objective function. The model is
+// retrained at most once per iteration.
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Surrogate model names accepted by Config.SurrogateModel.
+const (
+	SurrogateRBF     = "rbf"
+	SurrogateKriging = "kriging"
+)
+
+// Infill criterion names accepted by Config.InfillCriterion.
+const (
+	InfillExpectedImprovement  = "ei"
+	InfillLowerConfidenceBound = "lcb"
+)
+
+// defaultSurrogateSamples is the training set size when
+// Config.SurrogateSamples is zero.
+const defaultSurrogateSamples = 50
+
+// surrogateInfillSamples is the number of random points that are scored by
+// the infill criterion to find the model-proposed candidate of an iteration.
+const surrogateInfillSamples = 200
+
+// krigingNugget is added to the diagonal of the correlation matrix to keep
+// it well conditioned.
+const krigingNugget = 1e-6
+
+// validateSurrogateConfig checks the surrogate model, the infill criterion
+// and their parameters.
+func validateSurrogateConfig(config *Config) error {
+	switch config.SurrogateModel {
+	case "", SurrogateRBF, SurrogateKriging:
+	default:
+		return fmt.Errorf("unknown surrogate model '%s' (expected rbf or kriging)", config.SurrogateModel)
+	}
+
+	switch config.InfillCriterion {
+	case "", InfillExpectedImprovement, InfillLowerConfidenceBound:
+	default:
+		return fmt.Errorf("unknown infill criterion '%s' (expected ei or lcb)", config.InfillCriterion)
+	}
+
+	if config.SurrogateSamples < 0 {
+		return fmt.Errorf("SurrogateSamples must be non-negative, got %d", config.SurrogateSamples)
+	}
+
+	if config.SurrogateFraction < 0 || config.SurrogateFraction > 1 {
+		return fmt.Errorf("SurrogateFraction must be in [0, 1], got %v", config.SurrogateFraction)
+	}
+
+	if config.LCBKappa < 0 {
+		return fmt.Errorf("LCBKappa must be non-negative, got %v", config.LCBKappa)
+	}
+
+	return nil
+}
+
+// surrogateModel predicts the cost of a position in normalized coordinates.
+type surrogateModel interface {
+	predict(u []float64) (mean, sigma float64)
+}
+
+// surrogateController keeps the archive of true evaluations and decides
+// which candidates are evaluated.
+type surrogateController struct {
+	model    string
+	infill   string
+	kappa    float64
+	fraction float64
+	samples  int
+	lower    float64
+	upper    float64
+
+	positions [][]float64 // Archive of truly evaluated positions
+	costs     []float64
+
+	trained   surrogateModel // nil if too few samples or training failed
+	trainedAt int            // Iteration of the last training
+	origin    []float64      // Local frame of the training set
+	span      float64
+	skipped   int // Candidates discarded without a true evaluation
+
+	moves []deferredMove // Moves of the current movement phase
+}
+
+// deferredMove is a moved mayfly that has not been evaluated yet.
+type deferredMove struct {
+	mayfly   *Mayfly
+	previous []float64
+}
+
+// newSurrogateController creates the surrogate component for config.
+func newSurrogateController(config *Config) *surrogateController {
+	c := &surrogateController{
+		model:    config.SurrogateModel,
+		infill:   config.InfillCriterion,
+		kappa:    config.LCBKappa,
+		fraction: config.SurrogateFraction,
+		samples:  config.SurrogateSamples,
+		lower:    config.LowerBound,
+		upper:    config.UpperBound,
+	}
+
+	if c.model == "" {
+		c.model = SurrogateRBF
+	}
+
+	if c.infill == "" {
+		c.infill = InfillExpectedImprovement
+	}
+
+	if c.samples == 0 {
+		c.samples = defaultSurrogateSamples
+	}
+
+	// The linear tail of the RBF model needs more samples than dimensions
+	c.samples = max(c.samples, 2*(config.ProblemSize+1))
+	c.trainedAt = -1
+
+	return c
+}
+
+// record adds a true evaluation to the archive.
+func (c *surrogateController) record(position []float64, cost float64) {
+	c.positions = append(c.positions, c.normalize(position))
+	c.costs = append(c.costs, sanitizeCost(cost))
+}
+
+// normalize maps a position to [0, 1]^n.
+func (c *surrogateController) normalize(position []float64) []float64 {
+	u := make([]float64, len(position))
+	for j, v := range position {
+		u[j] = (v - c.lower) / (c.upper - c.lower)
+	}
+
+	return u
+}
+
+// current returns the model for the current iteration, training it on the
+// archive points closest to the global best if necessary.
+func (c *surrogateController) current(s *State) surrogateModel {
+	if c.trainedAt == s.Iteration {
+		return c.trained
+	}
+
+	c.trainedAt, c.trained = s.Iteration, nil
+
+	xs, ys := c.trainingSet(c.normalize(s.GlobalBest.Position))
+	if len(xs) == 0 || len(xs) < len(xs[0])+2 {
+		return nil
+	}
+
+	// Near convergence the training set is tiny compared to the search
+	// space, so the models work in a frame scaled to its extent
+	c.origin, c.span = append([]float64(nil), xs[0]...), 0
+	for _, x := range xs {
+		for j, v := range x {
+			c.origin[j] = math.Min(c.origin[j], v)
+		}
+	}
+
+	for _, x := range xs {
+		for j, v := range x {
+			c.span = math.Max(c.span, v-c.origin[j])
+		}
+	}
+
+	local := make([][]float64, len(xs))
+	for i, x := range xs {
+		local[i] = c.local(x)
+	}
+
+	xs = local
+
+	var ok bool
+	if c.model == SurrogateKriging {
+		c.trained, ok = fitKriging(xs, ys)
+	} else {
+		c.trained, ok = fitRBF(xs, ys)
+	}
+
+	if !ok {
+		c.trained = nil
+	}
+
+	return c.trained
+}
+
+// local maps a normalized position to the frame of the training set.
+func (c *surrogateController) local(u []float64) []float64 {
+	v := make([]float64, len(u))
+	for j := range u {
+		v[j] = (u[j] - c.origin[j]) / c.span
+	}
+
+	return v
+}
+
+// trainingSet returns up to c.samples distinct archive points, the closest
+// to center first.
+func (c *surrogateController) trainingSet(center []float64) ([][]float64, []float64) {
+	order := make([]int, len(c.positions))
+	distances := make([]float64, len(c.positions))
+
+	for i, p := range c.positions {
+		order[i] = i
+		distances[i] = euclideanDistance(p, center)
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return distances[order[a]] < distances[order[b]]
+	})
+
+	xs := make([][]float64, 0, c.samples)
+	ys := make([]float64, 0, c.samples)
+
+	for _, i := range order {
+		if len(xs) == c.samples {
+			break
+		}
+
+		duplicate := false
+		for _, x := range xs {
+			if euclideanDistance(x, c.positions[i]) < 1e-9 {
+				duplicate = true
+				break
+			}
+		}
+
+		if !duplicate {
+			xs = append(xs, c.positions[i])
+			ys = append(ys, c.costs[i])
+		}
+	}
+
+	return xs, ys
+}
+
+// deferMove records a moved mayfly whose evaluation is left to settleMoves.
+func (c *surrogateController) deferMove(mayfly *Mayfly, previous []float64) {
+	c.moves = append(c.moves, deferredMove{mayfly: mayfly, previous: previous})
+}
+
+// settleMoves evaluates the deferred moves that the surrogate selects and
+// returns the other mayflies to their previous positions. Personal and
+// global bests are updated if updateBest is set (males).
+func (c *surrogateController) settleMoves(s *State, updateBest bool) {
+	moves := c.moves
+	c.moves = nil
+
+	positions := make([][]float64, len(moves))
+	references := make([]float64, len(moves))
+
+	for i, move := range moves {
+		positions[i] = move.mayfly.Position
+		references[i] = move.mayfly.Cost
+	}
+
+	selected := make(map[int]bool)
+	for _, i := range c.screen(s, positions, references) {
+		selected[i] = true
+	}
+
+	for i, move := range moves {
+		if !selected[i] {
+			copy(move.mayfly.Position, move.previous)
+			continue
+		}
+
+		move.mayfly.Cost = s.Evaluate(move.mayfly.Position)
+		if updateBest {
+			updatePersonalBest(s, move.mayfly)
+		}
+	}
+}
+
+// screen returns the indices of the candidate positions that should be
+// evaluated, in ascending order: the best SurrogateFraction of them by the
+// infill criterion, at least one. Candidate i is scored by its improvement
+// on references[i], or on the global best if references is nil. Without a
+// trained model every candidate is evaluated.
+func (c *surrogateController) screen(s *State, candidates [][]float64, references []float64) []int {
+	selected := make([]int, len(candidates))
+	for i := range selected {
+		selected[i] = i
+	}
+
+	model := c.current(s)
+	if model == nil || len(candidates) == 0 {
+		return selected
+	}
+
+	scores := make([]float64, len(candidates))
+	for i, position := range candidates {
+		reference := s.GlobalBest.Cost
+		if references != nil {
+			reference = references[i]
+		}
+
+		mean, sigma := model.predict(c.local(c.normalize(position)))
+		scores[i] = c.infillScore(mean, sigma, reference)
+	}
+
+	sort.SliceStable(selected, func(a, b int) bool {
+		return scores[selected[a]] > scores[selected[b]]
+	})
+
+	count := max(1, int(math.Ceil(c.fraction*float64(len(candidates)))))
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+
+	c.skipped += len(candidates) - count
+	selected = selected[:count]
+	sort.Ints(selected)
+
+	return selected
+}
+
+// infillPoint returns the best of surrogateInfillSamples random positions
+// in the box of the training set and around the global best by the infill
+// criterion, or nil without a trained model.
+func (c *surrogateController) infillPoint(s *State) []float64 {
+	model := c.current(s)
+	if model == nil {
+		return nil
+	}
+
+	var best []float64
+
+	bestScore := math.Inf(-1)
+
+	center := c.normalize(s.GlobalBest.Position)
+
+	for k := 0; k < surrogateInfillSamples; k++ {
+		u := make([]float64, len(c.origin))
+		if k%2 == 0 {
+			for j := range u {
+				u[j] = math.Min(1, c.origin[j]+c.span*s.Rand.Float64())
+			}
+		} else {
+			// Radii of span/2, span/20, span/200 and span/2000
+			scale := 0.5 * c.span * math.Pow(0.1, float64((k/2)%4))
+			for j := range u {
+				u[j] = math.Max(0, math.Min(1, center[j]+scale*s.Rand.NormFloat64()))
+			}
+		}
+
+		mean, sigma := model.predict(c.local(u))
+		if score := c.infillScore(mean, sigma, s.GlobalBest.Cost); score > bestScore {
+			best, bestScore = u, score
+		}
+	}
+
+	position := make([]float64, len(best))
+	for j, v := range best {
+		position[j] = c.lower + v*(c.upper-c.lower)
+	}
+
+	return position
+}
+
+// infillScore returns the value of the infill criterion for a prediction
+// relative to the cost to improve on (higher is better).
+func (c *surrogateController) infillScore(mean, sigma, reference float64) float64 {
+	if c.infill == InfillLowerConfidenceBound {
+		return reference - (mean - c.kappa*sigma)
+	}
+
+	return expectedImprovement(mean, sigma, reference)
+}
+
+// expectedImprovement returns E[max(best - Y, 0)] for Y ~ N(mean, sigma²).
+func expectedImprovement(mean, sigma, best float64) float64 {
+	if sigma <= 1e-12 {
+		return math.Max(best-mean, 0)
+	}
+
+	z := (best - mean) / sigma
+	cdf := 0.5 * math.Erfc(-z/math.Sqrt2)
+	pdf := math.Exp(-0.5*z*z) / math.Sqrt(2*math.Pi)
+
+	return (best-mean)*cdf + sigma*pdf
+}
+
+// standardize returns (y - mean) / std and the mean and std of ys. A
+// constant ys has std 1.
+func standardize(ys []float64) ([]float64, float64, float64) {
+	mean := 0.0
+	for _, y := range ys {
+		mean += y
+	}
+
+	mean /= float64(len(ys))
+
+	variance := 0.0
+	for _, y := range ys {
+		variance += (y - mean) * (y - mean)
+	}
+
+	std := math.Sqrt(variance / float64(len(ys)))
+	if std < 1e-12 {
+		std = 1
+	}
+
+	z := make([]float64, len(ys))
+	for i, y := range ys {
+		z[i] = (y - mean) / std
+	}
+
+	return z, mean, std
+}
+
+// =============================================================================
+// Radial basis functions
+// =============================================================================
+
+// rbfModel interpolates the training costs with cubic radial basis
+// functions and a linear polynomial tail.
+type rbfModel struct {
+	centers [][]float64
+	weights []float64 // One per center
+	tail    []float64 // Constant, then one coefficient per dimension
+	mean    float64
+	std     float64
+	spacing float64 // Mean nearest-neighbour distance of the centers
+}
+
+// fitRBF solves the interpolation system of a cubic RBF model.
+func fitRBF(xs [][]float64, ys []float64) (*rbfModel, bool) {
+	n, d := len(xs), len(xs[0])
+	z, mean, std := standardize(ys)
+
+	size := n + d + 1
+	a := make([][]float64, size)
+	b := make([]float64, size)
+
+	for i := range a {
+		a[i] = make([]float64, size)
+	}
+
+	for i := 0; i < n; i++ {
+		for k := 0; k < n; k++ {
+			r := euclideanDistance(xs[i], xs[k])
+			a[i][k] = r * r * r
+		}
+
+		a[i][n], a[n][i] = 1, 1
+		for j := 0; j < d; j++ {
+			a[i][n+1+j], a[n+1+j][i] = xs[i][j], xs[i][j]
+		}
+
+		b[i] = z[i]
+	}
+
+	solution, ok := solveLinearSystem(a, b)
+	if !ok {
+		return nil, false
+	}
+
+	spacing := 0.0
+	for i := range xs {
+		spacing += nearestPointDistance(xs[i], xs, i)
+	}
+
+	return &rbfModel{
+		centers: xs,
+		weights: solution[:n],
+		tail:    solution[n:],
+		mean:    mean,
+		std:     std,
+		spacing: spacing / float64(n),
+	}, true
+}
+
+// predict returns the interpolated cost and a distance-based uncertainty
+// that is 0 at the centers and approaches the standard deviation of the
+// training costs far from them.
+func (m *rbfModel) predict(u []float64) (float64, float64) {
+	value := m.tail[0]
+	for j, v := range u {
+		value += m.tail[1+j] * v
+	}
+
+	nearest := math.Inf(1)
+	for i, center := range m.centers {
+		r := euclideanDistance(u, center)
+		value += m.weights[i] * r * r * r
+		nearest = math.Min(nearest, r)
+	}
+
+	return m.mean + m.std*value, m.std * nearest / (nearest + m.spacing)
+}
+
+// nearestPointDistance returns the distance from p to the closest point of
+// points, skipping index skip.
+func nearestPointDistance(p []float64, points [][]float64, skip int) float64 {
+	nearest := math.Inf(1)
+	for i, q := range points {
+		if i != skip {
+			nearest = math.Min(nearest, euclideanDistance(p, q))
+		}
+	}
+
+	return nearest
+}
+
+// =============================================================================
+// Kriging
+// =============================================================================
+
+// krigingModel is a Gaussian process with correlation
+// exp(-‖u - v‖² / (2ℓ²)) and a constant mean.
+type krigingModel struct {
+	points   [][]float64
+	chol     [][]float64 // Cholesky factor of the correlation matrix
+	alpha    []float64   // R⁻¹ (z - constant)
+	constant float64     // Mean of the standardized costs
+	variance float64     // Process variance of the standardized costs
+	length   float64
+	mean     float64
+	std      float64
+}
+
+// fitKriging chooses the length scale of maximum likelihood from a grid
+// relative to the diagonal of the unit cube and fits the model.
+func fitKriging(xs [][]float64, ys []float64) (*krigingModel, bool) {
+	z, mean, std := standardize(ys)
+
+	var best *krigingModel
+
+	bestLikelihood := math.Inf(-1)
+	diagonal := math.Sqrt(float64(len(xs[0])))
+
+	for _, factor := range []float64{0.02, 0.05, 0.1, 0.2, 0.5, 1} {
+		model, likelihood, ok := fitKrigingLength(xs, z, factor*diagonal)
+		if ok && likelihood > bestLikelihood {
+			best, bestLikelihood = model, likelihood
+		}
+	}
+
+	if best == nil {
+		return nil, false
+	}
+
+	best.mean, best.std = mean, std
+
+	return best, true
+}
+
+// fitKrigingLength fits the model for length scale ℓ and returns its
+// concentrated log-likelihood.
+func fitKrigingLength(xs [][]float64, z []float64, length float64) (*krigingModel, float64, bool) {
+	n := len(xs)
+	r := make([][]float64, n)
+
+	for i := range r {
+		r[i] = make([]float64, n)
+		for k := range r[i] {
+			r[i][k] = gaussianCorrelation(xs[i], xs[k], length)
+		}
+
+		r[i][i] += krigingNugget
+	}
+
+	chol, ok := cholesky(r)
+	if !ok {
+		return nil, 0, false
+	}
+
+	constant := 0.0
+	for _, v := range z {
+		constant += v
+	}
+
+	constant /= float64(n)
+
+	residual := make([]float64, n)
+	for i, v := range z {
+		residual[i] = v - constant
+	}
+
+	alpha := choleskySolve(chol, residual)
+	variance := dot(residual, alpha) / float64(n)
+
+	if variance <= 0 {
+		return nil, 0, false
+	}
+
+	logDet := 0.0
+	for i := range chol {
+		logDet += 2 * math.Log(chol[i][i])
+	}
+
+	model := &krigingModel{
+		points:   xs,
+		chol:     chol,
+		alpha:    alpha,
+		constant: constant,
+		variance: variance,
+		length:   length,
+	}
+
+	return model, -0.5*float64(n)*math.Log(variance) - 0.5*logDet, true
+}
+
+// predict returns the kriging mean and standard deviation.
+func (m *krigingModel) predict(u []float64) (float64, float64) {
+	k := make([]float64, len(m.points))
+	for i, p := range m.points {
+		k[i] = gaussianCorrelation(u, p, m.length)
+	}
+
+	v := forwardSubstitute(m.chol, k)
+	variance := m.variance * math.Max(0, 1+krigingNugget-dot(v, v))
+
+	return m.mean + m.std*(m.constant+dot(k, m.alpha)), m.std * math.Sqrt(variance)
+}
+
+// gaussianCorrelation returns exp(-‖a - b‖² / (2ℓ²)).
+func gaussianCorrelation(a, b []float64, length float64) float64 {
+	d := euclideanDistance(a, b)
+	return math.Exp(-d * d / (2 * length * length))
+}
+
+// cholesky returns the lower triangular L with L·Lᵀ = a, or false if a is
+// not positive definite.
+func cholesky(a [][]float64) ([][]float64, bool) {
+	n := len(a)
+	l := make([][]float64, n)
+
+	for i := range l {
+		l[i] = make([]float64, n)
+
+		for k := 0; k <= i; k++ {
+			sum := a[i][k]
+			for j := 0; j < k; j++ {
+				sum -= l[i][j] * l[k][j]
+			}
+
+			if i == k {
+				if sum <= 0 {
+					return nil, false
+				}
+
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][k] = sum / l[k][k]
+			}
+		}
+	}
+
+	return l, true
+}
+
+// forwardSubstitute solves L·x = b for lower triangular L.
+func forwardSubstitute(l [][]float64, b []float64) []float64 {
+	x := make([]float64, len(b))
+	for i := range x {
+		sum := b[i]
+		for j := 0; j < i; j++ {
+			sum -= l[i][j] * x[j]
+		}
+
+		x[i] = sum / l[i][i]
+	}
+
+	return x
+}
+
+// choleskySolve solves L·Lᵀ·x = b.
+func choleskySolve(l [][]float64, b []float64) []float64 {
+	y := forwardSubstitute(l, b)
+
+	x := make([]float64, len(y))
+	for i := len(y) - 1; i >= 0; i-- {
+		sum := y[i]
+		for j := i + 1; j < len(y); j++ {
+			sum -= l[j][i] * x[j]
+		}
+
+		x[i] = sum / l[i][i]
+	}
+
+	return x
+}
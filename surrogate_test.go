@@ -0,0 +1,316 @@
+package mayfly
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// =============================================================================
+// Tests for surrogate.go - Surrogate-assisted optimization
+// =============================================================================
+
+func newSurrogateTestConfig(fn ObjectiveFunction, model string, seed int64) *Config {
+	config := newTestConfig(fn, 5, 10, 300, seed)
+
+	if model != "" {
+		config.UseSurrogate = true
+		config.SurrogateModel = model
+	}
+
+	return config
+}
+
+// evalsToTarget runs config and returns the number of true evaluations
+// until the cost first fell below target, or -1 if it never did.
+func evalsToTarget(t *testing.T, config *Config, target float64) int {
+	t.Helper()
+
+	fn := config.ObjectiveFunc
+	evals, reached := 0, -1
+	config.ObjectiveFunc = func(x []float64) float64 {
+		evals++
+		cost := fn(x)
+
+		if cost < target && reached < 0 {
+			reached = evals
+		}
+
+		return cost
+	}
+
+	if _, err := Optimize(config); err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	return reached
+}
+
+func TestValidateSurrogateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"unknown model", func(c *Config) { c.SurrogateModel = "svm" }},
+		{"unknown infill", func(c *Config) { c.InfillCriterion = "pi" }},
+		{"negative samples", func(c *Config) { c.SurrogateSamples = -1 }},
+		{"fraction above 1", func(c *Config) { c.SurrogateFraction = 1.5 }},
+		{"negative kappa", func(c *Config) { c.LCBKappa = -1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newSurrogateTestConfig(Sphere, SurrogateRBF, 1)
+			tt.modify(config)
+
+			if _, err := Optimize(config); err == nil {
+				t.Error("Expected a validation error")
+			}
+		})
+	}
+}
+
+func TestSurrogateControllerParameters(t *testing.T) {
+	config := newSurrogateTestConfig(Sphere, SurrogateRBF, 1)
+
+	if c := newSurrogateController(config); c.kappa != 2 || c.fraction != 0.1 {
+		t.Errorf("Expected the defaults κ = 2 and fraction 0.1, got %v and %v", c.kappa, c.fraction)
+	}
+
+	// κ = 0 ranks by the prediction alone, fraction 0 evaluates one candidate per batch
+	config.InfillCriterion = InfillLowerConfidenceBound
+	config.LCBKappa = 0
+	config.SurrogateFraction = 0
+
+	c := newSurrogateController(config)
+	if c.kappa != 0 || c.fraction != 0 {
+		t.Fatalf("Explicit zeros replaced: κ = %v, fraction %v", c.kappa, c.fraction)
+	}
+
+	if score := c.infillScore(1, 5, 3); score != 2 {
+		t.Errorf("Expected the plain improvement 2 for κ = 0, got %g", score)
+	}
+}
+
+func TestExpectedImprovement(t *testing.T) {
+	// Without uncertainty EI is the plain improvement
+	if ei := expectedImprovement(1, 0, 3); ei != 2 {
+		t.Errorf("Expected 2, got %g", ei)
+	}
+
+	if ei := expectedImprovement(3, 0, 1); ei != 0 {
+		t.Errorf("Expected 0, got %g", ei)
+	}
+
+	// At mean == best EI is σ φ(0)
+	if ei := expectedImprovement(1, 2, 1); math.Abs(ei-2/math.Sqrt(2*math.Pi)) > 1e-12 {
+		t.Errorf("Expected σ/√(2π), got %g", ei)
+	}
+
+	// Uncertainty makes a worse mean worth evaluating
+	if expectedImprovement(2, 1, 1) <= expectedImprovement(2, 0.1, 1) {
+		t.Error("EI should grow with σ for a worse mean")
+	}
+}
+
+func TestCholeskySolve(t *testing.T) {
+	a := [][]float64{{4, 2, 0.4}, {2, 5, 1}, {0.4, 1, 3}}
+	b := []float64{1, 2, 3}
+
+	l, ok := cholesky(a)
+	if !ok {
+		t.Fatal("Cholesky failed on a positive definite matrix")
+	}
+
+	x := choleskySolve(l, b)
+	for i, row := range a {
+		if got := dot(row, x); math.Abs(got-b[i]) > 1e-12 {
+			t.Errorf("Row %d: A x = %g, expected %g", i, got, b[i])
+		}
+	}
+
+	if _, ok := cholesky([][]float64{{1, 2}, {2, 1}}); ok {
+		t.Error("Cholesky should fail on an indefinite matrix")
+	}
+}
+
+func TestSurrogateModels(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	f := func(u []float64) float64 { return (u[0]-0.3)*(u[0]-0.3) + 2*(u[1]-0.6)*(u[1]-0.6) }
+
+	xs := make([][]float64, 40)
+	ys := make([]float64, len(xs))
+
+	for i := range xs {
+		xs[i] = []float64{rng.Float64(), rng.Float64()}
+		ys[i] = f(xs[i])
+	}
+
+	rbf, ok := fitRBF(xs, ys)
+	if !ok {
+		t.Fatal("RBF fit failed")
+	}
+
+	kriging, ok := fitKriging(xs, ys)
+	if !ok {
+		t.Fatal("Kriging fit failed")
+	}
+
+	for name, model := range map[string]surrogateModel{"rbf": rbf, "kriging": kriging} {
+		t.Run(name, func(t *testing.T) {
+			// Interpolation with (almost) no uncertainty at the training points
+			for i, x := range xs {
+				mean, sigma := model.predict(x)
+				if math.Abs(mean-ys[i]) > 1e-3 || sigma > 1e-2 {
+					t.Fatalf("Training point %d: predicted %g ± %g, expected %g", i, mean, sigma, ys[i])
+				}
+			}
+
+			// Accurate predictions in between
+			for k := 0; k < 20; k++ {
+				u := []float64{0.2 + 0.6*rng.Float64(), 0.2 + 0.6*rng.Float64()}
+				if mean, _ := model.predict(u); math.Abs(mean-f(u)) > 0.05 {
+					t.Errorf("Predicted %g at %v, expected %g", mean, u, f(u))
+				}
+			}
+
+			// More uncertainty away from the data
+			_, near := model.predict([]float64{0.5, 0.5})
+			_, far := model.predict([]float64{3, 3})
+
+			if far <= near {
+				t.Errorf("Expected σ to grow away from the data, got %g near and %g far", near, far)
+			}
+		})
+	}
+}
+
+func TestSurrogateReducesEvaluations(t *testing.T) {
+	const target = 1e-3
+
+	// Documented reductions of the evaluations to reach 1e-3 on Sphere
+	tests := []struct {
+		model     string
+		dimension int
+		factor    float64
+	}{
+		{SurrogateRBF, 5, 3},
+		{SurrogateRBF, 10, 5},
+		{SurrogateKriging, 5, 2.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s-%dD", tt.model, tt.dimension), func(t *testing.T) {
+			baseline, assisted := 0, 0
+
+			for seed := int64(1); seed <= 3; seed++ {
+				config := newSurrogateTestConfig(Sphere, "", seed)
+				config.ProblemSize = tt.dimension
+				b := evalsToTarget(t, config, target)
+
+				config = newSurrogateTestConfig(Sphere, tt.model, seed)
+				config.ProblemSize = tt.dimension
+				a := evalsToTarget(t, config, target)
+
+				if b < 0 || a < 0 {
+					t.Fatalf("Seed %d: target not reached, baseline %d, surrogate %d", seed, b, a)
+				}
+
+				baseline += b
+				assisted += a
+			}
+
+			if tt.factor*float64(assisted) > float64(baseline) {
+				t.Errorf("Expected %gx fewer evaluations, got %d with surrogate and %d without",
+					tt.factor, assisted, baseline)
+			}
+		})
+	}
+}
+
+// bestParentRecorder is a mutation operator that checks whether its parent
+// is the best evaluated male or female.
+type bestParentRecorder struct {
+	calls, best int
+}
+
+func (r *bestParentRecorder) Mutate(s *State, x []float64) []float64 {
+	r.calls++
+
+	best := s.Males[0]
+	for _, m := range append(s.Males, s.Females...) {
+		if m.Cost < best.Cost {
+			best = m
+		}
+	}
+
+	if euclideanDistance(x, best.Position) == 0 {
+		r.best++
+	}
+
+	return append([]float64(nil), x...)
+}
+
+func TestSurrogateMutationParent(t *testing.T) {
+	config := newSurrogateTestConfig(Sphere, SurrogateRBF, 1)
+	config.MaxIterations = 30
+	config.NM = 3
+
+	recorder := &bestParentRecorder{}
+	pipeline := NewPipeline(config)
+	pipeline.Mutation = recorder
+	pipeline.MutationParents = BestMutationParent{}
+
+	if _, err := OptimizeWithPipeline(config, pipeline); err != nil {
+		t.Fatalf("OptimizeWithPipeline failed: %v", err)
+	}
+
+	// Crossover offspring are unevaluated, so the best parent is chosen
+	// among the males and females
+	if recorder.calls == 0 || recorder.best != recorder.calls {
+		t.Errorf("The best evaluated mayfly was the parent of %d of %d mutants", recorder.best, recorder.calls)
+	}
+}
+
+func TestSurrogateResult(t *testing.T) {
+	for _, infill := range []string{InfillExpectedImprovement, InfillLowerConfidenceBound} {
+		t.Run(infill, func(t *testing.T) {
+			run := func() *Result {
+				config := newSurrogateTestConfig(Rosenbrock, SurrogateRBF, 2)
+				config.InfillCriterion = infill
+				config.MaxIterations = 100
+
+				result, err := Optimize(config)
+				if err != nil {
+					t.Fatalf("Optimize failed: %v", err)
+				}
+
+				return result
+			}
+
+			result := run()
+			if result.SurrogateSkipped == 0 {
+				t.Error("Expected skipped candidates")
+			}
+
+			if got := Rosenbrock(result.GlobalBest.Position); got != result.GlobalBest.Cost {
+				t.Errorf("Global best cost %g is not the true cost %g", result.GlobalBest.Cost, got)
+			}
+
+			again := run()
+			if again.GlobalBest.Cost != result.GlobalBest.Cost || again.FuncEvalCount != result.FuncEvalCount {
+				t.Error("Seeded surrogate runs are not reproducible")
+			}
+		})
+	}
+
+	result, err := Optimize(newSurrogateTestConfig(Sphere, "", 1))
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if result.SurrogateSkipped != 0 {
+		t.Errorf("Expected no skipped candidates without surrogate, got %d", result.SurrogateSkipped)
+	}
+}
@@ -24,6 +24,10 @@ import (
 // It takes a position vector and returns a fitness cost.
 type ObjectiveFunction func([]float64) float64
 
+// GradientFunction returns the gradient of an ObjectiveFunction at a
+// position vector.
+type GradientFunction func([]float64) []float64
+
 // Best represents the best position and cost found.
 type Best struct {
 	Position []float64
@@ -36,71 +40,126 @@ type Mayfly struct {
 	Velocity []float64
 	Best     Best
 	Cost     float64
+	Sigma    float64 // Self-adaptive mutation step size (0 = not yet assigned)
 }
 
 // Config holds the configuration parameters for the Mayfly Algorithm.
 type Config struct {
-	ObjectiveFunc         ObjectiveFunction `json:"-"`
-	Rand                  *rand.Rand        `json:"-"`
-	CoolingSchedule       string            `json:"cooling_schedule"`
-	GravityType           string            `json:"gravity_type"`
-	ReductionFactor       float64           `json:"reduction_factor"`
-	Dance                 float64           `json:"dance"`
-	NPop                  int               `json:"npop"`
-	NPopF                 int               `json:"npopf"`
-	G                     float64           `json:"g"`
-	GDamp                 float64           `json:"g_damp"`
-	A1                    float64           `json:"a1"`
-	A2                    float64           `json:"a2"`
-	A3                    float64           `json:"a3"`
-	ChaosFactor           float64           `json:"chaos_factor"`
-	OrthogonalFactor      float64           `json:"orthogonal_factor"`
-	FL                    float64           `json:"fl"`
-	DanceDamp             float64           `json:"dance_damp"`
-	FLDamp                float64           `json:"fl_damp"`
-	NC                    int               `json:"nc"`
-	NM                    int               `json:"nm"`
-	Mu                    float64           `json:"mu"`
-	VelMax                float64           `json:"vel_max"`
-	VelMin                float64           `json:"vel_min"`
-	EliteCount            int               `json:"elite_count"`
-	SearchRange           float64           `json:"search_range"`
-	EnlargeFactor         float64           `json:"enlarge_factor"`
-	MaxIterations         int               `json:"max_iterations"`
-	UpperBound            float64           `json:"upper_bound"`
-	Beta                  float64           `json:"beta"`
-	LevyAlpha             float64           `json:"levy_alpha"`
-	StrategySwitch        int               `json:"strategy_switch"`
-	ArchiveSize           int               `json:"archive_size"`
-	LevyBeta              float64           `json:"levy_beta"`
-	OppositionRate        float64           `json:"opposition_rate"`
-	EliteOppositionCount  int               `json:"elite_opposition_count"`
-	OppositionProbability float64           `json:"opposition_probability"`
-	AquilaWeight          float64           `json:"aquila_weight"`
-	MedianWeight          float64           `json:"median_weight"`
-	LowerBound            float64           `json:"lower_bound"`
-	ProblemSize           int               `json:"problem_size"`
-	GoldenFactor          float64           `json:"golden_factor"`
-	InitialTemperature    float64           `json:"initial_temperature"`
-	CoolingRate           float64           `json:"cooling_rate"`
-	CauchyMutationRate    float64           `json:"cauchy_mutation_rate"`
-	UseGSASMA             bool              `json:"use_gsasma"`
-	UseWeightedMedian     bool              `json:"use_weighted_median"`
-	ApplyOBLToGlobalBest  bool              `json:"apply_obl_to_global_best"`
-	UseAOBLMOA            bool              `json:"use_aoblmoa"`
-	UseMPMA               bool              `json:"use_mpma"`
-	UseEOBBMA             bool              `json:"use_eobbma"`
-	UseOLCE               bool              `json:"use_olce"`
-	UseDESMA              bool              `json:"use_desma"`
+	ObjectiveFunc           ObjectiveFunction  `json:"-"`
+	ConstraintFunc          ConstraintFunction `json:"-"` // Used by OptimizeMulti; rejected by Optimize
+	Gradient                GradientFunction   `json:"-"` // Optional gradient of ObjectiveFunc
+	Rand                    *rand.Rand         `json:"-"`
+	CoolingSchedule         string             `json:"cooling_schedule"`
+	GravityType             string             `json:"gravity_type"`
+	ReductionFactor         float64            `json:"reduction_factor"`
+	Dance                   float64            `json:"dance"`
+	NPop                    int                `json:"npop"`
+	NPopF                   int                `json:"npopf"`
+	G                       float64            `json:"g"`
+	GDamp                   float64            `json:"g_damp"`
+	A1                      float64            `json:"a1"`
+	A2                      float64            `json:"a2"`
+	A3                      float64            `json:"a3"`
+	ChaosFactor             float64            `json:"chaos_factor"`
+	OrthogonalFactor        float64            `json:"orthogonal_factor"`
+	FL                      float64            `json:"fl"`
+	DanceDamp               float64            `json:"dance_damp"`
+	FLDamp                  float64            `json:"fl_damp"`
+	NC                      int                `json:"nc"`
+	NM                      int                `json:"nm"`
+	Mu                      float64            `json:"mu"`
+	VelMax                  float64            `json:"vel_max"`
+	VelMin                  float64            `json:"vel_min"`
+	EliteCount              int                `json:"elite_count"`
+	SearchRange             float64            `json:"search_range"`
+	EnlargeFactor           float64            `json:"enlarge_factor"`
+	MaxIterations           int                `json:"max_iterations"`
+	UpperBound              float64            `json:"upper_bound"`
+	Beta                    float64            `json:"beta"`
+	LevyAlpha               float64            `json:"levy_alpha"`
+	StrategySwitch          int                `json:"strategy_switch"`
+	ArchiveSize             int                `json:"archive_size"`
+	LevyBeta                float64            `json:"levy_beta"`
+	OppositionRate          float64            `json:"opposition_rate"`
+	EliteOppositionCount    int                `json:"elite_opposition_count"`
+	OppositionProbability   float64            `json:"opposition_probability"`
+	AquilaWeight            float64            `json:"aquila_weight"`
+	MedianWeight            float64            `json:"median_weight"`
+	LowerBound              float64            `json:"lower_bound"`
+	ProblemSize             int                `json:"problem_size"`
+	GoldenFactor            float64            `json:"golden_factor"`
+	InitialTemperature      float64            `json:"initial_temperature"`
+	CoolingRate             float64            `json:"cooling_rate"`
+	CauchyMutationRate      float64            `json:"cauchy_mutation_rate"`
+	UseGSASMA               bool               `json:"use_gsasma"`
+	UseWeightedMedian       bool               `json:"use_weighted_median"`
+	ApplyOBLToGlobalBest    bool               `json:"apply_obl_to_global_best"`
+	UseAOBLMOA              bool               `json:"use_aoblmoa"`
+	UseMPMA                 bool               `json:"use_mpma"`
+	UseEOBBMA               bool               `json:"use_eobbma"`
+	UseOLCE                 bool               `json:"use_olce"`
+	UseDESMA                bool               `json:"use_desma"`
+	CrossoverType           string             `json:"crossover_type"`
+	CrossoverEta            float64            `json:"crossover_eta"`
+	CrossoverAlpha          float64            `json:"crossover_alpha"`
+	DEScaleFactor           float64            `json:"de_scale_factor"`
+	DECrossoverRate         float64            `json:"de_crossover_rate"`
+	CrossoverParents        int                `json:"crossover_parents"`
+	MutationType            string             `json:"mutation_type"`
+	MutationEta             float64            `json:"mutation_eta"`
+	NonUniformShape         float64            `json:"nonuniform_shape"`
+	MutationSigma           float64            `json:"mutation_sigma"`
+	MutationTau             float64            `json:"mutation_tau"`
+	ParentSelection         string             `json:"parent_selection"`
+	MutationParentSelection string             `json:"mutation_parent_selection"`
+	TournamentSize          int                `json:"tournament_size"`
+	RestartStrategy         string             `json:"restart_strategy"`
+	RestartStagnation       int                `json:"restart_stagnation"`
+	RestartDiversity        float64            `json:"restart_diversity"`
+	RestartPopulationFactor float64            `json:"restart_population_factor"`
+	MaxRestarts             int                `json:"max_restarts"`
+	RestartMaxPopulation    int                `json:"restart_max_population"`
+	NichingMethod           string             `json:"niching_method"`
+	NicheRadius             float64            `json:"niche_radius"`
+	NicheCapacity           int                `json:"niche_capacity"`
+	SharingAlpha            float64            `json:"sharing_alpha"`
+	MaxOptima               int                `json:"max_optima"`
+	MultiObjectiveMode      string             `json:"multi_objective_mode"`
+	ReferenceDivisions      int                `json:"reference_divisions"`
+	Scalarization           string             `json:"scalarization"`
+	PBITheta                float64            `json:"pbi_theta"`
+	NeighborhoodSize        int                `json:"neighborhood_size"`
+	NeighborhoodProbability float64            `json:"neighborhood_probability"`
+	MaxReplacements         int                `json:"max_replacements"`
+	UseSurrogate            bool               `json:"use_surrogate"`
+	SurrogateModel          string             `json:"surrogate_model"`
+	InfillCriterion         string             `json:"infill_criterion"`
+	SurrogateSamples        int                `json:"surrogate_samples"`
+	SurrogateFraction       float64            `json:"surrogate_fraction"`
+	LCBKappa                float64            `json:"lcb_kappa"`
+	LocalSearch             string             `json:"local_search"`
+	LocalSearchInterval     int                `json:"local_search_interval"`
+	LocalSearchEvals        int                `json:"local_search_evals"`
+	LocalSearchStep         float64            `json:"local_search_step"`
+	CMAPopulation           int                `json:"cma_population"`
+	CMASigma                float64            `json:"cma_sigma"`
+	AdaptiveParameters      bool               `json:"adaptive_parameters"`
+	AdaptationMemory        int                `json:"adaptation_memory"`
 }
 
 // Result holds the results of the optimization.
 type Result struct {
-	BestSolution   []float64
-	GlobalBest     Best
-	FuncEvalCount  int
-	IterationCount int
-	Seed           int64 // Random seed used for reproducibility
+	BestSolution     []float64
+	GlobalBest       Best
+	FuncEvalCount    int
+	IterationCount   int
+	Seed             int64                  // Random seed used for reproducibility
+	Restarts         []RestartEvent         // Restart events (see Config.RestartStrategy)
+	Optima           []Best                 // Distinct optima sorted by cost (see Config.NichingMethod)
+	SurrogateSkipped int                    // Candidates discarded by the surrogate without evaluation (see Config.UseSurrogate)
+	LocalSearchEvals int                    // Evaluations of the local search, included in FuncEvalCount (see Config.LocalSearch)
+	GradientEvals    int                    // Calls of Config.Gradient
+	Parameters       []MovementCoefficients // Mean adapted coefficients after every iteration (see Config.AdaptiveParameters)
 }
 
 // newMayfly creates an empty mayfly with allocated slices.
@@ -122,6 +181,7 @@ func (m *Mayfly) clone() *Mayfly {
 		Position: make([]float64, len(m.Position)),
 		Velocity: make([]float64, len(m.Velocity)),
 		Cost:     m.Cost,
+		Sigma:    m.Sigma,
 		Best: Best{
 			Position: make([]float64, len(m.Best.Position)),
 			Cost:     m.Best.Cost,
@@ -158,11 +218,3 @@ func sanitizeCost(cost float64) float64 {
 	}
 	return cost
 }
-
-// evaluateWithSanitization evaluates the objective function after sanitizing the position.
-// This ensures all heavy-tailed operators (Lévy, Cauchy) don't pass invalid values.
-func evaluateWithSanitization(objFunc ObjectiveFunction, position []float64,
-	lowerBound, upperBound float64, rng *rand.Rand) float64 {
-	sanitizeVec(position, lowerBound, upperBound, rng)
-	return sanitizeCost(objFunc(position))
-}
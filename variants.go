@@ -2,7 +2,9 @@ package mayfly
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"sync"
 )
 
 // AlgorithmVariant represents a specific variant of the Mayfly Algorithm.
@@ -76,6 +78,20 @@ const (
 	NarrowValley                  // Ill-conditioned
 )
 
+// VariantOptimizer is implemented by variants that supply their own
+// optimization behavior instead of the flag-driven Mayfly pipeline.
+// OptimizeVariant, VariantBuilder.Optimize and ComparisonRunner use it
+// when available.
+type VariantOptimizer interface {
+	Optimize(config *Config) (*Result, error)
+}
+
+// PipelineVariant is implemented by variants that run the Mayfly main loop
+// with a custom Pipeline (e.g. extra stages or operators).
+type PipelineVariant interface {
+	Pipeline(config *Config) *Pipeline
+}
+
 // variantRegistry holds all available algorithm variants.
 var variantRegistry = map[string]AlgorithmVariant{
 	"ma":      &StandardMAVariant{},
@@ -86,6 +102,112 @@ var variantRegistry = map[string]AlgorithmVariant{
 	"gsasma":  &GSASMAVariant{},
 	"mpma":    &MPMAVariant{},
 	"aoblmoa": &AOBLMOAVariant{},
+
+	// Baselines with their own optimizer
+	"cmaes":         &CMAESVariant{},
+	"ipop-cmaes":    &CMAESVariant{ipop: true},
+	"pso":           &PSOVariant{},
+	"de":            &DEVariant{},
+	"jade":          &DEVariant{jade: true},
+	"ga":            &GAVariant{},
+	"random-search": &RandomSearchVariant{},
+
+	// Standalone components of the hybrids
+	"sa":          &SAVariant{},
+	"golden-sine": &GoldenSineVariant{},
+	"aquila":      &AquilaVariant{},
+
+	// Officially supported hybrids (see hybrid.go)
+	"desma-olce":        &HybridVariant{components: []AlgorithmVariant{&DESMAVariant{}, &OLCEVariant{}}},
+	"desma-gsasma":      &HybridVariant{components: []AlgorithmVariant{&DESMAVariant{}, &GSASMAVariant{}}},
+	"olce-gsasma":       &HybridVariant{components: []AlgorithmVariant{&OLCEVariant{}, &GSASMAVariant{}}},
+	"desma-olce-gsasma": &HybridVariant{components: []AlgorithmVariant{&DESMAVariant{}, &OLCEVariant{}, &GSASMAVariant{}}},
+	"mpma-desma":        &HybridVariant{components: []AlgorithmVariant{&MPMAVariant{}, &DESMAVariant{}}},
+}
+
+// variantAliases are alternative registry names that ListVariants omits.
+var variantAliases = map[string]bool{
+	"olce-ma": true,
+}
+
+// builtinVariants records the registry names shipped with the package.
+// They cannot be replaced or unregistered.
+var builtinVariants = func() map[string]bool {
+	names := make(map[string]bool, len(variantRegistry))
+	for name := range variantRegistry {
+		names[name] = true
+	}
+
+	return names
+}()
+
+// variantRegistryMu guards variantRegistry against concurrent registration.
+var variantRegistryMu sync.RWMutex
+
+// normalizeVariantName converts a variant name to its registry key.
+func normalizeVariantName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "standard" {
+		name = "ma"
+	}
+
+	return name
+}
+
+// RegisterVariant adds a third-party variant to the registry so it can be
+// created with NewVariant and is included in ListVariants, GetAllVariants,
+// ComparisonRunner and AlgorithmSelector.
+//
+// Names are case-insensitive and must not contain "+" (reserved for hybrids).
+// Returns an error if the name is empty, already registered or the variant is nil.
+// Variants that implement VariantOptimizer or PipelineVariant are run with
+// their own optimization behavior by OptimizeVariant.
+func RegisterVariant(name string, variant AlgorithmVariant) error {
+	key := normalizeVariantName(name)
+
+	if key == "" {
+		return fmt.Errorf("variant name cannot be empty")
+	}
+
+	if strings.Contains(key, "+") {
+		return fmt.Errorf("variant name %q cannot contain '+' (reserved for hybrids)", name)
+	}
+
+	if variant == nil {
+		return fmt.Errorf("variant %q cannot be nil", name)
+	}
+
+	variantRegistryMu.Lock()
+	defer variantRegistryMu.Unlock()
+
+	if _, exists := variantRegistry[key]; exists {
+		return fmt.Errorf("variant %q is already registered", key)
+	}
+
+	variantRegistry[key] = variant
+
+	return nil
+}
+
+// UnregisterVariant removes a variant added with RegisterVariant.
+// Returns an error if the name is unknown or refers to a built-in variant.
+func UnregisterVariant(name string) error {
+	key := normalizeVariantName(name)
+
+	if builtinVariants[key] {
+		return fmt.Errorf("built-in variant %q cannot be unregistered", key)
+	}
+
+	variantRegistryMu.Lock()
+	defer variantRegistryMu.Unlock()
+
+	if _, exists := variantRegistry[key]; !exists {
+		return fmt.Errorf("variant %q is not registered", key)
+	}
+
+	delete(variantRegistry, key)
+
+	return nil
 }
 
 // NewVariant creates an algorithm variant by name.
@@ -99,38 +221,74 @@ var variantRegistry = map[string]AlgorithmVariant{
 //   - "gsasma" - Golden Sine with Simulated Annealing MA
 //   - "mpma" - Median Position-Based MA
 //   - "aoblmoa" - Aquila Optimizer-Based Learning Multi-Objective Algorithm
+//
+// Baselines:
+//   - "cmaes" - Covariance Matrix Adaptation Evolution Strategy
+//   - "ipop-cmaes" - CMA-ES with IPOP restarts
+//   - "pso" - Particle Swarm Optimization
+//   - "de" - Differential Evolution (rand/1/bin)
+//   - "jade" - Adaptive Differential Evolution (current-to-pbest/1 with archive)
+//   - "ga" - Real-coded Genetic Algorithm
+//   - "random-search" - Uniform random search
+//
+// Standalone components of the hybrids:
+//   - "sa" - Simulated Annealing (GSASMA component)
+//   - "golden-sine" - Golden Sine Algorithm (GSASMA component)
+//   - "aquila" - Aquila Optimizer (AOBLMOA component)
+//
+// Hybrids:
+//   - "desma-olce", "desma-gsasma", "olce-gsasma", "desma-olce-gsasma", "mpma-desma"
+//   - Any other supported combination joined with "+" (e.g. "eobbma+desma")
+//
+// Variants added with RegisterVariant are available under their registered name.
 func NewVariant(name string) AlgorithmVariant {
-	name = strings.ToLower(strings.TrimSpace(name))
-	if name == "standard" {
-		name = "ma"
+	name = normalizeVariantName(name)
+
+	variantRegistryMu.RLock()
+	variant, ok := variantRegistry[name]
+	variantRegistryMu.RUnlock()
+
+	if ok {
+		return variant
 	}
 
-	return variantRegistry[name]
+	if strings.Contains(name, "+") {
+		hybrid, err := NewHybridVariant(strings.Split(name, "+")...)
+		if err == nil {
+			return hybrid
+		}
+	}
+
+	return nil
 }
 
-// ListVariants returns a list of all available algorithm variant names.
+// ListVariants returns a list of all available algorithm variant names,
+// including registered third-party variants.
 func ListVariants() []string {
+	variantRegistryMu.RLock()
+	defer variantRegistryMu.RUnlock()
+
 	variants := make([]string, 0, len(variantRegistry))
-	seen := make(map[string]bool)
 
 	for name := range variantRegistry {
 		// Skip aliases (only include primary names)
-		if name == "olce-ma" || name == "standard" {
+		if variantAliases[name] {
 			continue
 		}
 
-		if !seen[name] {
-			variants = append(variants, name)
-			seen[name] = true
-		}
+		variants = append(variants, name)
 	}
 
 	return variants
 }
 
-// GetAllVariants returns all available algorithm variants.
+// GetAllVariants returns all available algorithm variants, including
+// registered third-party variants.
 func GetAllVariants() []AlgorithmVariant {
-	variants := make([]AlgorithmVariant, 0, 7)
+	variantRegistryMu.RLock()
+	defer variantRegistryMu.RUnlock()
+
+	variants := make([]AlgorithmVariant, 0, len(variantRegistry))
 	seen := make(map[AlgorithmVariant]bool)
 
 	for _, variant := range variantRegistry {
@@ -143,6 +301,28 @@ func GetAllVariants() []AlgorithmVariant {
 	return variants
 }
 
+// OptimizeVariant runs variant on config. Variants implementing
+// VariantOptimizer use their own optimize method, variants implementing
+// PipelineVariant run their custom pipeline, and all others run Optimize.
+func OptimizeVariant(variant AlgorithmVariant, config *Config) (*Result, error) {
+	if variant == nil {
+		return nil, fmt.Errorf("variant cannot be nil")
+	}
+
+	switch v := variant.(type) {
+	case VariantOptimizer:
+		return v.Optimize(config)
+	case PipelineVariant:
+		if config == nil {
+			return nil, fmt.Errorf("config cannot be nil")
+		}
+
+		return OptimizeWithPipeline(config, v.Pipeline(config))
+	default:
+		return Optimize(config)
+	}
+}
+
 // =============================================================================
 // Standard MA Variant
 // =============================================================================
@@ -560,6 +740,506 @@ func (v *AOBLMOAVariant) RecommendedFor() []string {
 	}
 }
 
+// =============================================================================
+// CMA-ES Variant
+// =============================================================================
+
+// CMAESVariant represents the CMA-ES baseline, optionally with IPOP restarts.
+// It supplies its own optimizer (see OptimizeCMAES).
+type CMAESVariant struct {
+	ipop bool
+}
+
+func (v *CMAESVariant) Name() string {
+	if v.ipop {
+		return "IPOP-CMA-ES"
+	}
+
+	return "CMA-ES"
+}
+
+func (v *CMAESVariant) FullName() string {
+	if v.ipop {
+		return "Covariance Matrix Adaptation Evolution Strategy with Increasing Population Restarts"
+	}
+
+	return "Covariance Matrix Adaptation Evolution Strategy"
+}
+
+func (v *CMAESVariant) Description() string {
+	if v.ipop {
+		return "CMA-ES that restarts with doubled population size on convergence. Robust on multimodal and ill-conditioned problems."
+	}
+
+	return "Learns the covariance of the search distribution. De-facto standard for ill-conditioned continuous problems."
+}
+
+func (v *CMAESVariant) GetConfig() *Config {
+	if v.ipop {
+		return NewIPOPCMAESConfig()
+	}
+
+	return NewCMAESConfig()
+}
+
+func (v *CMAESVariant) ApplicableTo(characteristics ProblemCharacteristics) float64 {
+	if characteristics.MultiObjective {
+		return 0.1 // Single-objective only
+	}
+
+	score := 0.5
+
+	// Covariance adaptation learns rotated, ill-conditioned valleys
+	if characteristics.Landscape == NarrowValley {
+		score += 0.4
+	}
+
+	switch characteristics.Modality {
+	case Unimodal:
+		score += 0.1
+	case Multimodal:
+		if v.ipop {
+			score += 0.1
+		}
+	case HighlyMultimodal:
+		if v.ipop {
+			score += 0.1 // Larger populations smooth out local optima
+		} else {
+			score -= 0.2
+		}
+	}
+
+	// O(n²) memory and O(n³) eigendecompositions
+	if characteristics.Dimensionality > 100 {
+		score -= 0.2
+	}
+
+	return math.Max(0, min(score, 1.0))
+}
+
+func (v *CMAESVariant) EstimatedOverhead() float64 {
+	return 1.0 // Same evaluation budget per iteration
+}
+
+func (v *CMAESVariant) RecommendedFor() []string {
+	if v.ipop {
+		return []string{
+			"Multimodal problems with global structure",
+			"Rastrigin, Griewank, Ackley",
+			"Baseline comparisons",
+		}
+	}
+
+	return []string{
+		"Ill-conditioned and non-separable problems",
+		"Rosenbrock, BentCigar, Discus",
+		"Baseline comparisons",
+	}
+}
+
+// Optimize runs CMA-ES.
+func (v *CMAESVariant) Optimize(config *Config) (*Result, error) {
+	return OptimizeCMAES(config)
+}
+
+// =============================================================================
+// PSO Variant
+// =============================================================================
+
+// PSOVariant represents the particle swarm optimization baseline.
+// It supplies its own optimizer (see OptimizePSO).
+type PSOVariant struct{}
+
+func (v *PSOVariant) Name() string {
+	return "PSO"
+}
+
+func (v *PSOVariant) FullName() string {
+	return "Particle Swarm Optimization"
+}
+
+func (v *PSOVariant) Description() string {
+	return "Global-best particle swarm with constriction coefficients. Classic baseline for continuous problems."
+}
+
+func (v *PSOVariant) GetConfig() *Config {
+	return NewDefaultConfig()
+}
+
+func (v *PSOVariant) ApplicableTo(characteristics ProblemCharacteristics) float64 {
+	if characteristics.MultiObjective {
+		return 0.1 // Single-objective only
+	}
+
+	score := 0.4
+
+	if characteristics.Modality == Unimodal {
+		score += 0.1
+	}
+
+	if characteristics.RequiresFastConvergence {
+		score += 0.1
+	}
+
+	return min(score, 1.0)
+}
+
+func (v *PSOVariant) EstimatedOverhead() float64 {
+	return 1.0 // Same evaluation budget per iteration
+}
+
+func (v *PSOVariant) RecommendedFor() []string {
+	return []string{
+		"Baseline comparisons",
+		"Smooth continuous problems",
+	}
+}
+
+// Optimize runs PSO.
+func (v *PSOVariant) Optimize(config *Config) (*Result, error) {
+	return OptimizePSO(config)
+}
+
+// =============================================================================
+// DE Variant
+// =============================================================================
+
+// DEVariant represents the differential evolution baseline, either
+// DE/rand/1/bin or JADE. It supplies its own optimizer (see OptimizeDE and
+// OptimizeJADE).
+type DEVariant struct {
+	jade bool
+}
+
+func (v *DEVariant) Name() string {
+	if v.jade {
+		return "JADE"
+	}
+
+	return "DE"
+}
+
+func (v *DEVariant) FullName() string {
+	if v.jade {
+		return "Adaptive Differential Evolution with Optional External Archive"
+	}
+
+	return "Differential Evolution (rand/1/bin)"
+}
+
+func (v *DEVariant) Description() string {
+	if v.jade {
+		return "DE/current-to-pbest/1 with adaptive scale factor and crossover rate. Strong modern DE baseline."
+	}
+
+	return "Classic differential evolution with fixed scale factor and crossover rate."
+}
+
+func (v *DEVariant) GetConfig() *Config {
+	return NewDefaultConfig()
+}
+
+func (v *DEVariant) ApplicableTo(characteristics ProblemCharacteristics) float64 {
+	if characteristics.MultiObjective {
+		return 0.1 // Single-objective only
+	}
+
+	score := 0.4
+	if v.jade {
+		score = 0.5
+	}
+
+	if characteristics.Modality == Multimodal || characteristics.Modality == HighlyMultimodal {
+		score += 0.1
+	}
+
+	if v.jade && characteristics.Landscape == NarrowValley {
+		score += 0.1 // current-to-pbest follows valleys
+	}
+
+	return min(score, 1.0)
+}
+
+func (v *DEVariant) EstimatedOverhead() float64 {
+	return 1.0 // Same evaluation budget per iteration
+}
+
+func (v *DEVariant) RecommendedFor() []string {
+	return []string{
+		"Baseline comparisons",
+		"Multimodal continuous problems",
+	}
+}
+
+// Optimize runs DE or JADE.
+func (v *DEVariant) Optimize(config *Config) (*Result, error) {
+	if v.jade {
+		return OptimizeJADE(config)
+	}
+
+	return OptimizeDE(config)
+}
+
+// =============================================================================
+// GA Variant
+// =============================================================================
+
+// GAVariant represents the real-coded genetic algorithm baseline.
+// It supplies its own optimizer (see OptimizeGA).
+type GAVariant struct{}
+
+func (v *GAVariant) Name() string {
+	return "GA"
+}
+
+func (v *GAVariant) FullName() string {
+	return "Real-Coded Genetic Algorithm"
+}
+
+func (v *GAVariant) Description() string {
+	return "Generational GA with tournament selection, SBX crossover, polynomial mutation and elitism."
+}
+
+func (v *GAVariant) GetConfig() *Config {
+	return NewDefaultConfig()
+}
+
+func (v *GAVariant) ApplicableTo(characteristics ProblemCharacteristics) float64 {
+	if characteristics.MultiObjective {
+		return 0.1 // Single-objective only
+	}
+
+	score := 0.3
+
+	if characteristics.Modality == HighlyMultimodal {
+		score += 0.1
+	}
+
+	return min(score, 1.0)
+}
+
+func (v *GAVariant) EstimatedOverhead() float64 {
+	return 1.0 // Same evaluation budget per iteration
+}
+
+func (v *GAVariant) RecommendedFor() []string {
+	return []string{
+		"Baseline comparisons",
+	}
+}
+
+// Optimize runs the GA.
+func (v *GAVariant) Optimize(config *Config) (*Result, error) {
+	return OptimizeGA(config)
+}
+
+// =============================================================================
+// Random Search Variant
+// =============================================================================
+
+// RandomSearchVariant represents uniform random search, a sanity baseline
+// that every optimizer should beat. It supplies its own optimizer (see
+// OptimizeRandomSearch).
+type RandomSearchVariant struct{}
+
+func (v *RandomSearchVariant) Name() string {
+	return "RandomSearch"
+}
+
+func (v *RandomSearchVariant) FullName() string {
+	return "Uniform Random Search"
+}
+
+func (v *RandomSearchVariant) Description() string {
+	return "Samples uniformly random positions. Sanity baseline for comparisons."
+}
+
+func (v *RandomSearchVariant) GetConfig() *Config {
+	return NewDefaultConfig()
+}
+
+func (v *RandomSearchVariant) ApplicableTo(characteristics ProblemCharacteristics) float64 {
+	return 0.05 // Never the recommended choice
+}
+
+func (v *RandomSearchVariant) EstimatedOverhead() float64 {
+	return 1.0 // Same evaluation budget per iteration
+}
+
+func (v *RandomSearchVariant) RecommendedFor() []string {
+	return []string{
+		"Sanity checks in comparisons",
+	}
+}
+
+// Optimize runs random search.
+func (v *RandomSearchVariant) Optimize(config *Config) (*Result, error) {
+	return OptimizeRandomSearch(config)
+}
+
+// =============================================================================
+// SA Variant
+// =============================================================================
+
+// SAVariant represents simulated annealing, the acceptance component of
+// GSASMA, on its own. It supplies its own optimizer (see OptimizeSA).
+type SAVariant struct{}
+
+func (v *SAVariant) Name() string {
+	return "SA"
+}
+
+func (v *SAVariant) FullName() string {
+	return "Simulated Annealing"
+}
+
+func (v *SAVariant) Description() string {
+	return "Single-solution search with Metropolis acceptance and a cooling schedule. GSASMA component for ablation studies."
+}
+
+func (v *SAVariant) GetConfig() *Config {
+	return NewDefaultConfig()
+}
+
+func (v *SAVariant) ApplicableTo(characteristics ProblemCharacteristics) float64 {
+	if characteristics.MultiObjective {
+		return 0.1 // Single-objective only
+	}
+
+	score := 0.3
+
+	if characteristics.Modality == Unimodal {
+		score += 0.1
+	}
+
+	return min(score, 1.0)
+}
+
+func (v *SAVariant) EstimatedOverhead() float64 {
+	return 1.0 // Same evaluation budget per iteration
+}
+
+func (v *SAVariant) RecommendedFor() []string {
+	return []string{
+		"Ablation studies of GSASMA",
+		"Baseline comparisons",
+	}
+}
+
+// Optimize runs simulated annealing.
+func (v *SAVariant) Optimize(config *Config) (*Result, error) {
+	return OptimizeSA(config)
+}
+
+// =============================================================================
+// Golden Sine Variant
+// =============================================================================
+
+// GoldenSineVariant represents the Golden Sine Algorithm, the movement
+// component of GSASMA, on its own. It supplies its own optimizer (see
+// OptimizeGoldenSine).
+type GoldenSineVariant struct{}
+
+func (v *GoldenSineVariant) Name() string {
+	return "Gold-SA"
+}
+
+func (v *GoldenSineVariant) FullName() string {
+	return "Golden Sine Algorithm"
+}
+
+func (v *GoldenSineVariant) Description() string {
+	return "Population moves towards the best solution with golden sine steps. GSASMA component for ablation studies."
+}
+
+func (v *GoldenSineVariant) GetConfig() *Config {
+	return NewDefaultConfig()
+}
+
+func (v *GoldenSineVariant) ApplicableTo(characteristics ProblemCharacteristics) float64 {
+	if characteristics.MultiObjective {
+		return 0.1 // Single-objective only
+	}
+
+	score := 0.3
+
+	if characteristics.RequiresFastConvergence {
+		score += 0.1
+	}
+
+	return min(score, 1.0)
+}
+
+func (v *GoldenSineVariant) EstimatedOverhead() float64 {
+	return 1.0 // Same evaluation budget per iteration
+}
+
+func (v *GoldenSineVariant) RecommendedFor() []string {
+	return []string{
+		"Ablation studies of GSASMA",
+		"Baseline comparisons",
+	}
+}
+
+// Optimize runs the Golden Sine Algorithm.
+func (v *GoldenSineVariant) Optimize(config *Config) (*Result, error) {
+	return OptimizeGoldenSine(config)
+}
+
+// =============================================================================
+// Aquila Variant
+// =============================================================================
+
+// AquilaVariant represents the Aquila Optimizer, the strategy component of
+// AOBLMOA, on its own. It supplies its own optimizer (see OptimizeAquila).
+type AquilaVariant struct{}
+
+func (v *AquilaVariant) Name() string {
+	return "AO"
+}
+
+func (v *AquilaVariant) FullName() string {
+	return "Aquila Optimizer"
+}
+
+func (v *AquilaVariant) Description() string {
+	return "Four hunting strategies from exploration to exploitation. AOBLMOA component for ablation studies."
+}
+
+func (v *AquilaVariant) GetConfig() *Config {
+	return NewDefaultConfig()
+}
+
+func (v *AquilaVariant) ApplicableTo(characteristics ProblemCharacteristics) float64 {
+	if characteristics.MultiObjective {
+		return 0.1 // Single-objective only
+	}
+
+	score := 0.3
+
+	if characteristics.Modality == Multimodal || characteristics.Modality == HighlyMultimodal {
+		score += 0.1
+	}
+
+	return min(score, 1.0)
+}
+
+func (v *AquilaVariant) EstimatedOverhead() float64 {
+	return 1.0 // Same evaluation budget per iteration
+}
+
+func (v *AquilaVariant) RecommendedFor() []string {
+	return []string{
+		"Ablation studies of AOBLMOA",
+		"Baseline comparisons",
+	}
+}
+
+// Optimize runs the Aquila Optimizer.
+func (v *AquilaVariant) Optimize(config *Config) (*Result, error) {
+	return OptimizeAquila(config)
+}
+
 // =============================================================================
 // Fluent Builder API
 // =============================================================================
@@ -656,14 +1336,15 @@ func (b *VariantBuilder) Build() (*Config, error) {
 	return b.config, nil
 }
 
-// Optimize is a convenience method that builds the config and runs optimization.
+// Optimize is a convenience method that builds the config and runs optimization
+// with the variant's own optimize behavior (see OptimizeVariant).
 func (b *VariantBuilder) Optimize() (*Result, error) {
 	config, err := b.Build()
 	if err != nil {
 		return nil, err
 	}
 
-	return Optimize(config)
+	return OptimizeVariant(b.variant, config)
 }
 
 // GetVariant returns the underlying variant.
@@ -51,9 +51,9 @@ func TestNewVariant(t *testing.T) {
 func TestListVariants(t *testing.T) {
 	variants := ListVariants()
 
-	// Should have exactly 7 variants (excluding aliases)
-	if len(variants) != 7 {
-		t.Errorf("Expected 7 variants, got %d", len(variants))
+	// Should have exactly 7 variants, 5 hybrids, 7 baselines and 3 standalone components (excluding aliases)
+	if len(variants) != 22 {
+		t.Errorf("Expected 22 variants, got %d", len(variants))
 	}
 
 	// Check for required variants
@@ -76,9 +76,9 @@ func TestListVariants(t *testing.T) {
 func TestGetAllVariants(t *testing.T) {
 	variants := GetAllVariants()
 
-	// Should have exactly 7 unique variants
-	if len(variants) != 7 {
-		t.Errorf("Expected 7 variants, got %d", len(variants))
+	// Should have exactly 7 unique variants, 5 hybrids, 7 baselines and 3 standalone components
+	if len(variants) != 22 {
+		t.Errorf("Expected 22 variants, got %d", len(variants))
 	}
 
 	// Each should have valid methods
@@ -264,3 +264,148 @@ func TestNewBuilderFromVariant(t *testing.T) {
 		t.Error("Expected UseOLCE to be true")
 	}
 }
+
+// customVariant is a third-party variant with its own optimize behavior.
+type customVariant struct {
+	StandardMAVariant
+	calls int
+}
+
+func (v *customVariant) Name() string { return "CUSTOM" }
+
+func (v *customVariant) ApplicableTo(characteristics ProblemCharacteristics) float64 {
+	return 1.0
+}
+
+func (v *customVariant) Optimize(config *Config) (*Result, error) {
+	v.calls++
+	return Optimize(config)
+}
+
+// pipelineVariant is a third-party variant that adds a stage to the pipeline.
+type pipelineVariant struct {
+	StandardMAVariant
+	recorder *recordingStage
+}
+
+func (v *pipelineVariant) Name() string { return "PIPELINE" }
+
+func (v *pipelineVariant) Pipeline(config *Config) *Pipeline {
+	return NewPipeline(config).AddStage(v.recorder)
+}
+
+func TestRegisterVariant(t *testing.T) {
+	custom := &customVariant{}
+	if err := RegisterVariant("Custom", custom); err != nil {
+		t.Fatalf("RegisterVariant failed: %v", err)
+	}
+	defer UnregisterVariant("custom")
+
+	if NewVariant("CUSTOM") != custom {
+		t.Error("NewVariant should return the registered variant")
+	}
+
+	found := false
+	for _, name := range ListVariants() {
+		found = found || name == "custom"
+	}
+
+	if !found {
+		t.Error("ListVariants should include the registered variant")
+	}
+
+	best := NewAlgorithmSelector().RecommendBest(ProblemCharacteristics{Dimensionality: 10})
+	if best.Variant != custom {
+		t.Errorf("Selector should consider registered variants, got %s", best.Variant.Name())
+	}
+
+	runner := NewComparisonRunner().WithVariantNames("custom").WithRuns(2).WithIterations(10)
+	runner.Compare("Sphere", Sphere, 5, -10, 10)
+
+	if custom.calls != 2 {
+		t.Errorf("ComparisonRunner should use the variant's Optimize, called %d times", custom.calls)
+	}
+}
+
+func TestRegisterVariantErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		variant AlgorithmVariant
+	}{
+		{"Empty name", " ", &customVariant{}},
+		{"Nil variant", "nil-variant", nil},
+		{"Built-in name", "desma", &customVariant{}},
+		{"Standard alias", "standard", &customVariant{}},
+		{"Hybrid syntax", "a+b", &customVariant{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterVariant(tt.key, tt.variant); err == nil {
+				t.Errorf("Expected error registering %q", tt.key)
+			}
+		})
+	}
+
+	if err := UnregisterVariant("ma"); err == nil {
+		t.Error("Expected error unregistering a built-in variant")
+	}
+
+	if err := UnregisterVariant("not-registered"); err == nil {
+		t.Error("Expected error unregistering an unknown variant")
+	}
+}
+
+func TestOptimizeVariantPipeline(t *testing.T) {
+	variant := &pipelineVariant{recorder: newRecordingStage()}
+
+	result, err := NewBuilderFromVariant(variant).
+		ForProblem(Sphere, 5, -10, 10).
+		WithIterations(15).
+		Optimize()
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if variant.recorder.calls["end"] != 15 {
+		t.Errorf("Custom pipeline stage ran %d times, want 15", variant.recorder.calls["end"])
+	}
+
+	if result.IterationCount != 15 {
+		t.Errorf("Unexpected iteration count %d", result.IterationCount)
+	}
+
+	if _, err := OptimizeVariant(nil, NewDefaultConfig()); err == nil {
+		t.Error("Expected error for nil variant")
+	}
+}
+
+func TestRegisterPreset(t *testing.T) {
+	if err := RegisterVariant("preset-variant", &customVariant{}); err != nil {
+		t.Fatalf("RegisterVariant failed: %v", err)
+	}
+	defer UnregisterVariant("preset-variant")
+
+	preset := ConfigPreset("in_house")
+	if err := RegisterPreset(preset, "preset-variant", ""); err != nil {
+		t.Fatalf("RegisterPreset failed: %v", err)
+	}
+	defer UnregisterPreset(preset)
+
+	if ListPresets()[preset] == "" {
+		t.Error("ListPresets should include the registered preset with a description")
+	}
+
+	if _, err := NewPresetConfig(preset); err != nil {
+		t.Errorf("NewPresetConfig failed for registered preset: %v", err)
+	}
+
+	if err := RegisterPreset(PresetUnimodal, "preset-variant", "x"); err == nil {
+		t.Error("Expected error replacing a built-in preset")
+	}
+
+	if err := RegisterPreset("other", "unknown-variant", "x"); err == nil {
+		t.Error("Expected error for unknown variant")
+	}
+}